@@ -0,0 +1,56 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// errNoToken - token is not set by any source
+var errNoToken = errors.New("no OAuth token: pass --token, set YWM_TOKEN or add \"token\" to config file")
+
+// config - settings of json config file
+type config struct {
+	Token   string `json:"token"`
+	BaseURL string `json:"base_url"`
+	Output  string `json:"output"`
+}
+
+// configFile returns path of config file and whether it was set explicitly
+func (a *app) configFile() (string, bool) {
+	if a.configPath != "" {
+		return a.configPath, true
+	}
+	if path := a.getenv("YWM_CONFIG"); path != "" {
+		return path, true
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", false
+	}
+	return filepath.Join(dir, "ywm", "config.json"), false
+}
+
+// loadConfig reads config file once, missing default config is empty
+func (a *app) loadConfig() (*config, error) {
+	if a.config != nil {
+		return a.config, nil
+	}
+	cfg := &config{}
+	path, explicit := a.configFile()
+	if path != "" {
+		data, err := os.ReadFile(path)
+		switch {
+		case err == nil:
+			if err := json.Unmarshal(data, cfg); err != nil {
+				return nil, fmt.Errorf("config %s: %w", path, err)
+			}
+		case !os.IsNotExist(err) || explicit:
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	a.config = cfg
+	return cfg, nil
+}
@@ -0,0 +1,433 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func init() {
+	register(&group{
+		name:    "diagnostics",
+		summary: "check site problems found by Webmaster",
+		commands: []*command{
+			{name: "check", args: "[host...] [--all] [--fail-on critical] [--ignore TYPE[@host]] [--junit file]", summary: "fail when problems of severity are present", run: diagnosticsCheck},
+		},
+	})
+}
+
+// finding statuses
+const (
+	findingFail    = "FAIL"
+	findingWarn    = "WARN"
+	findingIgnored = "IGNORED"
+)
+
+// host statuses, FAIL and WARN are taken from the worst finding
+const (
+	hostOK    = "OK"
+	hostError = "ERROR"
+)
+
+// diagnosticsError - present problems at or above --fail-on, exit code depends on the worst severity
+type diagnosticsError struct {
+	failOn   ywm.DiagnosticSeverity
+	worst    ywm.DiagnosticSeverity
+	problems int
+	hosts    int
+}
+
+func (e *diagnosticsError) Error() string {
+	return fmt.Sprintf("%d problems at or above %s on %d hosts, worst is %s", e.problems, e.failOn, e.hosts, e.worst)
+}
+
+// parseSeverity parses severity case insensitive, e.g. "critical" or "possible-problem"
+func parseSeverity(s string) (ywm.DiagnosticSeverity, error) {
+	severity := ywm.DiagnosticSeverity(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(s), "-", "_")))
+	if !severity.IsKnown() {
+		return "", usagef("unknown severity %q, use fatal, critical, possible-problem or recommendation", s)
+	}
+	return severity, nil
+}
+
+// ignoreRule - problem type ignored on all hosts or on one host
+type ignoreRule struct {
+	problemType ywm.DiagnosticProblemType
+	hostID      ywm.HostID
+}
+
+// parseIgnoreRule parses "TYPE" or "TYPE@host"
+func parseIgnoreRule(s string) (ignoreRule, error) {
+	problemType, host := s, ""
+	if i := strings.Index(s, "@"); i >= 0 {
+		problemType, host = s[:i], s[i+1:]
+	}
+	rule := ignoreRule{problemType: ywm.DiagnosticProblemType(strings.ToUpper(strings.TrimSpace(problemType)))}
+	if rule.problemType == "" {
+		return ignoreRule{}, usagef("invalid ignore rule %q, use TYPE or TYPE@host", s)
+	}
+	if host != "" {
+		hostID, err := parseHost(strings.TrimSpace(host))
+		if err != nil {
+			return ignoreRule{}, err
+		}
+		rule.hostID = hostID
+	}
+	return rule, nil
+}
+
+func (r ignoreRule) matches(hostID ywm.HostID, problemType ywm.DiagnosticProblemType) bool {
+	return r.problemType == problemType && (r.hostID == "" || r.hostID == hostID)
+}
+
+// listFlag - repeatable flag of comma separated values
+type listFlag []string
+
+func (f *listFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *listFlag) Set(value string) error {
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*f = append(*f, item)
+		}
+	}
+	return nil
+}
+
+// diagnosticFinding - present problem of host
+type diagnosticFinding struct {
+	Type            ywm.DiagnosticProblemType `json:"type"`
+	Severity        ywm.DiagnosticSeverity    `json:"severity"`
+	LastStateUpdate ywm.Timestamp             `json:"last_state_update"`
+	// Status - FAIL at or above --fail-on, WARN below it, IGNORED by ignore rules
+	Status      string `json:"status"`
+	Description string `json:"description"`
+	Remediation string `json:"remediation,omitempty"`
+	DocURL      string `json:"doc_url,omitempty"`
+}
+
+// hostDiagnostics - result of checking host
+type hostDiagnostics struct {
+	HostID ywm.HostID `json:"host_id"`
+	// Status - OK, WARN, FAIL or ERROR when diagnostics are not available
+	Status   string               `json:"status"`
+	Error    string               `json:"error,omitempty"`
+	Problems []*diagnosticFinding `json:"problems"`
+
+	// checked - all problem types reported by api, they are test cases of junit report
+	checked ywm.DiagnosticProblems
+}
+
+// diagnosticsReport - json and yaml output of diagnostics check
+type diagnosticsReport struct {
+	FailOn ywm.DiagnosticSeverity `json:"fail_on"`
+	Worst  ywm.DiagnosticSeverity `json:"worst,omitempty"`
+	Hosts  []*hostDiagnostics     `json:"hosts"`
+}
+
+// diagnosticsChecker - options of diagnostics check
+type diagnosticsChecker struct {
+	failOn      ywm.DiagnosticSeverity
+	minSeverity ywm.DiagnosticSeverity
+	ignore      []ignoreRule
+	lang        ywm.Language
+}
+
+// check classifies present problems of host
+func (c *diagnosticsChecker) check(hostID ywm.HostID, problems ywm.DiagnosticProblems) *hostDiagnostics {
+	result := &hostDiagnostics{HostID: hostID, Status: hostOK, Problems: []*diagnosticFinding{}, checked: problems}
+	for _, p := range problems {
+		info := p.Info()
+		s := info.Severity
+		// problems of unknown severity are reported, but never fail check
+		if !p.State.IsPresent() || (s.IsKnown() && !s.AtLeast(c.minSeverity) && !s.AtLeast(c.failOn)) {
+			continue
+		}
+		finding := &diagnosticFinding{
+			Type:            p.Type,
+			Severity:        s,
+			LastStateUpdate: p.LastStateUpdate,
+			Status:          findingWarn,
+			Description:     info.Description.Get(c.lang),
+			Remediation:     info.Remediation.Get(c.lang),
+			DocURL:          info.DocURL,
+		}
+		if finding.Description == "" {
+			finding.Description = p.Type.String()
+		}
+		switch {
+		case c.ignored(hostID, p.Type):
+			finding.Status = findingIgnored
+		case s.IsKnown() && s.AtLeast(c.failOn):
+			finding.Status = findingFail
+		}
+		result.Problems = append(result.Problems, finding)
+		if finding.Status == findingFail || (finding.Status == findingWarn && result.Status == hostOK) {
+			result.Status = finding.Status
+		}
+	}
+	sort.SliceStable(result.Problems, func(i, j int) bool {
+		return result.Problems[i].Severity.Level() > result.Problems[j].Severity.Level()
+	})
+	return result
+}
+
+func (c *diagnosticsChecker) ignored(hostID ywm.HostID, problemType ywm.DiagnosticProblemType) bool {
+	for _, rule := range c.ignore {
+		if rule.matches(hostID, problemType) {
+			return true
+		}
+	}
+	return false
+}
+
+func diagnosticsCheck(a *app, args []string) error {
+	var all bool
+	var failOn, minSeverity, ignoreFile, lang, junit string
+	var ignore listFlag
+	fs := a.flagSet("diagnostics check")
+	fs.BoolVar(&all, "all", false, "check all verified hosts")
+	fs.StringVar(&failOn, "fail-on", "critical", "fail on present problems of severity or worse: fatal, critical, possible-problem, recommendation")
+	fs.StringVar(&minSeverity, "min-severity", "recommendation", "report present problems of severity or worse")
+	fs.Var(&ignore, "ignore", "ignored problem TYPE or TYPE@host, repeatable and comma separated")
+	fs.StringVar(&ignoreFile, "ignore-file", "", "file with ignore rules one per line")
+	fs.StringVar(&lang, "lang", "en", "language of descriptions: en or ru")
+	fs.StringVar(&junit, "junit", "", "write junit xml report to file, - for stdout instead of usual output")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if all == (len(args) != 0) {
+		return usagef("give hosts or --all")
+	}
+	checker := &diagnosticsChecker{lang: ywm.Language(lang)}
+	if checker.lang != ywm.LanguageEN && checker.lang != ywm.LanguageRU {
+		return usagef("unknown language %q, use en or ru", lang)
+	}
+	if checker.failOn, err = parseSeverity(failOn); err != nil {
+		return err
+	}
+	if checker.minSeverity, err = parseSeverity(minSeverity); err != nil {
+		return err
+	}
+	if ignoreFile != "" {
+		in, err := a.openInput(ignoreFile)
+		if err != nil {
+			return err
+		}
+		lines, err := readList(in)
+		in.Close()
+		if err != nil {
+			return err
+		}
+		ignore = append(ignore, lines...)
+	}
+	for _, value := range ignore {
+		rule, err := parseIgnoreRule(value)
+		if err != nil {
+			return err
+		}
+		checker.ignore = append(checker.ignore, rule)
+	}
+	var hosts []ywm.HostID
+	for _, arg := range args {
+		hostID, err := parseHost(arg)
+		if err != nil {
+			return err
+		}
+		hosts = append(hosts, hostID)
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	if all {
+		list, err := client.Hosts.GetHosts()
+		if err != nil {
+			return err
+		}
+		for _, host := range list.Hosts {
+			// diagnostics of unverified hosts are not available
+			if host.Verified {
+				hosts = append(hosts, host.HostID)
+			}
+		}
+	}
+
+	report := &diagnosticsReport{FailOn: checker.failOn, Hosts: []*hostDiagnostics{}}
+	var firstErr error
+	failed := &diagnosticsError{failOn: checker.failOn}
+	for _, hostID := range hosts {
+		resp, err := client.Diagnostic.GetDiagnositcs(hostID)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			report.Hosts = append(report.Hosts, &hostDiagnostics{HostID: hostID, Status: hostError, Error: errorText(err), Problems: []*diagnosticFinding{}})
+			continue
+		}
+		result := checker.check(hostID, resp.Problems)
+		report.Hosts = append(report.Hosts, result)
+		for _, p := range result.Problems {
+			if p.Status == findingFail {
+				failed.problems++
+				if p.Severity.Level() > failed.worst.Level() {
+					failed.worst = p.Severity
+				}
+			}
+		}
+		if result.Status == findingFail {
+			failed.hosts++
+		}
+	}
+	report.Worst = failed.worst
+
+	if junit != "" {
+		if err := a.writeJUnit(junit, report); err != nil {
+			return err
+		}
+	}
+	if junit != "-" {
+		if err := a.render(report, func(w io.Writer) { diagnosticsTable(w, report) }); err != nil {
+			return err
+		}
+	}
+	// present problems decide exit code before errors of other hosts
+	if failed.problems != 0 {
+		return failed
+	}
+	return firstErr
+}
+
+func diagnosticsTable(w io.Writer, report *diagnosticsReport) {
+	row(w, "HOST", "STATUS", "SEVERITY", "PROBLEM", "SINCE", "DESCRIPTION")
+	for _, host := range report.Hosts {
+		if host.Status == hostError {
+			row(w, host.HostID, host.Status, "-", "-", "-", host.Error)
+			continue
+		}
+		if len(host.Problems) == 0 {
+			row(w, host.HostID, host.Status, "-", "-", "-", "-")
+		}
+		for _, p := range host.Problems {
+			row(w, host.HostID, p.Status, p.Severity, p.Type, formatTime(p.LastStateUpdate), p.Description)
+		}
+	}
+}
+
+// junit report types, subset of junit xml read by CI servers
+type junitSuites struct {
+	XMLName  xml.Name     `xml:"testsuites"`
+	Name     string       `xml:"name,attr"`
+	Tests    int          `xml:"tests,attr"`
+	Failures int          `xml:"failures,attr"`
+	Errors   int          `xml:"errors,attr"`
+	Skipped  int          `xml:"skipped,attr"`
+	Suites   []junitSuite `xml:"testsuite"`
+}
+
+type junitSuite struct {
+	Name     string      `xml:"name,attr"`
+	Tests    int         `xml:"tests,attr"`
+	Failures int         `xml:"failures,attr"`
+	Errors   int         `xml:"errors,attr"`
+	Skipped  int         `xml:"skipped,attr"`
+	Cases    []junitCase `xml:"testcase"`
+}
+
+type junitCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr,omitempty"`
+	Text    string `xml:",chardata"`
+}
+
+// junitReport returns test suite per host with test case per problem type reported by api
+func junitReport(report *diagnosticsReport) *junitSuites {
+	suites := &junitSuites{Name: "ywm diagnostics check"}
+	for _, host := range report.Hosts {
+		suite := junitSuite{Name: string(host.HostID)}
+		if host.Status == hostError {
+			suite.Cases = append(suite.Cases, junitCase{
+				Name:      "diagnostics",
+				Classname: string(host.HostID),
+				Error:     &junitMessage{Message: host.Error},
+			})
+			suite.Errors++
+		}
+		findings := make(map[ywm.DiagnosticProblemType]*diagnosticFinding, len(host.Problems))
+		for _, p := range host.Problems {
+			findings[p.Type] = p
+		}
+		for _, p := range host.checked {
+			c := junitCase{Name: p.Type.String(), Classname: string(host.HostID)}
+			if f, ok := findings[p.Type]; ok {
+				text := fmt.Sprintf("%s: %s", f.Severity, f.Description)
+				switch f.Status {
+				case findingFail:
+					details := text + "\n" + f.Remediation + "\n" + f.DocURL
+					c.Failure = &junitMessage{Message: f.Description, Type: string(f.Severity), Text: strings.TrimSpace(details)}
+					suite.Failures++
+				case findingIgnored:
+					c.Skipped = &junitMessage{Message: "ignored: " + text}
+					suite.Skipped++
+				default:
+					c.SystemOut = text
+				}
+			}
+			suite.Cases = append(suite.Cases, c)
+		}
+		suite.Tests = len(suite.Cases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Errors += suite.Errors
+		suites.Skipped += suite.Skipped
+		suites.Suites = append(suites.Suites, suite)
+	}
+	return suites
+}
+
+// writeJUnit writes junit xml report to file or stdout for "-"
+func (a *app) writeJUnit(name string, report *diagnosticsReport) error {
+	w := a.stdout
+	if name != "-" {
+		f, err := os.Create(name)
+		if err != nil {
+			return err
+		}
+		if err := writeJUnitXML(f, report); err != nil {
+			f.Close()
+			return err
+		}
+		return f.Close()
+	}
+	return writeJUnitXML(w, report)
+}
+
+func writeJUnitXML(w io.Writer, report *diagnosticsReport) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(junitReport(report)); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"bytes"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/webmastertest"
+)
+
+const (
+	checkHost ywm.HostID = "https:example.com:443"
+	otherHost ywm.HostID = "https:shop.example.com:443"
+)
+
+// problem returns present problem of type unknown to catalog, so severity is taken from api
+func problem(problemType string, severity ywm.DiagnosticSeverity) ywm.DiagnosticProblem {
+	return ywm.DiagnosticProblem{Type: ywm.DiagnosticProblemType(problemType), Severity: severity, State: ywm.DiagnosticStatePresent}
+}
+
+func TestParseIgnoreRule(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    ignoreRule
+		wantErr bool
+	}{
+		{value: "no_sitemaps", want: ignoreRule{problemType: "NO_SITEMAPS"}},
+		{value: " NO_SITEMAPS@example.com ", want: ignoreRule{problemType: "NO_SITEMAPS", hostID: checkHost}},
+		{value: "NO_SITEMAPS@https://shop.example.com", want: ignoreRule{problemType: "NO_SITEMAPS", hostID: otherHost}},
+		{value: "@example.com", wantErr: true},
+		{value: "", wantErr: true},
+		{value: "NO_SITEMAPS@exa mple.com", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseIgnoreRule(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIgnoreRuleMatches(t *testing.T) {
+	anyHost := ignoreRule{problemType: "NO_SITEMAPS"}
+	oneHost := ignoreRule{problemType: "NO_SITEMAPS", hostID: checkHost}
+	tests := []struct {
+		name        string
+		rule        ignoreRule
+		hostID      ywm.HostID
+		problemType ywm.DiagnosticProblemType
+		want        bool
+	}{
+		{name: "any host", rule: anyHost, hostID: otherHost, problemType: "NO_SITEMAPS", want: true},
+		{name: "same host", rule: oneHost, hostID: checkHost, problemType: "NO_SITEMAPS", want: true},
+		{name: "other host", rule: oneHost, hostID: otherHost, problemType: "NO_SITEMAPS"},
+		{name: "other type", rule: anyHost, hostID: checkHost, problemType: "NO_ROBOTS_TXT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rule.matches(tt.hostID, tt.problemType); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsCheckerCheck(t *testing.T) {
+	problems := ywm.DiagnosticProblems{
+		problem("TEST_FATAL", ywm.DiagnosticSeverityFatal),
+		problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical),
+		problem("TEST_POSSIBLE", ywm.DiagnosticSeverityPossibleProblem),
+		problem("TEST_RECOMMENDATION", ywm.DiagnosticSeverityRecommendation),
+		problem("TEST_UNKNOWN", "SOMETHING_NEW"),
+		{Type: "TEST_ABSENT", Severity: ywm.DiagnosticSeverityFatal, State: ywm.DiagnosticStateAbsent},
+	}
+	tests := []struct {
+		name       string
+		checker    diagnosticsChecker
+		problems   ywm.DiagnosticProblems
+		wantStatus string
+		// wantFindings - status of every reported problem type in report order
+		wantFindings map[ywm.DiagnosticProblemType]string
+	}{
+		{
+			name:       "fail on critical",
+			checker:    diagnosticsChecker{failOn: ywm.DiagnosticSeverityCritical, minSeverity: ywm.DiagnosticSeverityRecommendation},
+			problems:   problems,
+			wantStatus: findingFail,
+			wantFindings: map[ywm.DiagnosticProblemType]string{
+				"TEST_FATAL":          findingFail,
+				"TEST_CRITICAL":       findingFail,
+				"TEST_POSSIBLE":       findingWarn,
+				"TEST_RECOMMENDATION": findingWarn,
+				"TEST_UNKNOWN":        findingWarn,
+			},
+		},
+		{
+			name:       "min severity hides minor problems",
+			checker:    diagnosticsChecker{failOn: ywm.DiagnosticSeverityFatal, minSeverity: ywm.DiagnosticSeverityCritical},
+			problems:   problems,
+			wantStatus: findingFail,
+			wantFindings: map[ywm.DiagnosticProblemType]string{
+				"TEST_FATAL":    findingFail,
+				"TEST_CRITICAL": findingWarn,
+				"TEST_UNKNOWN":  findingWarn,
+			},
+		},
+		{
+			name:       "fail on is reported above min severity",
+			checker:    diagnosticsChecker{failOn: ywm.DiagnosticSeverityPossibleProblem, minSeverity: ywm.DiagnosticSeverityFatal},
+			problems:   problems,
+			wantStatus: findingFail,
+			wantFindings: map[ywm.DiagnosticProblemType]string{
+				"TEST_FATAL":    findingFail,
+				"TEST_CRITICAL": findingFail,
+				"TEST_POSSIBLE": findingFail,
+				"TEST_UNKNOWN":  findingWarn,
+			},
+		},
+		{
+			name:       "unknown severity never fails",
+			checker:    diagnosticsChecker{failOn: ywm.DiagnosticSeverityRecommendation, minSeverity: ywm.DiagnosticSeverityRecommendation},
+			problems:   ywm.DiagnosticProblems{problem("TEST_UNKNOWN", "SOMETHING_NEW")},
+			wantStatus: findingWarn,
+			wantFindings: map[ywm.DiagnosticProblemType]string{
+				"TEST_UNKNOWN": findingWarn,
+			},
+		},
+		{
+			name: "ignored",
+			checker: diagnosticsChecker{
+				failOn:      ywm.DiagnosticSeverityCritical,
+				minSeverity: ywm.DiagnosticSeverityCritical,
+				ignore:      []ignoreRule{{problemType: "TEST_FATAL", hostID: checkHost}, {problemType: "TEST_CRITICAL"}, {problemType: "TEST_UNKNOWN", hostID: otherHost}},
+			},
+			problems:   problems,
+			wantStatus: findingWarn,
+			wantFindings: map[ywm.DiagnosticProblemType]string{
+				"TEST_FATAL":    findingIgnored,
+				"TEST_CRITICAL": findingIgnored,
+				"TEST_UNKNOWN":  findingWarn,
+			},
+		},
+		{
+			name:         "only ignored",
+			checker:      diagnosticsChecker{failOn: ywm.DiagnosticSeverityCritical, ignore: []ignoreRule{{problemType: "TEST_FATAL"}}},
+			problems:     ywm.DiagnosticProblems{problem("TEST_FATAL", ywm.DiagnosticSeverityFatal)},
+			wantStatus:   hostOK,
+			wantFindings: map[ywm.DiagnosticProblemType]string{"TEST_FATAL": findingIgnored},
+		},
+		{
+			name:         "absent problems",
+			checker:      diagnosticsChecker{failOn: ywm.DiagnosticSeverityRecommendation},
+			problems:     problems[5:],
+			wantStatus:   hostOK,
+			wantFindings: map[ywm.DiagnosticProblemType]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.checker.check(checkHost, tt.problems)
+			if got.Status != tt.wantStatus {
+				t.Errorf("host status %s, want %s", got.Status, tt.wantStatus)
+			}
+			findings := make(map[ywm.DiagnosticProblemType]string)
+			for i, p := range got.Problems {
+				findings[p.Type] = p.Status
+				if i > 0 && p.Severity.Level() > got.Problems[i-1].Severity.Level() {
+					t.Errorf("problems are not sorted by severity: %s after %s", p.Severity, got.Problems[i-1].Severity)
+				}
+			}
+			if !reflect.DeepEqual(findings, tt.wantFindings) {
+				t.Errorf("got %v, want %v", findings, tt.wantFindings)
+			}
+		})
+	}
+}
+
+func TestJUnitReport(t *testing.T) {
+	checker := &diagnosticsChecker{
+		failOn:      ywm.DiagnosticSeverityCritical,
+		minSeverity: ywm.DiagnosticSeverityPossibleProblem,
+		ignore:      []ignoreRule{{problemType: "TEST_IGNORED"}},
+	}
+	report := &diagnosticsReport{FailOn: checker.failOn, Hosts: []*hostDiagnostics{
+		checker.check(checkHost, ywm.DiagnosticProblems{
+			problem("TEST_FATAL", ywm.DiagnosticSeverityFatal),
+			problem("TEST_POSSIBLE", ywm.DiagnosticSeverityPossibleProblem),
+			problem("TEST_IGNORED", ywm.DiagnosticSeverityCritical),
+			problem("TEST_RECOMMENDATION", ywm.DiagnosticSeverityRecommendation),
+			{Type: "TEST_ABSENT", Severity: ywm.DiagnosticSeverityFatal, State: ywm.DiagnosticStateAbsent},
+		}),
+		{HostID: otherHost, Status: hostError, Error: "host not found", Problems: []*diagnosticFinding{}},
+	}}
+
+	suites := junitReport(report)
+	counts := []int{suites.Tests, suites.Failures, suites.Errors, suites.Skipped}
+	// every problem type reported by api is test case, absent and hidden problems pass
+	if want := []int{6, 1, 1, 1}; !reflect.DeepEqual(counts, want) {
+		t.Errorf("tests, failures, errors, skipped = %v, want %v", counts, want)
+	}
+
+	var buf bytes.Buffer
+	if err := writeJUnitXML(&buf, report); err != nil {
+		t.Fatal(err)
+	}
+	golden, err := os.ReadFile(filepath.Join("testdata", "diagnostics.junit.xml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != string(golden) {
+		t.Errorf("got:\n%s\nwant:\n%s", buf.String(), golden)
+	}
+}
+
+func TestDiagnosticsCheckExitCodes(t *testing.T) {
+	tests := []struct {
+		name     string
+		problems ywm.DiagnosticProblems
+		args     []string
+		want     int
+	}{
+		{name: "no problems", want: exitOK},
+		{name: "critical", problems: ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical)}, want: exitCritical},
+		{name: "worst severity", problems: ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical), problem("TEST_FATAL", ywm.DiagnosticSeverityFatal)}, want: exitFatal},
+		{name: "below fail on", problems: ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical)}, args: []string{"--fail-on", "fatal"}, want: exitOK},
+		{name: "possible problem", problems: ywm.DiagnosticProblems{problem("TEST_POSSIBLE", ywm.DiagnosticSeverityPossibleProblem)}, args: []string{"--fail-on", "possible-problem"}, want: exitPossibleProblem},
+		{name: "ignored", problems: ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical)}, args: []string{"--ignore", "test_critical@example.com"}, want: exitOK},
+		{name: "ignored on other host", problems: ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical)}, args: []string{"--ignore", "TEST_CRITICAL@shop.example.com"}, want: exitCritical},
+		{name: "unknown host", args: []string{"missing.example.com"}, want: exitNotFound},
+		{name: "problems before host errors", problems: ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical)}, args: []string{"missing.example.com"}, want: exitCritical},
+		{name: "unknown severity", args: []string{"--fail-on", "bad"}, want: exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := webmastertest.NewServer()
+			defer s.Close()
+			hostID, _ := s.AddHost("https://example.com")
+			s.UpdateHost(hostID, func(h *webmastertest.HostData) { h.Diagnostics = tt.problems })
+			args := []string{"--token=" + s.Token, "diagnostics", "check"}
+			if tt.name != "unknown host" {
+				args = append(args, "example.com")
+			}
+			code, _, stderr := runCLI(t, s, append(args, tt.args...)...)
+			if code != tt.want {
+				t.Errorf("exit code %d, want %d, stderr: %s", code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestDiagnosticsCheckJUnitStdout(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	s.UpdateHost(hostID, func(h *webmastertest.HostData) {
+		h.Diagnostics = ywm.DiagnosticProblems{problem("TEST_CRITICAL", ywm.DiagnosticSeverityCritical)}
+	})
+	code, stdout, stderr := runCLI(t, s, "--token="+s.Token, "diagnostics", "check", "example.com", "--junit", "-")
+	if code != exitCritical {
+		t.Fatalf("exit code %d, want %d, stderr: %s", code, exitCritical, stderr)
+	}
+	// stdout is only junit report, table is not written
+	var suites junitSuites
+	if err := xml.Unmarshal([]byte(stdout), &suites); err != nil {
+		t.Fatalf("decode %q: %v", stdout, err)
+	}
+	if strings.Contains(stdout, "HOST") || suites.Failures != 1 {
+		t.Errorf("unexpected stdout:\n%s", stdout)
+	}
+
+	junit := filepath.Join(t.TempDir(), "report.xml")
+	code, stdout, _ = runCLI(t, s, "--token="+s.Token, "diagnostics", "check", "example.com", "--junit", junit)
+	if code != exitCritical || !strings.Contains(stdout, "HOST") {
+		t.Errorf("exit code %d, table expected on stdout:\n%s", code, stdout)
+	}
+	if data, err := os.ReadFile(junit); err != nil || !strings.Contains(string(data), "<testsuites") {
+		t.Errorf("junit file %q, error %v", data, err)
+	}
+}
@@ -0,0 +1,143 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// exit codes by class of failure
+const (
+	exitOK        = 0
+	exitError     = 1
+	exitUsage     = 2
+	exitAuth      = 3
+	exitNotFound  = 4
+	exitConflict  = 5
+	exitInvalid   = 6
+	exitRateLimit = 7
+	exitServer    = 8
+
+	// worst severity of problems failing diagnostics check
+	exitRecommendation  = 11
+	exitPossibleProblem = 12
+	exitCritical        = 13
+	exitFatal           = 14
+)
+
+// apiErrorBody - error body of api response
+type apiErrorBody struct {
+	ErrorCode    string `json:"error_code"`
+	ErrorMessage string `json:"error_message"`
+}
+
+// apiErrorCode returns error_code of api error or empty string
+func apiErrorCode(err error) string {
+	var apiErr *ywm.YandexWebmasterError
+	if !errors.As(err, &apiErr) {
+		return ""
+	}
+	var body apiErrorBody
+	if json.Unmarshal([]byte(apiErr.ErrorData), &body) != nil {
+		return ""
+	}
+	return body.ErrorCode
+}
+
+// notFoundError - object looked up by command does not exist
+type notFoundError struct {
+	message string
+}
+
+func (e *notFoundError) Error() string {
+	return e.message
+}
+
+// exitCode returns exit code of err
+func exitCode(err error) int {
+	var uerr *usageError
+	if errors.As(err, &uerr) || errors.Is(err, ywm.ErrInvalidHostID) {
+		return exitUsage
+	}
+	if errors.Is(err, errNoToken) {
+		return exitAuth
+	}
+	var qerr *quotaError
+	if errors.As(err, &qerr) {
+		return exitRateLimit
+	}
+	var derr *diagnosticsError
+	if errors.As(err, &derr) {
+		switch derr.worst {
+		case ywm.DiagnosticSeverityFatal:
+			return exitFatal
+		case ywm.DiagnosticSeverityCritical:
+			return exitCritical
+		case ywm.DiagnosticSeverityPossibleProblem:
+			return exitPossibleProblem
+		}
+		return exitRecommendation
+	}
+	var nerr *notFoundError
+	if errors.As(err, &nerr) {
+		return exitNotFound
+	}
+	var verrs ywm.ValidationErrors
+	if errors.As(err, &verrs) {
+		return exitInvalid
+	}
+	var apiErr *ywm.YandexWebmasterError
+	if !errors.As(err, &apiErr) {
+		return exitError
+	}
+	switch code := apiErr.HTTPCode; {
+	case code == http.StatusUnauthorized || code == http.StatusForbidden:
+		return exitAuth
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return exitNotFound
+	case code == http.StatusConflict:
+		return exitConflict
+	case code == http.StatusBadRequest || code == http.StatusUnprocessableEntity || code == http.StatusRequestEntityTooLarge:
+		return exitInvalid
+	case code == http.StatusTooManyRequests:
+		return exitRateLimit
+	case code >= 500:
+		return exitServer
+	}
+	return exitError
+}
+
+// errorText returns readable message of err, api errors are shown with their error code
+func errorText(err error) string {
+	var apiErr *ywm.YandexWebmasterError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+	var body apiErrorBody
+	if json.Unmarshal([]byte(apiErr.ErrorData), &body) == nil && body.ErrorCode != "" {
+		if body.ErrorMessage != "" {
+			return fmt.Sprintf("%s: %s (http %d)", body.ErrorCode, body.ErrorMessage, apiErr.HTTPCode)
+		}
+		return fmt.Sprintf("%s (http %d)", body.ErrorCode, apiErr.HTTPCode)
+	}
+	if apiErr.ErrorMessage != "" && apiErr.ErrorData == "" {
+		// network error, there is no response
+		return apiErr.ErrorMessage
+	}
+	if apiErr.ErrorMessage != "" {
+		return fmt.Sprintf("%s (http %d)", apiErr.ErrorMessage, apiErr.HTTPCode)
+	}
+	return fmt.Sprintf("http %d %s", apiErr.HTTPCode, http.StatusText(apiErr.HTTPCode))
+}
+
+// fail prints err and returns its exit code
+func (a *app) fail(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	fmt.Fprintf(a.stderr, "ywm: %s\n", errorText(err))
+	return exitCode(err)
+}
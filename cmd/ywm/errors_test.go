@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/webmastertest"
+)
+
+func TestExitCode(t *testing.T) {
+	apiError := func(status int) error {
+		return fmt.Errorf("get host: %w", &ywm.YandexWebmasterError{HTTPCode: status, Endpoint: "user/1/hosts"})
+	}
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "usage", err: usagef("unknown flag"), want: exitUsage},
+		{name: "invalid host id", err: fmt.Errorf("host: %w", ywm.ErrInvalidHostID), want: exitUsage},
+		{name: "no token", err: errNoToken, want: exitAuth},
+		{name: "quota", err: &quotaError{urls: 3, remainder: 1}, want: exitRateLimit},
+		{name: "fatal problems", err: &diagnosticsError{worst: ywm.DiagnosticSeverityFatal}, want: exitFatal},
+		{name: "critical problems", err: &diagnosticsError{worst: ywm.DiagnosticSeverityCritical}, want: exitCritical},
+		{name: "possible problems", err: &diagnosticsError{worst: ywm.DiagnosticSeverityPossibleProblem}, want: exitPossibleProblem},
+		{name: "recommendations", err: &diagnosticsError{worst: ywm.DiagnosticSeverityRecommendation}, want: exitRecommendation},
+		{name: "not found", err: &notFoundError{message: "no task"}, want: exitNotFound},
+		{name: "validation", err: ywm.ValidationErrors{{Field: "limit", Reason: "must be 0 or 1..500"}}, want: exitInvalid},
+		{name: "unauthorized", err: apiError(http.StatusUnauthorized), want: exitAuth},
+		{name: "forbidden", err: apiError(http.StatusForbidden), want: exitAuth},
+		{name: "api not found", err: apiError(http.StatusNotFound), want: exitNotFound},
+		{name: "gone", err: apiError(http.StatusGone), want: exitNotFound},
+		{name: "conflict", err: apiError(http.StatusConflict), want: exitConflict},
+		{name: "bad request", err: apiError(http.StatusBadRequest), want: exitInvalid},
+		{name: "entity too large", err: apiError(http.StatusRequestEntityTooLarge), want: exitInvalid},
+		{name: "too many requests", err: apiError(http.StatusTooManyRequests), want: exitRateLimit},
+		{name: "server error", err: apiError(http.StatusBadGateway), want: exitServer},
+		{name: "network error", err: apiError(http.StatusServiceUnavailable), want: exitServer},
+		{name: "other status", err: apiError(http.StatusTeapot), want: exitError},
+		{name: "other error", err: errors.New("disk full"), want: exitError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := exitCode(tt.err); got != tt.want {
+				t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+// runCLI runs command line against fake server and returns exit code, stdout and stderr
+func runCLI(t *testing.T, s *webmastertest.Server, args ...string) (int, string, string) {
+	t.Helper()
+	return runCLIInput(t, s, "", args...)
+}
+
+// runCLIInput runs command line with stdin against fake server
+func runCLIInput(t *testing.T, s *webmastertest.Server, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	configPath := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(configPath, []byte(`{}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	var stdout, stderr bytes.Buffer
+	a := &app{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr, getenv: func(string) string { return "" }}
+	global := []string{"--config", configPath, "--base-url", s.URL}
+	code := a.run(append(global, args...))
+	return code, stdout.String(), stderr.String()
+}
+
+func TestRunExitCodes(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	token := "--token=" + s.Token
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "help", args: []string{"help"}, want: exitOK},
+		{name: "unknown command", args: []string{"nope"}, want: exitUsage},
+		{name: "missing argument", args: []string{token, "hosts", "get"}, want: exitUsage},
+		{name: "invalid host", args: []string{token, "hosts", "get", "https:bad host:443"}, want: exitUsage},
+		{name: "no token", args: []string{"hosts", "list"}, want: exitAuth},
+		{name: "wrong token", args: []string{"--token=wrong", "hosts", "list"}, want: exitAuth},
+		{name: "get host", args: []string{token, "hosts", "get", string(hostID)}, want: exitOK},
+		{name: "unknown host", args: []string{token, "hosts", "get", "missing.com"}, want: exitNotFound},
+		{name: "existing host", args: []string{token, "hosts", "add", "example.com"}, want: exitConflict},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, s, tt.args...)
+			if code != tt.want {
+				t.Errorf("run(%v) = %d, want %d, stderr: %s", tt.args, code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestHostsAddSendsHostURL(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want string
+	}{
+		{arg: "example.com", want: "https://example.com/"},
+		{arg: "http:Example.com:8080", want: "http://example.com:8080/"},
+		{arg: "https://пример.рф/some/page", want: "https://xn--e1afmkfd.xn--p1ai/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			s := webmastertest.NewServer()
+			defer s.Close()
+			code, _, stderr := runCLI(t, s, "--token="+s.Token, "hosts", "add", tt.arg)
+			if code != exitOK {
+				t.Fatalf("exit code %d, stderr: %s", code, stderr)
+			}
+			var body struct {
+				HostURL string `json:"host_url"`
+			}
+			for _, req := range s.Requests() {
+				if req.Method == http.MethodPost {
+					json.Unmarshal(req.Body, &body)
+				}
+			}
+			if body.HostURL != tt.want {
+				t.Errorf("host_url = %q, want %q", body.HostURL, tt.want)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, name string, data string) {
+	t.Helper()
+	if err := os.WriteFile(name, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMutationsRejectFormatBeforeAPICall(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "hosts add csv", args: []string{"hosts", "add", "new.example.com", "-o", "csv"}},
+		{name: "hosts add jsonl", args: []string{"hosts", "add", "new.example.com", "-o", "jsonl"}},
+		{name: "hosts delete csv", args: []string{"hosts", "delete", "example.com", "-o", "csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := webmastertest.NewServer()
+			defer s.Close()
+			s.AddHost("https://example.com")
+			code, _, stderr := runCLI(t, s, append([]string{"--token=" + s.Token}, tt.args...)...)
+			if code != exitUsage || !strings.Contains(stderr, "not supported") {
+				t.Errorf("exit code %d, want %d, stderr: %s", code, exitUsage, stderr)
+			}
+			if requests := s.Requests(); len(requests) != 0 {
+				t.Errorf("api is called: %+v", requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func init() {
+	register(&group{
+		name:    "hosts",
+		summary: "list, add and delete sites",
+		commands: []*command{
+			{name: "list", summary: "list added sites", run: hostsList},
+			{name: "get", args: "<host>", summary: "show site", run: hostsGet},
+			{name: "add", args: "<url> [--dry-run]", summary: "add site", run: hostsAdd},
+			{name: "delete", args: "<host> [--dry-run]", summary: "delete site", run: hostsDelete},
+		},
+	})
+}
+
+// mutation - result of command changing state, json and yaml output of mutations
+type mutation struct {
+	Action    string     `json:"action"`
+	HostID    ywm.HostID `json:"host_id"`
+	SitemapID string     `json:"sitemap_id,omitempty"`
+	URL       string     `json:"url,omitempty"`
+	DryRun    bool       `json:"dry_run"`
+	// Result - api response of mutation
+	Result interface{} `json:"result,omitempty"`
+	// Note - why nothing is done
+	Note string `json:"note,omitempty"`
+}
+
+// target returns changed object of mutation
+func (m *mutation) target() string {
+	switch {
+	case m.URL != "" && m.SitemapID != "":
+		return fmt.Sprintf("%s (%s)", m.URL, m.SitemapID)
+	case m.URL != "":
+		return m.URL
+	case m.SitemapID != "":
+		return m.SitemapID
+	}
+	return string(m.HostID)
+}
+
+func hostsList(a *app, args []string) error {
+	if _, err := commandArgs(a, "hosts list", args, 0, nil); err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	hosts, err := client.Hosts.GetHosts()
+	if err != nil {
+		return err
+	}
+	return a.render(hosts, func(w io.Writer) {
+		row(w, "HOST ID", "URL", "VERIFIED", "DATA", "NAME")
+		for _, host := range hosts.Hosts {
+			row(w, host.HostID, host.UnicodeHostURL, yesNo(host.Verified), host.HostDataStatus, host.HostDisplayName)
+		}
+	})
+}
+
+// commandArgs parses flags of command registered by flags and checks number of positional arguments
+func commandArgs(a *app, name string, args []string, want int, flags func(fs *flag.FlagSet)) ([]string, error) {
+	fs := a.flagSet(name)
+	if flags != nil {
+		flags(fs)
+	}
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) != want {
+		return nil, usagef("expected %d arguments, got %d", want, len(args))
+	}
+	return args, nil
+}
+
+// hostArg parses the only argument of command as host
+func hostArg(a *app, name string, args []string, flags func(fs *flag.FlagSet)) (ywm.HostID, error) {
+	args, err := commandArgs(a, name, args, 1, flags)
+	if err != nil {
+		return "", err
+	}
+	return parseHost(args[0])
+}
+
+func hostsGet(a *app, args []string) error {
+	hostID, err := hostArg(a, "hosts get", args, nil)
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	host, err := client.Hosts.GetHost(hostID)
+	if err != nil {
+		return err
+	}
+	return a.render(host, func(w io.Writer) {
+		row(w, "HOST ID", host.HostID)
+		row(w, "URL", host.UnicodeHostURL)
+		if host.AsciiHostURL != host.UnicodeHostURL {
+			row(w, "ASCII URL", host.AsciiHostURL)
+		}
+		row(w, "VERIFIED", yesNo(host.Verified))
+		row(w, "DATA", host.HostDataStatus)
+		row(w, "NAME", host.HostDisplayName)
+		if host.MainMirror.HostID != "" {
+			row(w, "MAIN MIRROR", host.MainMirror.UnicodeHostURL)
+		}
+	})
+}
+
+func hostsAdd(a *app, args []string) error {
+	var dryRun bool
+	args, err := commandArgs(a, "hosts add", args, 1, func(fs *flag.FlagSet) {
+		fs.BoolVar(&dryRun, "dry-run", false, "show what would be done without adding site")
+	})
+	if err != nil {
+		return err
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := a.renderFormat(); err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	result := &mutation{Action: "add", HostID: hostID, DryRun: dryRun}
+	if dryRun {
+		hosts, err := client.Hosts.GetHosts()
+		if err != nil {
+			return err
+		}
+		for _, host := range hosts.Hosts {
+			if host.HostID == hostID {
+				result.Note = "site is already added"
+			}
+		}
+	} else {
+		// host id is normalized, raw argument may be a bare name or host id
+		created, err := client.Hosts.AddHost(hostID.ASCIIURL())
+		if err != nil {
+			return err
+		}
+		result.HostID = created.HostID
+		result.Result = created
+	}
+	return a.renderMutation(result)
+}
+
+func hostsDelete(a *app, args []string) error {
+	var dryRun bool
+	hostID, err := hostArg(a, "hosts delete", args, func(fs *flag.FlagSet) {
+		fs.BoolVar(&dryRun, "dry-run", false, "show what would be done without deleting site")
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := a.renderFormat(); err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	result := &mutation{Action: "delete", HostID: hostID, DryRun: dryRun}
+	if dryRun {
+		// fails with not found for unknown site
+		if _, err := client.Hosts.GetHost(hostID); err != nil {
+			return err
+		}
+	} else if _, err := client.Hosts.DeleteHost(hostID); err != nil {
+		return err
+	}
+	return a.renderMutation(result)
+}
+
+// renderMutation writes result of mutation, table output is one line message
+func (a *app) renderMutation(m *mutation) error {
+	return a.render(m, func(w io.Writer) {
+		action := map[string]string{"add": "added", "delete": "deleted"}[m.Action]
+		switch {
+		case m.Note != "":
+			fmt.Fprintf(w, "%s: %s, nothing to do\n", m.target(), m.Note)
+		case m.DryRun:
+			fmt.Fprintf(w, "would be %s: %s (dry run)\n", action, m.target())
+		default:
+			fmt.Fprintf(w, "%s: %s\n", action, m.target())
+		}
+	})
+}
@@ -0,0 +1,241 @@
+// Command ywm manages sites in Yandex Webmaster from command line.
+//
+//	ywm hosts list
+//	ywm hosts add https://example.com --dry-run
+//	ywm hosts get example.com -o yaml
+//	ywm sitemaps list example.com
+//	ywm sitemaps sync example.com --file desired.txt --dry-run
+//	ywm recrawl submit --host example.com -q urls.txt | ywm recrawl watch example.com
+//	ywm queries popular example.com --period "last 28d" --device desktop,mobile --compare -o csv
+//	ywm diagnostics check --all --fail-on critical --junit report.xml
+//
+// OAuth token is taken from --token flag, YWM_TOKEN environment variable or "token"
+// of json config file ~/.config/ywm/config.json, path of config is overridden by
+// --config flag or YWM_CONFIG environment variable:
+//
+//	{"token": "...", "output": "table", "base_url": "https://api.webmaster.yandex.net/v4/"}
+//
+// Exit code tells class of failure: 2 - usage, 3 - authorization, 4 - not found,
+// 5 - conflict, 6 - validation, 7 - quota or rate limit, 8 - api or network unavailable,
+// 1 - other errors. diagnostics check exits with 11 - recommendation, 12 - possible problem,
+// 13 - critical or 14 - fatal by the worst present problem at or above --fail-on.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func main() {
+	a := &app{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr, getenv: os.Getenv}
+	os.Exit(a.run(os.Args[1:]))
+}
+
+// app - state of single cli run
+type app struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	getenv func(string) string
+
+	// global flags
+	token      string
+	configPath string
+	baseURL    string
+	output     string
+	timeout    time.Duration
+
+	config *config
+	api    *ywm.Client
+}
+
+// command - subcommand of group, run gets arguments after command name
+type command struct {
+	name    string
+	args    string
+	summary string
+	run     func(a *app, args []string) error
+}
+
+// group - group of commands over one api service
+type group struct {
+	name     string
+	summary  string
+	commands []*command
+}
+
+// groups - registered command groups by name
+var groups = make(map[string]*group)
+
+func register(g *group) {
+	groups[g.name] = g
+}
+
+// usageError - wrong arguments, usage of command is printed
+type usageError struct {
+	message string
+}
+
+func (e *usageError) Error() string {
+	return e.message
+}
+
+func usagef(format string, args ...interface{}) error {
+	return &usageError{message: fmt.Sprintf(format, args...)}
+}
+
+// run executes command line and returns exit code
+func (a *app) run(args []string) int {
+	// global flags end at command name, flags of command are parsed by command
+	fs := a.flagSet("ywm")
+	fs.Usage = a.usage
+	err := fs.Parse(args)
+	rest := fs.Args()
+	if errors.Is(err, flag.ErrHelp) {
+		return exitOK
+	}
+	if err == nil && (len(rest) == 0 || rest[0] == "help") {
+		a.usage()
+		return exitOK
+	}
+	if err != nil {
+		return a.fail(usagef("%s", err.Error()))
+	}
+	g, ok := groups[rest[0]]
+	if !ok {
+		a.usage()
+		return a.fail(usagef("unknown command %q", rest[0]))
+	}
+	if len(rest) < 2 || rest[1] == "help" || rest[1] == "-h" || rest[1] == "--help" {
+		a.groupUsage(g)
+		if len(rest) < 2 {
+			return exitUsage
+		}
+		return exitOK
+	}
+	for _, cmd := range g.commands {
+		if cmd.name == rest[1] {
+			err := cmd.run(a, rest[2:])
+			if errors.Is(err, flag.ErrHelp) {
+				return exitOK
+			}
+			var uerr *usageError
+			if errors.As(err, &uerr) {
+				fmt.Fprintln(a.stderr, strings.TrimSpace(fmt.Sprintf("usage: ywm %s %s %s", g.name, cmd.name, cmd.args)))
+			}
+			return a.fail(err)
+		}
+	}
+	a.groupUsage(g)
+	return a.fail(usagef("unknown command %q of %s", rest[1], g.name))
+}
+
+// flagSet creates flag set of command with global flags
+func (a *app) flagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(a.stderr)
+	fs.StringVar(&a.token, "token", a.token, "OAuth token, overrides YWM_TOKEN and config")
+	fs.StringVar(&a.configPath, "config", a.configPath, "config file, overrides YWM_CONFIG")
+	fs.StringVar(&a.baseURL, "base-url", a.baseURL, "api base url")
+	fs.StringVar(&a.output, "o", a.output, "output format: table, json or yaml, csv and jsonl for reports")
+	fs.StringVar(&a.output, "output", a.output, "output format: table, json or yaml, csv and jsonl for reports")
+	timeout := a.timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	fs.DurationVar(&a.timeout, "timeout", timeout, "timeout of api request")
+	return fs
+}
+
+// parseFlags parses flags placed before and after positional arguments
+func parseFlags(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func (a *app) usage() {
+	fmt.Fprintln(a.stderr, "usage: ywm <command> <subcommand> [flags] [args]")
+	fmt.Fprintln(a.stderr)
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(a.stderr, "  %-12s %s\n", name, groups[name].summary)
+	}
+	fmt.Fprintln(a.stderr)
+	fmt.Fprintln(a.stderr, "global flags: --token, --config, --base-url, --timeout, -o table|json|yaml|csv|jsonl")
+}
+
+func (a *app) groupUsage(g *group) {
+	fmt.Fprintf(a.stderr, "usage: ywm %s <subcommand> [flags] [args]\n\n", g.name)
+	for _, cmd := range g.commands {
+		fmt.Fprintf(a.stderr, "  %-36s %s\n", cmd.name+" "+cmd.args, cmd.summary)
+	}
+}
+
+// client returns api client created on first use
+func (a *app) client() (*ywm.Client, error) {
+	if a.api != nil {
+		return a.api, nil
+	}
+	cfg, err := a.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	token := a.token
+	if token == "" {
+		token = a.getenv("YWM_TOKEN")
+	}
+	if token == "" {
+		token = cfg.Token
+	}
+	if token == "" {
+		return nil, errNoToken
+	}
+	opts := []ywm.Option{ywm.WithHTTPClient(&http.Client{Timeout: a.timeout})}
+	baseURL := a.baseURL
+	if baseURL == "" {
+		baseURL = cfg.BaseURL
+	}
+	if baseURL != "" {
+		opts = append(opts, ywm.WithBaseURL(baseURL))
+	}
+	a.api, err = ywm.NewClient(token, opts...)
+	return a.api, err
+}
+
+// parseHost parses host id "https:example.com:443", site url or bare domain of https site
+func parseHost(s string) (ywm.HostID, error) {
+	if hostID, err := ywm.ParseHostID(s); err == nil {
+		return hostID, nil
+	}
+	if !strings.Contains(s, "://") {
+		s = "https://" + s
+	}
+	hostID, err := ywm.HostIDFromURL(s)
+	if err != nil {
+		return "", &usageError{message: err.Error()}
+	}
+	return hostID, nil
+}
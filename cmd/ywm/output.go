@@ -0,0 +1,154 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatYAML  = "yaml"
+	// formatCSV and formatJSONLines are supported by commands writing records
+	formatCSV       = "csv"
+	formatJSONLines = "jsonl"
+)
+
+// format returns output format from flag or config, table by default
+func (a *app) format() (string, error) {
+	format := a.output
+	if format == "" {
+		cfg, err := a.loadConfig()
+		if err != nil {
+			return "", err
+		}
+		format = cfg.Output
+	}
+	switch format {
+	case "":
+		return formatTable, nil
+	case formatTable, formatJSON, formatYAML, formatCSV, formatJSONLines:
+		return format, nil
+	}
+	return "", usagef("unknown output format %q, use table, json, yaml, csv or jsonl", format)
+}
+
+// renderFormat returns output format of render, commands changing data check it before api calls
+// so that unsupported csv or jsonl output does not fail after changes are made
+func (a *app) renderFormat() (string, error) {
+	format, err := a.format()
+	if err != nil {
+		return "", err
+	}
+	if format == formatCSV || format == formatJSONLines {
+		return "", usagef("%s output is not supported by command, use table, json or yaml", format)
+	}
+	return format, nil
+}
+
+// render writes v as json or yaml, table output is written by table
+func (a *app) render(v interface{}, table func(w io.Writer)) error {
+	format, err := a.renderFormat()
+	if err != nil {
+		return err
+	}
+	switch format {
+	case formatJSON:
+		return writeJSON(a.stdout, v)
+	case formatYAML:
+		return writeYAML(a.stdout, v)
+	}
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// records - rows of report, header and cells are used by table and csv output, items by json lines
+type records struct {
+	header []string
+	rows   [][]string
+	items  []interface{}
+}
+
+// renderRecords writes report v as json or yaml, records as table, csv or one json line per item
+func (a *app) renderRecords(v interface{}, r *records) error {
+	format, err := a.format()
+	if err != nil {
+		return err
+	}
+	switch format {
+	case formatJSON:
+		return writeJSON(a.stdout, v)
+	case formatYAML:
+		return writeYAML(a.stdout, v)
+	case formatJSONLines:
+		enc := json.NewEncoder(a.stdout)
+		enc.SetEscapeHTML(false)
+		for _, item := range r.items {
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+		}
+		return nil
+	case formatCSV:
+		w := csv.NewWriter(a.stdout)
+		header := make([]string, len(r.header))
+		for i, column := range r.header {
+			// "CTR %" -> "ctr_pct", "SHOWS PREV" -> "shows_prev"
+			header[i] = strings.ReplaceAll(strings.ToLower(strings.ReplaceAll(column, "%", "pct")), " ", "_")
+		}
+		if err := w.Write(header); err != nil {
+			return err
+		}
+		if err := w.WriteAll(r.rows); err != nil {
+			return err
+		}
+		return w.Error()
+	}
+	tw := tabwriter.NewWriter(a.stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(r.header, "\t"))
+	for _, cells := range r.rows {
+		fmt.Fprintln(tw, strings.Join(cells, "\t"))
+	}
+	return tw.Flush()
+}
+
+// row writes tab separated table row
+func row(w io.Writer, columns ...interface{}) {
+	cells := make([]string, len(columns))
+	for i, column := range columns {
+		cells[i] = fmt.Sprint(column)
+	}
+	fmt.Fprintln(w, strings.Join(cells, "\t"))
+}
+
+// yesNo returns table value of flag
+func yesNo(v bool) string {
+	if v {
+		return "yes"
+	}
+	return "no"
+}
+
+// formatTime returns table value of timestamp in its offset
+func formatTime(t ywm.Timestamp) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return t.Format("2006-01-02 15:04")
+}
+
+// writeJSON writes v as indented json
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
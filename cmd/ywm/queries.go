@@ -0,0 +1,642 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/analytics"
+)
+
+func init() {
+	register(&group{
+		name:    "queries",
+		summary: "search query reports",
+		commands: []*command{
+			{name: "popular", args: "<host> [--period \"last 28d\"] [--limit 100] [--order-by shows] [--compare]", summary: "popular queries with indicators", run: queriesPopular},
+			{name: "history", args: "<host> [--period \"last 28d\"] [--compare]", summary: "daily indicators of all queries", run: queriesHistory},
+			{name: "query", args: "<host> <query-id|text> [--text] [--period \"last 28d\"] [--compare]", summary: "daily indicators of single query", run: queriesQuery},
+		},
+	})
+}
+
+// period - inclusive range of calendar dates
+type period struct {
+	From ywm.Date `json:"date_from"`
+	To   ywm.Date `json:"date_to"`
+}
+
+func (p period) String() string {
+	return p.From.String() + ".." + p.To.String()
+}
+
+// days returns number of dates in period
+func (p period) days() int {
+	return int(p.To.Sub(p.From.Time).Hours()/24) + 1
+}
+
+// previous returns period of the same length right before p
+func (p period) previous() (period, error) {
+	from, to, err := analytics.PreviousPeriod(p.From.Time, p.To.Time)
+	if err != nil {
+		return period{}, usagef("%s", err.Error())
+	}
+	return period{From: ywm.DateOf(from), To: ywm.DateOf(to)}, nil
+}
+
+// in returns start of period dates in loc for requests
+func (p period) in(loc *time.Location) (time.Time, time.Time) {
+	return time.Date(p.From.Year(), p.From.Month(), p.From.Day(), 0, 0, 0, 0, loc),
+		time.Date(p.To.Year(), p.To.Month(), p.To.Day(), 0, 0, 0, 0, loc)
+}
+
+var lastPeriodRe = regexp.MustCompile(`^(?:last[ -]?)?(\d+)([dw])$`)
+
+// parsePeriod parses "last 28d", "last 4w", "yesterday", "2026-09-01..2026-09-30" or single date,
+// relative periods end yesterday because data of today is incomplete
+func parsePeriod(s string, today ywm.Date) (period, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "yesterday" {
+		s = "last 1d"
+	}
+	if m := lastPeriodRe.FindStringSubmatch(s); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		if m[2] == "w" {
+			n *= 7
+		}
+		if n < 1 {
+			return period{}, usagef("empty period %q", s)
+		}
+		to := ywm.DateOf(today.AddDate(0, 0, -1))
+		return period{From: ywm.DateOf(to.AddDate(0, 0, -(n - 1))), To: to}, nil
+	}
+	from, to := s, s
+	if i := strings.Index(s, ".."); i >= 0 {
+		from, to = s[:i], s[i+2:]
+	}
+	var p period
+	for _, d := range []struct {
+		value string
+		date  *ywm.Date
+	}{{from, &p.From}, {to, &p.To}} {
+		t, err := time.Parse("2006-01-02", d.value)
+		if err != nil {
+			return period{}, usagef("invalid period %q, use \"last 28d\", \"last 4w\" or \"2006-01-02..2006-01-31\"", s)
+		}
+		*d.date = ywm.DateOf(t)
+	}
+	if p.From.After(p.To.Time) {
+		return period{}, usagef("period %q starts after its end", s)
+	}
+	return p, nil
+}
+
+// metricNames - names of metrics in --indicators
+var metricNames = map[string]analytics.Metric{
+	"shows":          analytics.MetricShows,
+	"clicks":         analytics.MetricClicks,
+	"ctr":            analytics.MetricCTR,
+	"position":       analytics.MetricAvgShowPosition,
+	"show-position":  analytics.MetricAvgShowPosition,
+	"click-position": analytics.MetricAvgClickPosition,
+}
+
+// metricColumns - table and csv column of metric
+var metricColumns = map[analytics.Metric]string{
+	analytics.MetricShows:            "SHOWS",
+	analytics.MetricClicks:           "CLICKS",
+	analytics.MetricCTR:              "CTR %",
+	analytics.MetricAvgShowPosition:  "POSITION",
+	analytics.MetricAvgClickPosition: "CLICK POSITION",
+}
+
+// parseMetrics parses comma separated metrics by short name or api indicator name
+func parseMetrics(s string) ([]analytics.Metric, error) {
+	var metrics []analytics.Metric
+	seen := make(map[analytics.Metric]bool)
+	for _, name := range strings.Split(s, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		m, ok := metricNames[name]
+		if !ok {
+			m = analytics.Metric(strings.ToUpper(name))
+			if _, known := metricColumns[m]; !known {
+				return nil, usagef("unknown indicator %q, use shows, clicks, ctr, position or click-position", name)
+			}
+		}
+		if !seen[m] {
+			seen[m] = true
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, nil
+}
+
+// queryIndicators returns api indicators needed to compute metrics
+func queryIndicators(metrics []analytics.Metric) []ywm.QueryIndicator {
+	var indicators []ywm.QueryIndicator
+	seen := make(map[ywm.QueryIndicator]bool)
+	for _, m := range metrics {
+		needed := []ywm.QueryIndicator{ywm.QueryIndicator(m)}
+		if m == analytics.MetricCTR {
+			needed = []ywm.QueryIndicator{ywm.QueryIndicatorTotalShows, ywm.QueryIndicatorTotalClicks}
+		}
+		for _, indicator := range needed {
+			if !seen[indicator] {
+				seen[indicator] = true
+				indicators = append(indicators, indicator)
+			}
+		}
+	}
+	return indicators
+}
+
+// summaryMetrics - metrics printed by comparison summary, they are requested with --compare whatever --indicators are
+var summaryMetrics = []analytics.Metric{analytics.MetricShows, analytics.MetricClicks, analytics.MetricAvgShowPosition}
+
+// indicators returns api indicators of report metrics and of comparison summary
+func (r *report) indicators() []ywm.QueryIndicator {
+	metrics := append([]analytics.Metric(nil), r.metrics...)
+	if r.compare {
+		metrics = append(metrics, summaryMetrics...)
+	}
+	return queryIndicators(metrics)
+}
+
+// parseDevices parses comma separated device types, e.g. "all,desktop,mobile-and-tablet"
+func parseDevices(s string) ([]ywm.DeviceTypeIndicator, error) {
+	var devices []ywm.DeviceTypeIndicator
+	seen := make(map[ywm.DeviceTypeIndicator]bool)
+	for _, name := range strings.Split(s, ",") {
+		device := ywm.DeviceTypeIndicator(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(name), "-", "_")))
+		if !device.IsKnown() {
+			return nil, usagef("unknown device %q, use all, desktop, mobile-and-tablet, mobile or tablet", name)
+		}
+		if !seen[device] {
+			seen[device] = true
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// reportFlags - flags shared by query reports
+type reportFlags struct {
+	period     string
+	indicators string
+	devices    string
+	compare    bool
+}
+
+func (f *reportFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&f.period, "period", "last 28d", `dates: "last 28d", "last 4w", "yesterday", "2006-01-02..2006-01-31" or single date`)
+	fs.StringVar(&f.indicators, "indicators", "shows,clicks,ctr,position", "comma separated shows, clicks, ctr, position, click-position")
+	fs.StringVar(&f.devices, "device", "all", "comma separated all, desktop, mobile-and-tablet, mobile, tablet")
+	fs.BoolVar(&f.compare, "compare", false, "compare with previous period of the same length")
+}
+
+// report - parsed report flags
+type report struct {
+	client  *ywm.Client
+	period  period
+	metrics []analytics.Metric
+	devices []ywm.DeviceTypeIndicator
+	compare bool
+}
+
+// parse checks report flags and creates client, period is relative to today in reporting location
+func (f *reportFlags) parse(a *app) (*report, error) {
+	metrics, err := parseMetrics(f.indicators)
+	if err != nil {
+		return nil, err
+	}
+	devices, err := parseDevices(f.devices)
+	if err != nil {
+		return nil, err
+	}
+	client, err := a.client()
+	if err != nil {
+		return nil, err
+	}
+	p, err := parsePeriod(f.period, ywm.DateIn(time.Now(), client.Location()))
+	if err != nil {
+		return nil, err
+	}
+	return &report{client: client, period: p, metrics: metrics, devices: devices, compare: f.compare}, nil
+}
+
+// header returns metric columns, previous values and changes are added in comparison
+func (r *report) header(columns ...string) []string {
+	for _, m := range r.metrics {
+		columns = append(columns, metricColumns[m])
+		if r.compare {
+			columns = append(columns, metricColumns[m]+" PREV", metricColumns[m]+" CHANGE")
+		}
+	}
+	return columns
+}
+
+// cells returns metric cells of row, missing values are shown as "-"
+func (r *report) cells(current map[analytics.Metric]float64, previous map[analytics.Metric]float64, cells ...string) []string {
+	for _, m := range r.metrics {
+		cur, curOK := current[m]
+		cells = append(cells, metricValue(m, cur, curOK))
+		if !r.compare {
+			continue
+		}
+		prev, prevOK := previous[m]
+		cells = append(cells, metricValue(m, prev, prevOK))
+		if curOK && prevOK {
+			cells = append(cells, metricChange(m, cur-prev))
+		} else {
+			cells = append(cells, "-")
+		}
+	}
+	return cells
+}
+
+// metricValue formats value of metric, ctr is shown in percents
+func metricValue(m analytics.Metric, v float64, ok bool) string {
+	switch {
+	case !ok:
+		return "-"
+	case m == analytics.MetricCTR:
+		return strconv.FormatFloat(v*100, 'f', 2, 64)
+	case m.LowerIsBetter():
+		return strconv.FormatFloat(v, 'f', 1, 64)
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// metricChange formats signed change of metric, ctr change is in percentage points
+func metricChange(m analytics.Metric, delta float64) string {
+	switch {
+	case m == analytics.MetricCTR:
+		return fmt.Sprintf("%+.2f", delta*100)
+	case m.LowerIsBetter():
+		return fmt.Sprintf("%+.1f", delta)
+	}
+	if delta < 0 {
+		return strconv.FormatFloat(delta, 'f', -1, 64)
+	}
+	return "+" + strconv.FormatFloat(delta, 'f', -1, 64)
+}
+
+// queryValues returns metrics of popular query
+func (r *report) queryValues(q *ywm.PopularSearchQuery) map[analytics.Metric]float64 {
+	values := make(map[analytics.Metric]float64, len(r.metrics))
+	for _, m := range r.metrics {
+		values[m] = analytics.Value(q, m)
+	}
+	return values
+}
+
+// queryRow - popular query of report
+type queryRow struct {
+	Device    ywm.DeviceTypeIndicator      `json:"device"`
+	QueryID   string                       `json:"query_id"`
+	QueryText string                       `json:"query_text"`
+	Values    map[analytics.Metric]float64 `json:"indicators,omitempty"`
+	Previous  map[analytics.Metric]float64 `json:"previous,omitempty"`
+	// Status - NEW, LOST or CHANGED in comparison
+	Status string `json:"status,omitempty"`
+}
+
+// popularReport - json and yaml output of popular queries
+type popularReport struct {
+	HostID         ywm.HostID  `json:"host_id"`
+	Period         period      `json:"period"`
+	PreviousPeriod *period     `json:"previous_period,omitempty"`
+	Queries        []*queryRow `json:"queries"`
+}
+
+func queriesPopular(a *app, args []string) error {
+	var flags reportFlags
+	var limit int
+	var orderBy string
+	hostID, err := hostArg(a, "queries popular", args, func(fs *flag.FlagSet) {
+		flags.register(fs)
+		fs.IntVar(&limit, "limit", 100, "number of queries per device, 0 for all")
+		fs.StringVar(&orderBy, "order-by", "shows", "order of queries: shows or clicks")
+	})
+	if err != nil {
+		return err
+	}
+	var order ywm.QueryIndicator
+	switch orderBy {
+	case "shows", "TOTAL_SHOWS":
+		order = ywm.QueryIndicatorTotalShows
+	case "clicks", "TOTAL_CLICKS":
+		order = ywm.QueryIndicatorTotalClicks
+	default:
+		return usagef("unknown order %q, use shows or clicks", orderBy)
+	}
+	if limit < 0 {
+		return usagef("limit must not be negative")
+	}
+	r, err := flags.parse(a)
+	if err != nil {
+		return err
+	}
+	result := &popularReport{HostID: hostID, Period: r.period, Queries: []*queryRow{}}
+	if r.compare {
+		prev, err := r.period.previous()
+		if err != nil {
+			return err
+		}
+		result.PreviousPeriod = &prev
+	}
+	for _, device := range r.devices {
+		req := ywm.PopularQueriesRequest{QueryIndicators: r.indicators(), OrderBy: order, DeviceType: device}
+		req.DateFrom, req.DateTo = r.period.in(r.client.Location())
+		if !r.compare {
+			current, err := analytics.PopularQueries(r.client.SearchQuery, hostID, req, limit)
+			if err != nil {
+				return err
+			}
+			for _, q := range current {
+				result.Queries = append(result.Queries, &queryRow{Device: device, QueryID: q.QueryID, QueryText: q.QueryText, Values: r.queryValues(q)})
+			}
+			continue
+		}
+		// new and lost queries are only known from complete lists, --limit applies to shown rows
+		current, err := analytics.AllPopularQueries(r.client.SearchQuery, hostID, req)
+		if err != nil {
+			return err
+		}
+		req.DateFrom, req.DateTo = result.PreviousPeriod.in(r.client.Location())
+		previous, err := analytics.AllPopularQueries(r.client.SearchQuery, hostID, req)
+		if err != nil {
+			return err
+		}
+		c := analytics.Compare(current, previous)
+		result.Queries = append(result.Queries, r.comparisonRows(device, c, current, previous, limit)...)
+		writeComparison(a.stderr, device, c.Current, c.Previous, fmt.Sprintf(", %d new, %d lost queries", len(c.New), len(c.Lost)))
+	}
+	records := &records{header: r.header("DEVICE", "QUERY")}
+	if r.compare {
+		records.header = append(records.header, "STATUS")
+	}
+	for _, q := range result.Queries {
+		cells := r.cells(q.Values, q.Previous, string(q.Device), q.QueryText)
+		if r.compare {
+			cells = append(cells, q.Status)
+		}
+		records.rows = append(records.rows, cells)
+		records.items = append(records.items, q)
+	}
+	return a.renderRecords(result, records)
+}
+
+// comparisonRows returns top limit queries of current period with their status and lost queries
+// from top limit of previous period, all queries are returned if limit is zero
+func (r *report) comparisonRows(device ywm.DeviceTypeIndicator, c analytics.Comparison, current []*ywm.PopularSearchQuery, previous []*ywm.PopularSearchQuery, limit int) []*queryRow {
+	previousOf := make(map[*ywm.PopularSearchQuery]*ywm.PopularSearchQuery, len(c.Changed))
+	for _, d := range c.Changed {
+		previousOf[d.Current] = d.Previous
+	}
+	lost := make(map[*ywm.PopularSearchQuery]bool, len(c.Lost))
+	for _, q := range c.Lost {
+		lost[q] = true
+	}
+	if limit > 0 && len(current) > limit {
+		current = current[:limit]
+	}
+	if limit > 0 && len(previous) > limit {
+		previous = previous[:limit]
+	}
+	var rows []*queryRow
+	for _, q := range current {
+		row := &queryRow{Device: device, QueryID: q.QueryID, QueryText: q.QueryText, Values: r.queryValues(q), Status: "NEW"}
+		if prev, ok := previousOf[q]; ok {
+			row.Previous, row.Status = r.queryValues(prev), "CHANGED"
+		}
+		rows = append(rows, row)
+	}
+	for _, q := range previous {
+		if lost[q] {
+			rows = append(rows, &queryRow{Device: device, QueryID: q.QueryID, QueryText: q.QueryText, Previous: r.queryValues(q), Status: "LOST"})
+		}
+	}
+	return rows
+}
+
+// historyRow - daily indicators of report
+type historyRow struct {
+	Device       ywm.DeviceTypeIndicator      `json:"device"`
+	Date         ywm.Date                     `json:"date"`
+	Values       map[analytics.Metric]float64 `json:"indicators"`
+	PreviousDate *ywm.Date                    `json:"previous_date,omitempty"`
+	Previous     map[analytics.Metric]float64 `json:"previous,omitempty"`
+}
+
+// historyReport - json and yaml output of query history
+type historyReport struct {
+	HostID         ywm.HostID    `json:"host_id"`
+	QueryID        string        `json:"query_id,omitempty"`
+	QueryText      string        `json:"query_text,omitempty"`
+	Period         period        `json:"period"`
+	PreviousPeriod *period       `json:"previous_period,omitempty"`
+	Days           []*historyRow `json:"days"`
+}
+
+func queriesHistory(a *app, args []string) error {
+	var flags reportFlags
+	hostID, err := hostArg(a, "queries history", args, flags.register)
+	if err != nil {
+		return err
+	}
+	r, err := flags.parse(a)
+	if err != nil {
+		return err
+	}
+	result := &historyReport{HostID: hostID}
+	err = r.history(a, result, func(req ywm.QueryHistoryRequest) (ywm.SearchAllHistoryIndicator, error) {
+		resp, err := r.client.SearchQuery.GetQueryAllHistory(hostID, req)
+		return resp.Indicators, err
+	})
+	if err != nil {
+		return err
+	}
+	return r.renderHistory(a, result)
+}
+
+func queriesQuery(a *app, args []string) error {
+	var flags reportFlags
+	var byText bool
+	args, err := commandArgs(a, "queries query", args, 2, func(fs *flag.FlagSet) {
+		flags.register(fs)
+		fs.BoolVar(&byText, "text", false, "find query by text among popular queries of period")
+	})
+	if err != nil {
+		return err
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	r, err := flags.parse(a)
+	if err != nil {
+		return err
+	}
+	result := &historyReport{HostID: hostID, QueryID: args[1]}
+	if byText {
+		if result.QueryID, err = r.findQuery(hostID, args[1]); err != nil {
+			return err
+		}
+	}
+	err = r.history(a, result, func(req ywm.QueryHistoryRequest) (ywm.SearchAllHistoryIndicator, error) {
+		resp, err := r.client.SearchQuery.GetSingleSearchQueryHistory(hostID, result.QueryID, req)
+		result.QueryText = resp.QueryText
+		return resp.Indicators, err
+	})
+	if err != nil {
+		return err
+	}
+	return r.renderHistory(a, result)
+}
+
+// findQuery returns id of popular query with text, case is ignored
+func (r *report) findQuery(hostID ywm.HostID, text string) (string, error) {
+	req := ywm.PopularQueriesRequest{QueryIndicators: []ywm.QueryIndicator{ywm.QueryIndicatorTotalShows}}
+	req.DateFrom, req.DateTo = r.period.in(r.client.Location())
+	queries, err := analytics.AllPopularQueries(r.client.SearchQuery, hostID, req)
+	if err != nil {
+		return "", err
+	}
+	for _, q := range queries {
+		if strings.EqualFold(strings.TrimSpace(q.QueryText), strings.TrimSpace(text)) {
+			return q.QueryID, nil
+		}
+	}
+	return "", &notFoundError{message: fmt.Sprintf("query %q is not found among popular queries of %s", text, r.period)}
+}
+
+// history fills days of result by fetch for every device, previous period is fetched in comparison
+func (r *report) history(a *app, result *historyReport, fetch func(req ywm.QueryHistoryRequest) (ywm.SearchAllHistoryIndicator, error)) error {
+	result.Period, result.Days = r.period, []*historyRow{}
+	if r.compare {
+		prev, err := r.period.previous()
+		if err != nil {
+			return err
+		}
+		result.PreviousPeriod = &prev
+	}
+	for _, device := range r.devices {
+		req := ywm.QueryHistoryRequest{QueryIndicators: r.indicators(), DeviceType: device}
+		req.DateFrom, req.DateTo = r.period.in(r.client.Location())
+		current, err := fetch(req)
+		if err != nil {
+			return err
+		}
+		var previous ywm.SearchAllHistoryIndicator
+		if r.compare {
+			req.DateFrom, req.DateTo = result.PreviousPeriod.in(r.client.Location())
+			if previous, err = fetch(req); err != nil {
+				return err
+			}
+			writeComparison(a.stderr, device, summarizeHistory(current), summarizeHistory(previous), "")
+		}
+		currentSeries, previousSeries := indicatorSeries(current), indicatorSeries(previous)
+		for i := 0; i < r.period.days(); i++ {
+			date := ywm.DateOf(r.period.From.AddDate(0, 0, i))
+			day := &historyRow{Device: device, Date: date, Values: r.historyValues(currentSeries, date)}
+			if r.compare {
+				prevDate := ywm.DateOf(result.PreviousPeriod.From.AddDate(0, 0, i))
+				day.PreviousDate, day.Previous = &prevDate, r.historyValues(previousSeries, prevDate)
+			}
+			result.Days = append(result.Days, day)
+		}
+	}
+	return nil
+}
+
+// indicatorSeries returns series of all query indicators of history
+func indicatorSeries(h ywm.SearchAllHistoryIndicator) map[ywm.QueryIndicator]ywm.TimeSeries {
+	series := make(map[ywm.QueryIndicator]ywm.TimeSeries)
+	for _, indicator := range ywm.AllQueryIndicators {
+		series[indicator] = h.Series(indicator)
+	}
+	return series
+}
+
+// historyValues returns metrics on date, metrics without data are missing
+func (r *report) historyValues(series map[ywm.QueryIndicator]ywm.TimeSeries, date ywm.Date) map[analytics.Metric]float64 {
+	values := make(map[analytics.Metric]float64, len(r.metrics))
+	for _, m := range r.metrics {
+		if m == analytics.MetricCTR {
+			shows, _ := series[ywm.QueryIndicatorTotalShows].Value(date)
+			clicks, _ := series[ywm.QueryIndicatorTotalClicks].Value(date)
+			if shows != 0 {
+				values[m] = analytics.CTR(clicks, shows)
+			}
+			continue
+		}
+		if v, ok := series[ywm.QueryIndicator(m)].Value(date); ok {
+			values[m] = v
+		}
+	}
+	return values
+}
+
+func (r *report) renderHistory(a *app, result *historyReport) error {
+	records := &records{header: r.header("DEVICE", "DATE")}
+	if r.compare {
+		records.header = r.header("DEVICE", "DATE", "PREV DATE")
+	}
+	for _, day := range result.Days {
+		cells := []string{string(day.Device), day.Date.String()}
+		if day.PreviousDate != nil {
+			cells = append(cells, day.PreviousDate.String())
+		}
+		records.rows = append(records.rows, r.cells(day.Values, day.Previous, cells...))
+		records.items = append(records.items, day)
+	}
+	return a.renderRecords(result, records)
+}
+
+// summarizeHistory returns totals of history, positions are weighted by shows and clicks
+func summarizeHistory(h ywm.SearchAllHistoryIndicator) analytics.Summary {
+	var s analytics.Summary
+	shows, clicks := h.Series(ywm.QueryIndicatorTotalShows), h.Series(ywm.QueryIndicatorTotalClicks)
+	s.Shows, s.Clicks = shows.Sum(), clicks.Sum()
+	s.CTR = analytics.CTR(s.Clicks, s.Shows)
+	for _, p := range h.Series(ywm.QueryIndicatorAvgShowPosition).Points {
+		weight, _ := shows.Value(p.Date)
+		s.AvgShowPosition += p.Value * weight
+	}
+	for _, p := range h.Series(ywm.QueryIndicatorAvgClickPosition).Points {
+		weight, _ := clicks.Value(p.Date)
+		s.AvgClickPosition += p.Value * weight
+	}
+	if s.Shows != 0 {
+		s.AvgShowPosition /= s.Shows
+	}
+	if s.Clicks != 0 {
+		s.AvgClickPosition /= s.Clicks
+	}
+	return s
+}
+
+// writeComparison writes one line summary of period comparison
+func writeComparison(w io.Writer, device ywm.DeviceTypeIndicator, current analytics.Summary, previous analytics.Summary, suffix string) {
+	fmt.Fprintf(w, "%s: shows %.0f (%s), clicks %.0f (%s), ctr %.2f%% (%+.2f pp), position %.1f (%+.1f)%s\n",
+		device,
+		current.Shows, percentChange(current.Shows, previous.Shows),
+		current.Clicks, percentChange(current.Clicks, previous.Clicks),
+		current.CTR*100, (current.CTR-previous.CTR)*100,
+		current.AvgShowPosition, current.AvgShowPosition-previous.AvgShowPosition,
+		suffix)
+}
+
+// percentChange formats relative change of value
+func percentChange(current float64, previous float64) string {
+	if previous == 0 {
+		return "n/a"
+	}
+	return fmt.Sprintf("%+.1f%%", (current-previous)/previous*100)
+}
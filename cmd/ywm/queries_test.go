@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/analytics"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/webmastertest"
+)
+
+func TestParsePeriod(t *testing.T) {
+	today := ywm.NewDate(2026, 10, 19)
+	tests := []struct {
+		input   string
+		from    string
+		to      string
+		wantErr bool
+	}{
+		{input: "last 28d", from: "2026-09-21", to: "2026-10-18"},
+		{input: "Last 4W", from: "2026-09-21", to: "2026-10-18"},
+		{input: "last-7d", from: "2026-10-12", to: "2026-10-18"},
+		{input: "7d", from: "2026-10-12", to: "2026-10-18"},
+		{input: " yesterday ", from: "2026-10-18", to: "2026-10-18"},
+		{input: "2026-09-01..2026-09-30", from: "2026-09-01", to: "2026-09-30"},
+		{input: "2026-09-01", from: "2026-09-01", to: "2026-09-01"},
+		{input: "2025-12-31..2026-01-01", from: "2025-12-31", to: "2026-01-01"},
+		{input: "last 0d", wantErr: true},
+		{input: "last 28", wantErr: true},
+		{input: "last 1m", wantErr: true},
+		{input: "2026-09-30..2026-09-01", wantErr: true},
+		{input: "2026-09-01..", wantErr: true},
+		{input: "2026-02-30", wantErr: true},
+		{input: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := parsePeriod(tt.input, today)
+			if tt.wantErr {
+				var uerr *usageError
+				if !errors.As(err, &uerr) {
+					t.Errorf("parsePeriod(%q) = %v, %v, want usage error", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.From.String() != tt.from || got.To.String() != tt.to {
+				t.Errorf("parsePeriod(%q) = %s, want %s..%s", tt.input, got, tt.from, tt.to)
+			}
+		})
+	}
+}
+
+func TestReportIndicators(t *testing.T) {
+	tests := []struct {
+		name    string
+		metrics []analytics.Metric
+		compare bool
+		want    []ywm.QueryIndicator
+	}{
+		{
+			name:    "ctr",
+			metrics: []analytics.Metric{analytics.MetricCTR},
+			want:    []ywm.QueryIndicator{ywm.QueryIndicatorTotalShows, ywm.QueryIndicatorTotalClicks},
+		},
+		{
+			name:    "click position",
+			metrics: []analytics.Metric{analytics.MetricAvgClickPosition},
+			want:    []ywm.QueryIndicator{ywm.QueryIndicatorAvgClickPosition},
+		},
+		{
+			name:    "comparison summary",
+			metrics: []analytics.Metric{analytics.MetricClicks},
+			compare: true,
+			want:    []ywm.QueryIndicator{ywm.QueryIndicatorTotalClicks, ywm.QueryIndicatorTotalShows, ywm.QueryIndicatorAvgShowPosition},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &report{metrics: tt.metrics, compare: tt.compare}
+			if got := r.indicators(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("indicators() = %v, want %v", got, tt.want)
+			}
+			if len(r.metrics) != len(tt.metrics) {
+				t.Errorf("metrics are changed: %v", r.metrics)
+			}
+		})
+	}
+}
+
+func TestComparisonRows(t *testing.T) {
+	query := func(id string, shows float64) *ywm.PopularSearchQuery {
+		return &ywm.PopularSearchQuery{QueryID: id, QueryText: id, Indicators: ywm.SearchIndicator{TotalShows: shows}}
+	}
+	// b is below top 2 of previous period, c is below top 2 of current period
+	current := []*ywm.PopularSearchQuery{query("a", 30), query("b", 20), query("c", 10), query("d", 5)}
+	previous := []*ywm.PopularSearchQuery{query("a", 40), query("e", 30), query("b", 10), query("c", 8), query("f", 1)}
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{limit: 2, want: []string{"a CHANGED", "b CHANGED", "e LOST"}},
+		{limit: 1, want: []string{"a CHANGED"}},
+		{limit: 0, want: []string{"a CHANGED", "b CHANGED", "c CHANGED", "d NEW", "e LOST", "f LOST"}},
+	}
+	r := &report{metrics: []analytics.Metric{analytics.MetricShows}, compare: true}
+	c := analytics.Compare(current, previous)
+	for _, tt := range tests {
+		var got []string
+		for _, row := range r.comparisonRows(ywm.DeviceTypeAll, c, current, previous, tt.limit) {
+			got = append(got, row.QueryID+" "+row.Status)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("limit %d: rows = %v, want %v", tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestQueriesPopularCompareRequests(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	s.UpdateHost(hostID, func(h *webmastertest.HostData) {
+		for _, id := range []string{"a", "b", "c"} {
+			h.PopularQueries = append(h.PopularQueries, &ywm.PopularSearchQuery{QueryID: id, QueryText: id})
+		}
+	})
+	code, stdout, stderr := runCLI(t, s, "--token="+s.Token, "queries", "popular", string(hostID),
+		"--period", "2026-09-01..2026-09-07", "--indicators", "clicks", "--limit", "2", "--compare")
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	var popular int
+	for _, req := range s.Requests() {
+		if req.Method != http.MethodGet || !strings.HasSuffix(req.Path, "/search-queries/popular") {
+			continue
+		}
+		popular++
+		indicators := req.Query["query_indicator"]
+		for _, want := range []string{"TOTAL_CLICKS", "TOTAL_SHOWS", "AVG_SHOW_POSITION"} {
+			if !strings.Contains(strings.Join(indicators, ","), want) {
+				t.Errorf("request %v misses %s", req.Query, want)
+			}
+		}
+		if req.Query.Get("limit") != "500" {
+			t.Errorf("previous and current periods are fetched with limit %q", req.Query.Get("limit"))
+		}
+	}
+	if popular != 2 {
+		t.Errorf("%d popular queries requests, want 2", popular)
+	}
+	if rows := strings.Count(stdout, "CHANGED"); rows != 2 {
+		t.Errorf("%d rows, want 2:\n%s", rows, stdout)
+	}
+}
@@ -0,0 +1,406 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func init() {
+	register(&group{
+		name:    "recrawl",
+		summary: "submit urls for recrawl and follow tasks",
+		commands: []*command{
+			{name: "submit", args: "[--host <host>] [--partial] [--dry-run] [-q] [file...]", summary: "submit urls from files or stdin, one per line", run: recrawlSubmit},
+			{name: "status", args: "<host> [task-id...]", summary: "show tasks, ids are read from stdin if not given", run: recrawlStatus},
+			{name: "watch", args: "<host> [task-id...] [--interval 10s] [--max-wait 30m]", summary: "poll tasks until they are finished", run: recrawlWatch},
+			{name: "quota", args: "[host...]", summary: "show recrawl quota of hosts, all verified hosts by default", run: recrawlQuota},
+		},
+	})
+}
+
+// quotaError - urls do not fit into recrawl quota
+type quotaError struct {
+	hostID    ywm.HostID
+	urls      int
+	remainder int
+}
+
+func (e *quotaError) Error() string {
+	return fmt.Sprintf("%d urls of %s exceed recrawl quota remainder %d, use --partial to submit first %d", e.urls, e.hostID, e.remainder, e.remainder)
+}
+
+// recrawlSubmission - result of submitting url
+type recrawlSubmission struct {
+	HostID ywm.HostID `json:"host_id"`
+	URL    string     `json:"url"`
+	TaskID string     `json:"task_id,omitempty"`
+	// Status - SUBMITTED, DRY_RUN, SKIPPED_QUOTA or FAILED
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+// readRecrawlURLs reads urls from files or stdin, drops fragments and duplicates keeping order
+func (a *app) readRecrawlURLs(files []string) ([]string, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+	var urls []string
+	seen := make(map[string]bool)
+	for _, file := range files {
+		in, err := a.openInput(file)
+		if err != nil {
+			return nil, err
+		}
+		lines, err := readList(in)
+		in.Close()
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range lines {
+			u, err := url.Parse(line)
+			if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+				return nil, usagef("%s: invalid url %q", file, line)
+			}
+			u.Fragment = ""
+			if !seen[u.String()] {
+				seen[u.String()] = true
+				urls = append(urls, u.String())
+			}
+		}
+	}
+	return urls, nil
+}
+
+func recrawlSubmit(a *app, args []string) error {
+	var host string
+	var partial, dryRun, quiet bool
+	fs := a.flagSet("recrawl submit")
+	fs.StringVar(&host, "host", "", "host of all urls, urls are grouped by their hosts if empty")
+	fs.BoolVar(&partial, "partial", false, "submit urls fitting into quota instead of failing")
+	fs.BoolVar(&dryRun, "dry-run", false, "check urls and quota without submitting")
+	fs.BoolVar(&quiet, "q", false, "print only task ids, e.g. to pipe them into recrawl watch")
+	files, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	var only ywm.HostID
+	if host != "" {
+		if only, err = parseHost(host); err != nil {
+			return err
+		}
+	}
+	urls, err := a.readRecrawlURLs(files)
+	if err != nil {
+		return err
+	}
+	if len(urls) == 0 {
+		return usagef("no urls to submit")
+	}
+	var hosts []ywm.HostID
+	byHost := make(map[ywm.HostID][]string)
+	for _, u := range urls {
+		hostID, err := ywm.HostIDFromURL(u)
+		if err != nil {
+			return usagef("invalid url %q: %s", u, err.Error())
+		}
+		if only != "" && hostID != only {
+			return usagef("url %s does not belong to %s", u, only)
+		}
+		if _, ok := byHost[hostID]; !ok {
+			hosts = append(hosts, hostID)
+		}
+		byHost[hostID] = append(byHost[hostID], u)
+	}
+	if !quiet {
+		// format is checked before urls are submitted
+		if _, err := a.renderFormat(); err != nil {
+			return err
+		}
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+
+	// quota of every host is checked before anything is submitted
+	remainders := make(map[ywm.HostID]int)
+	for _, hostID := range hosts {
+		quota, err := client.Recrawl.GetRecrawlQuota(hostID)
+		if err != nil {
+			return fmt.Errorf("quota of %s: %w", hostID, err)
+		}
+		remainders[hostID] = quota.QuotaRemainder
+		if n := len(byHost[hostID]); n > quota.QuotaRemainder && !partial {
+			return &quotaError{hostID: hostID, urls: n, remainder: quota.QuotaRemainder}
+		}
+	}
+
+	var results []*recrawlSubmission
+	var firstErr error
+	for _, hostID := range hosts {
+		for i, u := range byHost[hostID] {
+			result := &recrawlSubmission{HostID: hostID, URL: u}
+			results = append(results, result)
+			switch {
+			case i >= remainders[hostID]:
+				result.Status = "SKIPPED_QUOTA"
+			case dryRun:
+				result.Status = "DRY_RUN"
+			default:
+				resp, err := client.Recrawl.RecrawlURL(hostID, u)
+				if err != nil {
+					result.Status, result.Error = "FAILED", errorText(err)
+					if firstErr == nil {
+						firstErr = fmt.Errorf("submit %s: %w", u, err)
+					}
+					continue
+				}
+				result.Status, result.TaskID = "SUBMITTED", resp.TaskID
+			}
+		}
+	}
+	if quiet {
+		for _, r := range results {
+			if r.TaskID != "" {
+				fmt.Fprintln(a.stdout, r.TaskID)
+			}
+		}
+		return firstErr
+	}
+	if err := a.render(results, func(w io.Writer) {
+		row(w, "HOST", "URL", "TASK ID", "STATUS")
+		for _, r := range results {
+			status := r.Status
+			if r.Error != "" {
+				status += ": " + r.Error
+			}
+			row(w, r.HostID, r.URL, dash(r.TaskID), status)
+		}
+	}); err != nil {
+		return err
+	}
+	return firstErr
+}
+
+// dash returns "-" for empty table value
+func dash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// taskArgs returns host and task ids from arguments or stdin, first field of stdin lines is task id
+func (a *app) taskArgs(args []string) (ywm.HostID, []string, error) {
+	if len(args) == 0 {
+		return "", nil, usagef("host is required")
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return "", nil, err
+	}
+	taskIDs := args[1:]
+	if len(taskIDs) == 0 {
+		scanner := bufio.NewScanner(a.stdin)
+		for scanner.Scan() {
+			if fields := strings.Fields(scanner.Text()); len(fields) != 0 && !strings.HasPrefix(fields[0], "#") {
+				taskIDs = append(taskIDs, fields[0])
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return "", nil, err
+		}
+	}
+	if len(taskIDs) == 0 {
+		return "", nil, usagef("no task ids")
+	}
+	return hostID, taskIDs, nil
+}
+
+// renderTasks writes recrawl tasks
+func (a *app) renderTasks(tasks []*ywm.RecrawlTask) error {
+	return a.render(tasks, func(w io.Writer) {
+		row(w, "TASK ID", "URL", "STATE", "ADDED")
+		for _, task := range tasks {
+			row(w, task.TaskID, dash(task.URL), task.State, formatTime(task.AddedTime))
+		}
+	})
+}
+
+// fetchTasks gets state of tasks
+func fetchTasks(recrawl ywm.RecrawlAPI, hostID ywm.HostID, taskIDs []string) ([]*ywm.RecrawlTask, error) {
+	tasks := make([]*ywm.RecrawlTask, 0, len(taskIDs))
+	for _, taskID := range taskIDs {
+		task, err := recrawl.GetRecrawlTask(hostID, taskID)
+		if err != nil {
+			return nil, fmt.Errorf("task %s: %w", taskID, err)
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, nil
+}
+
+// errTasksFailed - some of watched tasks failed
+var errTasksFailed = errors.New("recrawl tasks failed")
+
+func recrawlStatus(a *app, args []string) error {
+	fs := a.flagSet("recrawl status")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	hostID, taskIDs, err := a.taskArgs(args)
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	tasks, err := fetchTasks(client.Recrawl, hostID, taskIDs)
+	if err != nil {
+		return err
+	}
+	return a.renderTasks(tasks)
+}
+
+func recrawlWatch(a *app, args []string) error {
+	var interval, maxWait time.Duration
+	fs := a.flagSet("recrawl watch")
+	fs.DurationVar(&interval, "interval", 10*time.Second, "polling interval")
+	fs.DurationVar(&maxWait, "max-wait", 30*time.Minute, "give up after this time, 0 to wait forever")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if interval <= 0 {
+		return usagef("--interval must be positive")
+	}
+	hostID, taskIDs, err := a.taskArgs(args)
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	var deadline time.Time
+	if maxWait > 0 {
+		deadline = time.Now().Add(maxWait)
+	}
+	states := make(map[string]ywm.RecrawlTaskState)
+	for {
+		tasks, err := fetchTasks(client.Recrawl, hostID, taskIDs)
+		if err != nil {
+			return err
+		}
+		pending, failed := 0, 0
+		for _, task := range tasks {
+			if states[task.TaskID] != task.State {
+				// progress goes to stderr, stdout gets final report
+				fmt.Fprintf(a.stderr, "%s %s %s\n", time.Now().Format("15:04:05"), task.TaskID, task.State)
+				states[task.TaskID] = task.State
+			}
+			if !task.State.IsTerminal() {
+				pending++
+			}
+			if task.State == ywm.RecrawlTaskStateFailed {
+				failed++
+			}
+		}
+		if pending == 0 || (!deadline.IsZero() && time.Now().Add(interval).After(deadline)) {
+			if err := a.renderTasks(tasks); err != nil {
+				return err
+			}
+			switch {
+			case pending != 0:
+				return fmt.Errorf("%d of %d tasks are not finished after %s", pending, len(tasks), maxWait)
+			case failed != 0:
+				return fmt.Errorf("%w: %d of %d", errTasksFailed, failed, len(tasks))
+			}
+			return nil
+		}
+		time.Sleep(interval)
+	}
+}
+
+// hostQuota - recrawl quota of host
+type hostQuota struct {
+	HostID         ywm.HostID `json:"host_id"`
+	DailyQuota     int        `json:"daily_quota"`
+	QuotaRemainder int        `json:"quota_remainder"`
+	Error          string     `json:"error,omitempty"`
+}
+
+func recrawlQuota(a *app, args []string) error {
+	fs := a.flagSet("recrawl quota")
+	args, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	var hosts []ywm.HostID
+	for _, arg := range args {
+		hostID, err := parseHost(arg)
+		if err != nil {
+			return err
+		}
+		hosts = append(hosts, hostID)
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		all, err := client.Hosts.GetHosts()
+		if err != nil {
+			return err
+		}
+		for _, host := range all.Hosts {
+			// quota of unverified hosts is not available
+			if host.Verified {
+				hosts = append(hosts, host.HostID)
+			}
+		}
+	}
+	var firstErr error
+	quotas := make([]*hostQuota, 0, len(hosts))
+	var daily, remainder int
+	for _, hostID := range hosts {
+		q := &hostQuota{HostID: hostID}
+		quota, err := client.Recrawl.GetRecrawlQuota(hostID)
+		if err != nil {
+			q.Error = errorText(err)
+			if firstErr == nil {
+				firstErr = fmt.Errorf("quota of %s: %w", hostID, err)
+			}
+		} else {
+			q.DailyQuota, q.QuotaRemainder = quota.DailyQuota, quota.QuotaRemainder
+			daily += quota.DailyQuota
+			remainder += quota.QuotaRemainder
+		}
+		quotas = append(quotas, q)
+	}
+	if err := a.render(quotas, func(w io.Writer) {
+		row(w, "HOST", "DAILY", "REMAINING", "USED")
+		for _, q := range quotas {
+			if q.Error != "" {
+				row(w, q.HostID, "-", "-", q.Error)
+				continue
+			}
+			row(w, q.HostID, q.DailyQuota, q.QuotaRemainder, q.DailyQuota-q.QuotaRemainder)
+		}
+		if len(quotas) > 1 {
+			row(w, "TOTAL", daily, remainder, daily-remainder)
+		}
+	}); err != nil {
+		return err
+	}
+	return firstErr
+}
@@ -0,0 +1,306 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/webmastertest"
+)
+
+func TestReadRecrawlURLs(t *testing.T) {
+	dir := t.TempDir()
+	first := filepath.Join(dir, "first.txt")
+	second := filepath.Join(dir, "second.txt")
+	writeFile(t, first, "https://example.com/a\nhttps://example.com/b#top\n")
+	writeFile(t, second, "# comment\nhttps://example.com/b\nhttps://example.com/c\n")
+	tests := []struct {
+		name    string
+		stdin   string
+		files   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:  "stdin",
+			stdin: "https://example.com/a\n\nhttps://example.com/a\nhttps://example.com/b#top\nhttps://example.com/b\n",
+			want:  []string{"https://example.com/a", "https://example.com/b"},
+		},
+		{
+			name:  "files keep order",
+			files: []string{first, second},
+			want:  []string{"https://example.com/a", "https://example.com/b", "https://example.com/c"},
+		},
+		{
+			name:  "stdin and file",
+			stdin: "https://example.com/c\nhttps://example.com/a\n",
+			files: []string{"-", first},
+			want:  []string{"https://example.com/c", "https://example.com/a", "https://example.com/b"},
+		},
+		{name: "no scheme", stdin: "example.com/a\n", wantErr: true},
+		{name: "ftp", stdin: "ftp://example.com/a\n", wantErr: true},
+		{name: "missing file", files: []string{filepath.Join(dir, "missing.txt")}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &app{stdin: strings.NewReader(tt.stdin), stdout: &bytes.Buffer{}, stderr: &bytes.Buffer{}}
+			got, err := a.readRecrawlURLs(tt.files)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error %v, want error %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// recrawlPosts returns urls submitted for recrawl
+func recrawlPosts(s *webmastertest.Server) []string {
+	var result []string
+	for _, r := range s.Requests() {
+		if r.Method == http.MethodPost && strings.HasSuffix(r.Path, "/recrawl/queue") {
+			var body struct {
+				URL string `json:"url"`
+			}
+			json.Unmarshal(r.Body, &body)
+			result = append(result, body.URL)
+		}
+	}
+	return result
+}
+
+func TestRecrawlSubmit(t *testing.T) {
+	urls := "https://example.com/a\nhttps://example.com/b\nhttps://example.com/a#dup\n"
+	tests := []struct {
+		name       string
+		quota      int
+		args       []string
+		wantCode   int
+		wantPosts  []string
+		wantStatus []string
+	}{
+		{
+			name:       "fits into quota",
+			quota:      5,
+			wantCode:   exitOK,
+			wantPosts:  []string{"https://example.com/a", "https://example.com/b"},
+			wantStatus: []string{"SUBMITTED", "SUBMITTED"},
+		},
+		{
+			name:     "quota exceeded",
+			quota:    1,
+			wantCode: exitRateLimit,
+		},
+		{
+			name:       "partial",
+			quota:      1,
+			args:       []string{"--partial"},
+			wantCode:   exitOK,
+			wantPosts:  []string{"https://example.com/a"},
+			wantStatus: []string{"SUBMITTED", "SKIPPED_QUOTA"},
+		},
+		{
+			name:       "dry run",
+			quota:      5,
+			args:       []string{"--dry-run"},
+			wantCode:   exitOK,
+			wantStatus: []string{"DRY_RUN", "DRY_RUN"},
+		},
+		{
+			name:     "foreign host",
+			quota:    5,
+			args:     []string{"--host", "other.example.com"},
+			wantCode: exitUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := webmastertest.NewServer(webmastertest.WithDailyQuota(tt.quota))
+			defer s.Close()
+			s.AddHost("https://example.com")
+			args := append([]string{"--token=" + s.Token, "recrawl", "submit", "-o", "json"}, tt.args...)
+			code, stdout, stderr := runCLIInput(t, s, urls, args...)
+			if code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d, stderr: %s", code, tt.wantCode, stderr)
+			}
+			if got := recrawlPosts(s); !reflect.DeepEqual(got, tt.wantPosts) {
+				t.Errorf("submitted %v, want %v", got, tt.wantPosts)
+			}
+			if tt.wantStatus == nil {
+				return
+			}
+			var results []*recrawlSubmission
+			if err := json.Unmarshal([]byte(stdout), &results); err != nil {
+				t.Fatalf("decode %q: %v", stdout, err)
+			}
+			var status []string
+			for _, r := range results {
+				status = append(status, r.Status)
+			}
+			if !reflect.DeepEqual(status, tt.wantStatus) {
+				t.Errorf("status %v, want %v", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestRecrawlSubmitQuiet(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	code, stdout, stderr := runCLIInput(t, s, "https://example.com/a\nhttps://example.com/b\n", "--token="+s.Token, "recrawl", "submit", "-q")
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	host, _ := s.Host(hostID)
+	var want []string
+	for _, task := range host.RecrawlTasks {
+		want = append(want, task.TaskID)
+	}
+	if got := strings.Fields(stdout); len(want) != 2 || !reflect.DeepEqual(got, want) {
+		t.Errorf("task ids %v, want %v", got, want)
+	}
+}
+
+func TestRecrawlSubmitRejectsFormatBeforeAPICall(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	s.AddHost("https://example.com")
+	code, _, stderr := runCLIInput(t, s, "https://example.com/a\n", "--token="+s.Token, "recrawl", "submit", "-o", "csv")
+	if code != exitUsage || !strings.Contains(stderr, "not supported") {
+		t.Errorf("exit code %d, want %d, stderr: %s", code, exitUsage, stderr)
+	}
+	if requests := s.Requests(); len(requests) != 0 {
+		t.Errorf("unexpected api requests %v", requests)
+	}
+}
+
+// submitTasks submits urls and returns their task ids
+func submitTasks(t *testing.T, s *webmastertest.Server, urls ...string) []string {
+	t.Helper()
+	code, stdout, stderr := runCLIInput(t, s, strings.Join(urls, "\n"), "--token="+s.Token, "recrawl", "submit", "-q")
+	if code != exitOK {
+		t.Fatalf("submit exit code %d, stderr: %s", code, stderr)
+	}
+	return strings.Fields(stdout)
+}
+
+func TestRecrawlWatch(t *testing.T) {
+	s := webmastertest.NewServer(webmastertest.WithRecrawlDuration(50 * time.Millisecond))
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	taskIDs := submitTasks(t, s, "https://example.com/a", "https://example.com/b")
+
+	// task ids are read from stdin like in submit -q | watch pipeline
+	code, stdout, stderr := runCLIInput(t, s, strings.Join(taskIDs, "\n"), "--token="+s.Token, "recrawl", "watch", string(hostID), "--interval", "10ms", "-o", "json")
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	var tasks []*ywm.RecrawlTask
+	if err := json.Unmarshal([]byte(stdout), &tasks); err != nil {
+		t.Fatalf("decode %q: %v", stdout, err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("got %d tasks, want 2", len(tasks))
+	}
+	for _, task := range tasks {
+		if task.State != ywm.RecrawlTaskStateDone {
+			t.Errorf("task %s state %s, want DONE", task.TaskID, task.State)
+		}
+	}
+	// every task is reported IN_PROGRESS first, then DONE
+	for _, state := range []ywm.RecrawlTaskState{ywm.RecrawlTaskStateInProgress, ywm.RecrawlTaskStateDone} {
+		if n := strings.Count(stderr, string(state)); n != 2 {
+			t.Errorf("%s reported %d times, want 2, stderr: %s", state, n, stderr)
+		}
+	}
+	var polls int
+	for _, r := range s.Requests() {
+		if r.Method == http.MethodGet && strings.Contains(r.Path, "/recrawl/queue/") {
+			polls++
+		}
+	}
+	// first poll sees unfinished tasks, so watch has to poll again
+	if polls <= 2 {
+		t.Errorf("got %d task requests, want more than one poll", polls)
+	}
+}
+
+func TestRecrawlWatchFailures(t *testing.T) {
+	tests := []struct {
+		name     string
+		duration time.Duration
+		fail     bool
+		args     []string
+		wantCode int
+	}{
+		{name: "failed task", duration: time.Millisecond, fail: true, wantCode: exitError},
+		{name: "max wait", duration: time.Hour, args: []string{"--max-wait", "30ms"}, wantCode: exitError},
+		{name: "invalid interval", duration: time.Millisecond, args: []string{"--interval", "0s"}, wantCode: exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := webmastertest.NewServer(webmastertest.WithRecrawlDuration(tt.duration))
+			defer s.Close()
+			hostID, _ := s.AddHost("https://example.com")
+			taskIDs := submitTasks(t, s, "https://example.com/a")
+			if tt.fail {
+				s.UpdateHost(hostID, func(h *webmastertest.HostData) {
+					h.RecrawlTasks[0].State = ywm.RecrawlTaskStateFailed
+				})
+			}
+			args := append([]string{"--token=" + s.Token, "recrawl", "watch", string(hostID), taskIDs[0], "--interval", "10ms"}, tt.args...)
+			code, stdout, stderr := runCLI(t, s, args...)
+			if code != tt.wantCode {
+				t.Fatalf("exit code %d, want %d, stderr: %s", code, tt.wantCode, stderr)
+			}
+			if tt.wantCode != exitUsage && !strings.Contains(stdout, taskIDs[0]) {
+				t.Errorf("final report is missing task: %s", stdout)
+			}
+		})
+	}
+}
+
+func TestRecrawlQuota(t *testing.T) {
+	s := webmastertest.NewServer(webmastertest.WithDailyQuota(10))
+	defer s.Close()
+	first, _ := s.AddHost("https://example.com")
+	second, _ := s.AddHost("https://shop.example.com")
+	unverified, _ := s.AddHost("https://new.example.com")
+	for _, hostID := range []ywm.HostID{first, second} {
+		s.UpdateHost(hostID, func(h *webmastertest.HostData) { h.Host.Verified = true })
+	}
+	s.UpdateHost(unverified, func(h *webmastertest.HostData) { h.Host.Verified = false })
+	submitTasks(t, s, "https://example.com/a", "https://example.com/b", "https://shop.example.com/a")
+
+	code, stdout, stderr := runCLI(t, s, "--token="+s.Token, "recrawl", "quota", "-o", "json")
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	var quotas []*hostQuota
+	if err := json.Unmarshal([]byte(stdout), &quotas); err != nil {
+		t.Fatalf("decode %q: %v", stdout, err)
+	}
+	want := []*hostQuota{
+		{HostID: first, DailyQuota: 10, QuotaRemainder: 8},
+		{HostID: second, DailyQuota: 10, QuotaRemainder: 9},
+	}
+	if !reflect.DeepEqual(quotas, want) {
+		t.Errorf("got %+v, want %+v", quotas, want)
+	}
+
+	code, stdout, stderr = runCLI(t, s, "--token="+s.Token, "recrawl", "quota")
+	if code != exitOK {
+		t.Fatalf("exit code %d, stderr: %s", code, stderr)
+	}
+	if lines := strings.Split(strings.TrimSpace(stdout), "\n"); len(lines) != 4 || !strings.HasPrefix(lines[3], "TOTAL") || !strings.Contains(lines[3], "17") {
+		t.Errorf("unexpected table:\n%s", stdout)
+	}
+}
@@ -0,0 +1,479 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func init() {
+	register(&group{
+		name:    "sitemaps",
+		summary: "list, add, delete and sync sitemaps",
+		commands: []*command{
+			{name: "list", args: "<host> [--flat]", summary: "show sitemaps found by robot as tree of sitemap indexes", run: sitemapsList},
+			{name: "get", args: "<host> <sitemap-id>", summary: "show sitemap found by robot", run: sitemapsGet},
+			{name: "user-added", args: "<host>", summary: "list sitemaps added by user", run: sitemapsUserAdded},
+			{name: "add", args: "<host> <url> [--dry-run]", summary: "add sitemap", run: sitemapsAdd},
+			{name: "delete", args: "<host> <sitemap-id|url> [--dry-run]", summary: "delete sitemap added by user", run: sitemapsDelete},
+			{name: "sync", args: "<host> --file <desired.txt> [--dry-run] [--yes]", summary: "add and delete user sitemaps to match file", run: sitemapsSync},
+		},
+	})
+}
+
+// sitemapsPageLimit - page size of sitemap listings, maximum of api
+const sitemapsPageLimit = 100
+
+// sitemapNode - sitemap with children of sitemap index
+type sitemapNode struct {
+	Sitemap  *ywm.Sitemap   `json:"sitemap"`
+	Children []*sitemapNode `json:"children,omitempty"`
+}
+
+// listSitemaps returns all sitemaps with parent, paging with from cursor
+func listSitemaps(sitemaps ywm.SitemapsAPI, hostID ywm.HostID, parentID string) ([]*ywm.Sitemap, error) {
+	var all []*ywm.Sitemap
+	req := ywm.SitemapsRequest{ParentID: parentID, Limit: sitemapsPageLimit}
+	for {
+		page, err := sitemaps.GetSitemaps(hostID, req)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Sitemaps...)
+		if len(page.Sitemaps) < sitemapsPageLimit {
+			return all, nil
+		}
+		req.From = page.Sitemaps[len(page.Sitemaps)-1].SitemapID
+	}
+}
+
+// sitemapTree returns sitemaps with parent and children of sitemap indexes, seen guards against cycles
+func sitemapTree(sitemaps ywm.SitemapsAPI, hostID ywm.HostID, parentID string, seen map[string]bool) ([]*sitemapNode, error) {
+	list, err := listSitemaps(sitemaps, hostID, parentID)
+	if err != nil {
+		return nil, err
+	}
+	nodes := make([]*sitemapNode, 0, len(list))
+	for _, sitemap := range list {
+		node := &sitemapNode{Sitemap: sitemap}
+		if sitemap.ChildrenCount > 0 && !seen[sitemap.SitemapID] {
+			seen[sitemap.SitemapID] = true
+			if node.Children, err = sitemapTree(sitemaps, hostID, sitemap.SitemapID, seen); err != nil {
+				return nil, err
+			}
+		}
+		nodes = append(nodes, node)
+	}
+	return nodes, nil
+}
+
+// userAddedSitemaps returns all sitemaps added by user, paging with offset
+func userAddedSitemaps(sitemaps ywm.SitemapsAPI, hostID ywm.HostID) ([]*ywm.AddedUserSitemap, error) {
+	var all []*ywm.AddedUserSitemap
+	req := ywm.UserAddedSitemapsRequest{Limit: sitemapsPageLimit}
+	for {
+		page, err := sitemaps.GetUserAddedSitemaps(hostID, req)
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page.Sitemaps...)
+		if len(page.Sitemaps) == 0 || len(all) >= page.Count {
+			return all, nil
+		}
+		req.Offset = len(all)
+	}
+}
+
+func sitemapsList(a *app, args []string) error {
+	var flat bool
+	args, err := commandArgs(a, "sitemaps list", args, 1, func(fs *flag.FlagSet) {
+		fs.BoolVar(&flat, "flat", false, "list only top level sitemaps")
+	})
+	if err != nil {
+		return err
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	var tree []*sitemapNode
+	if flat {
+		list, err := listSitemaps(client.Sitemaps, hostID, "")
+		if err != nil {
+			return err
+		}
+		for _, sitemap := range list {
+			tree = append(tree, &sitemapNode{Sitemap: sitemap})
+		}
+	} else if tree, err = sitemapTree(client.Sitemaps, hostID, "", make(map[string]bool)); err != nil {
+		return err
+	}
+	return a.render(tree, func(w io.Writer) {
+		row(w, "SITEMAP", "TYPE", "URLS", "ERRORS", "SOURCES", "LAST ACCESS", "ID")
+		writeSitemapTree(w, tree, "", true)
+	})
+}
+
+// writeSitemapTree writes table rows of nodes, children are drawn with tree branches below their index
+func writeSitemapTree(w io.Writer, nodes []*sitemapNode, indent string, top bool) {
+	for i, node := range nodes {
+		s := node.Sitemap
+		prefix, childIndent := "", ""
+		if !top {
+			prefix, childIndent = "├── ", indent+"│   "
+			if i == len(nodes)-1 {
+				prefix, childIndent = "└── ", indent+"    "
+			}
+		}
+		row(w, indent+prefix+s.SitemapURL, s.SitemapType, s.URLsCount, s.ErrorsCount, strings.Join(s.Sources, ","), formatTime(s.LastAccessDate), s.SitemapID)
+		writeSitemapTree(w, node.Children, childIndent, false)
+	}
+}
+
+func sitemapsGet(a *app, args []string) error {
+	args, err := commandArgs(a, "sitemaps get", args, 2, nil)
+	if err != nil {
+		return err
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	s, err := client.Sitemaps.GetSitemap(hostID, args[1])
+	if err != nil {
+		return err
+	}
+	return a.render(s, func(w io.Writer) {
+		row(w, "ID", s.SitemapID)
+		row(w, "URL", s.SitemapURL)
+		row(w, "TYPE", s.SitemapType)
+		row(w, "URLS", s.URLsCount)
+		row(w, "ERRORS", s.ErrorsCount)
+		if s.ChildrenCount > 0 {
+			row(w, "CHILDREN", s.ChildrenCount)
+		}
+		row(w, "SOURCES", strings.Join(s.Sources, ","))
+		row(w, "LAST ACCESS", formatTime(s.LastAccessDate))
+	})
+}
+
+func sitemapsUserAdded(a *app, args []string) error {
+	hostID, err := hostArg(a, "sitemaps user-added", args, nil)
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	sitemaps, err := userAddedSitemaps(client.Sitemaps, hostID)
+	if err != nil {
+		return err
+	}
+	result := ywm.AddedUserSitemaps{Sitemaps: sitemaps, Count: len(sitemaps)}
+	return a.render(result, func(w io.Writer) {
+		row(w, "ID", "URL", "ADDED")
+		for _, s := range sitemaps {
+			row(w, s.SitemapID, s.SitemapURL, formatTime(s.AddedDate))
+		}
+	})
+}
+
+// sitemapURLArg checks that sitemap url belongs to host
+func sitemapURLArg(hostID ywm.HostID, sitemapURL string) error {
+	urlHostID, err := ywm.HostIDFromURL(sitemapURL)
+	if err != nil {
+		return usagef("invalid sitemap url %q", sitemapURL)
+	}
+	if urlHostID != hostID {
+		return usagef("sitemap %s does not belong to %s", sitemapURL, hostID)
+	}
+	return nil
+}
+
+// sitemapKey returns sitemap url with scheme, host and port normalized as host id, so that
+// "HTTPS://Example.com:443/sitemap.xml" matches "https://example.com/sitemap.xml"
+func sitemapKey(sitemapURL string) string {
+	u, err := url.Parse(sitemapURL)
+	if err != nil {
+		return sitemapURL
+	}
+	hostID, err := ywm.HostIDFromURL(sitemapURL)
+	if err != nil {
+		return sitemapURL
+	}
+	return string(hostID) + u.RequestURI()
+}
+
+func sitemapsAdd(a *app, args []string) error {
+	var dryRun bool
+	args, err := commandArgs(a, "sitemaps add", args, 2, func(fs *flag.FlagSet) {
+		fs.BoolVar(&dryRun, "dry-run", false, "show what would be done without adding sitemap")
+	})
+	if err != nil {
+		return err
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	sitemapURL := args[1]
+	if err := sitemapURLArg(hostID, sitemapURL); err != nil {
+		return err
+	}
+	if _, err := a.renderFormat(); err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	result := &mutation{Action: "add", HostID: hostID, URL: sitemapURL, DryRun: dryRun}
+	if dryRun {
+		added, err := userAddedSitemaps(client.Sitemaps, hostID)
+		if err != nil {
+			return err
+		}
+		for _, s := range added {
+			if sitemapKey(s.SitemapURL) == sitemapKey(sitemapURL) {
+				result.SitemapID = s.SitemapID
+				result.Note = "sitemap is already added"
+			}
+		}
+	} else {
+		added, err := client.Sitemaps.AddSitemap(hostID, sitemapURL)
+		if err != nil {
+			return err
+		}
+		result.SitemapID = added.SitemapID
+		result.Result = added
+	}
+	return a.renderMutation(result)
+}
+
+func sitemapsDelete(a *app, args []string) error {
+	var dryRun bool
+	args, err := commandArgs(a, "sitemaps delete", args, 2, func(fs *flag.FlagSet) {
+		fs.BoolVar(&dryRun, "dry-run", false, "show what would be done without deleting sitemap")
+	})
+	if err != nil {
+		return err
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	if _, err := a.renderFormat(); err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	result := &mutation{Action: "delete", HostID: hostID, DryRun: dryRun}
+	if strings.Contains(args[1], "://") {
+		// sitemap is given by url, look up its id
+		added, err := userAddedSitemaps(client.Sitemaps, hostID)
+		if err != nil {
+			return err
+		}
+		for _, s := range added {
+			if sitemapKey(s.SitemapURL) == sitemapKey(args[1]) {
+				result.SitemapID, result.URL = s.SitemapID, s.SitemapURL
+			}
+		}
+		if result.SitemapID == "" {
+			return &notFoundError{message: fmt.Sprintf("sitemap %s is not added by user", args[1])}
+		}
+	} else {
+		s, err := client.Sitemaps.GetUserAddedSitemap(hostID, args[1])
+		if err != nil {
+			return err
+		}
+		result.SitemapID, result.URL = s.SitemapID, s.SitemapURL
+	}
+	if !dryRun {
+		if _, err := client.Sitemaps.DeleteSitemap(hostID, result.SitemapID); err != nil {
+			return err
+		}
+	}
+	return a.renderMutation(result)
+}
+
+// sitemapPlan - changes of user sitemaps to match desired list
+type sitemapPlan struct {
+	HostID ywm.HostID              `json:"host_id"`
+	Add    []string                `json:"add"`
+	Delete []*ywm.AddedUserSitemap `json:"delete"`
+	Keep   []*ywm.AddedUserSitemap `json:"keep"`
+	DryRun bool                    `json:"dry_run"`
+	// Applied - plan was executed
+	Applied bool `json:"applied"`
+}
+
+// readList reads values one per line, blank lines and lines starting with # are skipped
+func readList(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, scanner.Err()
+}
+
+// openInput opens file or stdin for "-"
+func (a *app) openInput(name string) (io.ReadCloser, error) {
+	if name == "-" {
+		return io.NopCloser(a.stdin), nil
+	}
+	return os.Open(name)
+}
+
+// confirm asks question on stderr and reads answer from stdin
+func (a *app) confirm(question string) bool {
+	fmt.Fprintf(a.stderr, "%s [y/N] ", question)
+	answer, _ := bufio.NewReader(a.stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func sitemapsSync(a *app, args []string) error {
+	var file string
+	var dryRun, yes bool
+	args, err := commandArgs(a, "sitemaps sync", args, 1, func(fs *flag.FlagSet) {
+		fs.StringVar(&file, "file", "", "file with desired sitemap urls one per line, - for stdin")
+		fs.BoolVar(&dryRun, "dry-run", false, "only show plan")
+		fs.BoolVar(&yes, "yes", false, "apply plan without confirmation")
+	})
+	if err != nil {
+		return err
+	}
+	if file == "" {
+		return usagef("--file is required")
+	}
+	if file == "-" && !yes && !dryRun {
+		return usagef("--yes or --dry-run is required when file is read from stdin")
+	}
+	hostID, err := parseHost(args[0])
+	if err != nil {
+		return err
+	}
+	in, err := a.openInput(file)
+	if err != nil {
+		return err
+	}
+	desired, err := readList(in)
+	in.Close()
+	if err != nil {
+		return err
+	}
+	for _, u := range desired {
+		if err := sitemapURLArg(hostID, u); err != nil {
+			return err
+		}
+	}
+	format, err := a.renderFormat()
+	if err != nil {
+		return err
+	}
+	client, err := a.client()
+	if err != nil {
+		return err
+	}
+	added, err := userAddedSitemaps(client.Sitemaps, hostID)
+	if err != nil {
+		return err
+	}
+
+	plan := planSitemaps(hostID, desired, added)
+	plan.DryRun = dryRun
+	changes := len(plan.Add) + len(plan.Delete)
+	if format == formatTable || (!dryRun && !yes) {
+		a.writePlan(plan)
+	}
+	if changes != 0 && !dryRun {
+		if !yes && !a.confirm(fmt.Sprintf("apply %d changes?", changes)) {
+			return fmt.Errorf("plan is not applied")
+		}
+		for _, u := range plan.Add {
+			if _, err := client.Sitemaps.AddSitemap(hostID, u); err != nil {
+				return fmt.Errorf("add %s: %w", u, err)
+			}
+		}
+		for _, s := range plan.Delete {
+			if _, err := client.Sitemaps.DeleteSitemap(hostID, s.SitemapID); err != nil {
+				return fmt.Errorf("delete %s: %w", s.SitemapURL, err)
+			}
+		}
+		plan.Applied = true
+	}
+	if format != formatTable {
+		return a.render(plan, nil)
+	}
+	switch {
+	case changes == 0:
+		fmt.Fprintln(a.stdout, "nothing to do")
+	case plan.Applied:
+		fmt.Fprintf(a.stdout, "applied: %d added, %d deleted\n", len(plan.Add), len(plan.Delete))
+	}
+	return nil
+}
+
+// planSitemaps returns changes of added user sitemaps to match desired urls, urls are compared by sitemapKey
+// and duplicates are added once
+func planSitemaps(hostID ywm.HostID, desired []string, added []*ywm.AddedUserSitemap) *sitemapPlan {
+	plan := &sitemapPlan{HostID: hostID, Add: []string{}, Delete: []*ywm.AddedUserSitemap{}, Keep: []*ywm.AddedUserSitemap{}}
+	want := make(map[string]bool)
+	for _, u := range desired {
+		want[sitemapKey(u)] = true
+	}
+	existing := make(map[string]bool)
+	for _, s := range added {
+		existing[sitemapKey(s.SitemapURL)] = true
+		if want[sitemapKey(s.SitemapURL)] {
+			plan.Keep = append(plan.Keep, s)
+		} else {
+			plan.Delete = append(plan.Delete, s)
+		}
+	}
+	for _, u := range desired {
+		if !existing[sitemapKey(u)] {
+			existing[sitemapKey(u)] = true
+			plan.Add = append(plan.Add, u)
+		}
+	}
+	return plan
+}
+
+// writePlan writes plan of sync in table form, plan goes to stderr when output is machine readable
+func (a *app) writePlan(plan *sitemapPlan) {
+	w := a.stdout
+	if format, _ := a.format(); format != formatTable {
+		w = a.stderr
+	}
+	fmt.Fprintf(w, "plan for %s: %d to add, %d to delete, %d unchanged\n", plan.HostID, len(plan.Add), len(plan.Delete), len(plan.Keep))
+	for _, u := range plan.Add {
+		fmt.Fprintf(w, "  + %s\n", u)
+	}
+	for _, s := range plan.Delete {
+		fmt.Fprintf(w, "  - %s (%s)\n", s.SitemapURL, s.SitemapID)
+	}
+}
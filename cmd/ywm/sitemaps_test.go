@@ -0,0 +1,235 @@
+package main
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+	"github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster/webmastertest"
+)
+
+func TestPlanSitemaps(t *testing.T) {
+	a := &ywm.AddedUserSitemap{SitemapID: "1", SitemapURL: "https://example.com/a.xml"}
+	b := &ywm.AddedUserSitemap{SitemapID: "2", SitemapURL: "https://example.com/b.xml"}
+	tests := []struct {
+		name       string
+		desired    []string
+		added      []*ywm.AddedUserSitemap
+		wantAdd    []string
+		wantDelete []*ywm.AddedUserSitemap
+		wantKeep   []*ywm.AddedUserSitemap
+	}{
+		{
+			name:       "nothing",
+			wantAdd:    []string{},
+			wantDelete: []*ywm.AddedUserSitemap{},
+			wantKeep:   []*ywm.AddedUserSitemap{},
+		},
+		{
+			name:       "unchanged",
+			desired:    []string{b.SitemapURL, a.SitemapURL},
+			added:      []*ywm.AddedUserSitemap{a, b},
+			wantAdd:    []string{},
+			wantDelete: []*ywm.AddedUserSitemap{},
+			wantKeep:   []*ywm.AddedUserSitemap{a, b},
+		},
+		{
+			name:       "add and delete",
+			desired:    []string{a.SitemapURL, "https://example.com/c.xml"},
+			added:      []*ywm.AddedUserSitemap{a, b},
+			wantAdd:    []string{"https://example.com/c.xml"},
+			wantDelete: []*ywm.AddedUserSitemap{b},
+			wantKeep:   []*ywm.AddedUserSitemap{a},
+		},
+		{
+			name:       "delete all",
+			added:      []*ywm.AddedUserSitemap{a, b},
+			wantAdd:    []string{},
+			wantDelete: []*ywm.AddedUserSitemap{a, b},
+			wantKeep:   []*ywm.AddedUserSitemap{},
+		},
+		{
+			name:       "urls are compared normalized",
+			desired:    []string{"HTTPS://Example.com:443/a.xml", "https://example.com/b.xml/"},
+			added:      []*ywm.AddedUserSitemap{a, b},
+			wantAdd:    []string{"https://example.com/b.xml/"},
+			wantDelete: []*ywm.AddedUserSitemap{b},
+			wantKeep:   []*ywm.AddedUserSitemap{a},
+		},
+		{
+			name:       "duplicate urls are added once",
+			desired:    []string{"https://example.com/c.xml", "https://example.com/d.xml", "https://example.com/c.xml"},
+			wantAdd:    []string{"https://example.com/c.xml", "https://example.com/d.xml"},
+			wantDelete: []*ywm.AddedUserSitemap{},
+			wantKeep:   []*ywm.AddedUserSitemap{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan := planSitemaps("https:example.com:443", tt.desired, tt.added)
+			if !reflect.DeepEqual(plan.Add, tt.wantAdd) {
+				t.Errorf("Add = %v, want %v", plan.Add, tt.wantAdd)
+			}
+			if !reflect.DeepEqual(plan.Delete, tt.wantDelete) {
+				t.Errorf("Delete = %v, want %v", plan.Delete, tt.wantDelete)
+			}
+			if !reflect.DeepEqual(plan.Keep, tt.wantKeep) {
+				t.Errorf("Keep = %v, want %v", plan.Keep, tt.wantKeep)
+			}
+		})
+	}
+}
+
+func TestSitemapsSync(t *testing.T) {
+	tests := []struct {
+		name  string
+		stdin string
+		flags []string
+		code  int
+		want  []string
+	}{
+		{name: "dry run", flags: []string{"--dry-run"}, want: []string{"https://example.com/a.xml", "https://example.com/b.xml"}},
+		{name: "yes", flags: []string{"--yes"}, want: []string{"https://example.com/a.xml", "https://example.com/c.xml"}},
+		{name: "confirmed", stdin: "y\n", want: []string{"https://example.com/a.xml", "https://example.com/c.xml"}},
+		{name: "declined", stdin: "n\n", code: exitError, want: []string{"https://example.com/a.xml", "https://example.com/b.xml"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := webmastertest.NewServer()
+			defer s.Close()
+			hostID, _ := s.AddHost("https://example.com")
+			s.UpdateHost(hostID, func(h *webmastertest.HostData) {
+				h.UserSitemaps = []*ywm.AddedUserSitemap{
+					{SitemapID: "1", SitemapURL: "https://example.com/a.xml"},
+					{SitemapID: "2", SitemapURL: "https://example.com/b.xml"},
+				}
+			})
+			file := t.TempDir() + "/desired.txt"
+			writeFile(t, file, "# desired\nhttps://example.com/a.xml\n\nhttps://example.com/c.xml\n")
+			args := append([]string{"--token=" + s.Token, "sitemaps", "sync", string(hostID), "--file", file}, tt.flags...)
+			code, stdout, stderr := runCLIInput(t, s, tt.stdin, args...)
+			if code != tt.code {
+				t.Fatalf("exit code %d, want %d, stderr: %s", code, tt.code, stderr)
+			}
+			if !strings.Contains(stdout, "1 to add, 1 to delete, 1 unchanged") {
+				t.Errorf("plan is not shown: %s", stdout)
+			}
+			host, _ := s.Host(hostID)
+			var got []string
+			for _, sitemap := range host.UserSitemaps {
+				got = append(got, sitemap.SitemapURL)
+			}
+			sort.Strings(got)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("sitemaps = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSitemapsSyncErrors(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	file := t.TempDir() + "/desired.txt"
+	writeFile(t, file, "https://other.com/sitemap.xml\n")
+	tests := []struct {
+		name string
+		args []string
+		want int
+	}{
+		{name: "no file", args: []string{"sitemaps", "sync", string(hostID)}, want: exitUsage},
+		{name: "stdin without yes", args: []string{"sitemaps", "sync", string(hostID), "--file", "-"}, want: exitUsage},
+		{name: "foreign url", args: []string{"sitemaps", "sync", string(hostID), "--file", file, "--dry-run"}, want: exitUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCLI(t, s, append([]string{"--token=" + s.Token}, tt.args...)...)
+			if code != tt.want {
+				t.Errorf("exit code %d, want %d, stderr: %s", code, tt.want, stderr)
+			}
+		})
+	}
+}
+
+func TestSitemapKey(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{url: "https://example.com/sitemap.xml", want: "https:example.com:443/sitemap.xml"},
+		{url: "HTTPS://Example.COM:443/sitemap.xml", want: "https:example.com:443/sitemap.xml"},
+		{url: "http://example.com:8080/s.xml?page=2", want: "http:example.com:8080/s.xml?page=2"},
+		{url: "https://пример.рф/sitemap.xml", want: "https:xn--e1afmkfd.xn--p1ai:443/sitemap.xml"},
+		{url: "https://example.com", want: "https:example.com:443/"},
+		{url: "not a url", want: "not a url"},
+	}
+	for _, tt := range tests {
+		if got := sitemapKey(tt.url); got != tt.want {
+			t.Errorf("sitemapKey(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestSitemapsDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		arg  string
+		code int
+		left int
+	}{
+		{name: "by url", arg: "https://example.com/a.xml", code: exitOK, left: 0},
+		{name: "by url in other case", arg: "HTTPS://EXAMPLE.com:443/a.xml", code: exitOK, left: 0},
+		{name: "by id", arg: "1", code: exitOK, left: 0},
+		{name: "unknown url", arg: "https://example.com/missing.xml", code: exitNotFound, left: 1},
+		{name: "unknown id", arg: "42", code: exitNotFound, left: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := webmastertest.NewServer()
+			defer s.Close()
+			hostID, _ := s.AddHost("https://example.com")
+			s.UpdateHost(hostID, func(h *webmastertest.HostData) {
+				h.UserSitemaps = []*ywm.AddedUserSitemap{{SitemapID: "1", SitemapURL: "https://example.com/a.xml"}}
+			})
+			code, _, stderr := runCLI(t, s, "--token="+s.Token, "sitemaps", "delete", string(hostID), tt.arg)
+			if code != tt.code {
+				t.Errorf("exit code %d, want %d, stderr: %s", code, tt.code, stderr)
+			}
+			host, _ := s.Host(hostID)
+			if len(host.UserSitemaps) != tt.left {
+				t.Errorf("%d sitemaps left, want %d", len(host.UserSitemaps), tt.left)
+			}
+		})
+	}
+}
+
+func TestSitemapsRejectFormatBeforeAPICall(t *testing.T) {
+	s := webmastertest.NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	file := t.TempDir() + "/desired.txt"
+	writeFile(t, file, "https://example.com/c.xml\n")
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "add", args: []string{"sitemaps", "add", string(hostID), "https://example.com/c.xml", "-o", "csv"}},
+		{name: "delete", args: []string{"sitemaps", "delete", string(hostID), "https://example.com/a.xml", "-o", "jsonl"}},
+		{name: "sync", args: []string{"sitemaps", "sync", string(hostID), "--file", file, "--yes", "-o", "csv"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := len(s.Requests())
+			code, _, stderr := runCLI(t, s, append([]string{"--token=" + s.Token}, tt.args...)...)
+			if code != exitUsage || !strings.Contains(stderr, "not supported") {
+				t.Errorf("exit code %d, want %d, stderr: %s", code, exitUsage, stderr)
+			}
+			if requests := s.Requests()[before:]; len(requests) != 0 {
+				t.Errorf("api is called: %+v", requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// yamlNode - json value with order of object keys kept
+type yamlNode struct {
+	// scalar - json encoded value of null, bool, number or string
+	scalar json.RawMessage
+	// object - value is object with keys and values in order
+	object bool
+	keys   []string
+	items  []*yamlNode
+}
+
+// writeYAML writes v as yaml, v is encoded to json first so json tags and marshalers apply
+func writeYAML(w io.Writer, v interface{}) error {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	dec := json.NewDecoder(buf)
+	dec.UseNumber()
+	node, err := decodeYAMLNode(dec)
+	if err != nil {
+		return err
+	}
+	out := &strings.Builder{}
+	if inline, ok := node.inline(); ok {
+		out.WriteString(inline + "\n")
+	} else {
+		node.write(out, 0)
+	}
+	_, err = io.WriteString(w, out.String())
+	return err
+}
+
+// decodeYAMLNode reads next json value from dec
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	token, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yamlNode{object: t == '{'}
+		for dec.More() {
+			if node.object {
+				key, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key.(string))
+			}
+			item, err := decodeYAMLNode(dec)
+			if err != nil {
+				return nil, err
+			}
+			node.items = append(node.items, item)
+		}
+		// closing delimiter
+		if _, err := dec.Token(); err != nil {
+			return nil, err
+		}
+		return node, nil
+	case nil:
+		return &yamlNode{scalar: json.RawMessage("null")}, nil
+	case json.Number:
+		return &yamlNode{scalar: json.RawMessage(t.String())}, nil
+	case bool:
+		if t {
+			return &yamlNode{scalar: json.RawMessage("true")}, nil
+		}
+		return &yamlNode{scalar: json.RawMessage("false")}, nil
+	case string:
+		return &yamlNode{scalar: json.RawMessage(yamlString(t))}, nil
+	}
+	return nil, io.ErrUnexpectedEOF
+}
+
+// yamlPlainRe - strings safe to write without quotes
+var yamlPlainRe = regexp.MustCompile(`^[\p{L}\p{N}_/.][\p{L}\p{N}_/.:@+\- ]*$`)
+
+// yamlReservedRe - plain strings which yaml reads as other types
+var yamlReservedRe = regexp.MustCompile(`^(?i:null|~|true|false|yes|no|on|off|y|n|[-+]?(\.inf|\.nan)|[-+]?\.?[0-9][0-9_.eE+\-:]*|0x[0-9a-f_]+|0o[0-7_]+|0b[01_]+)$`)
+
+// yamlString returns string as plain scalar if possible, double quoted otherwise
+func yamlString(s string) string {
+	if yamlPlainRe.MatchString(s) && !yamlReservedRe.MatchString(s) &&
+		!strings.HasSuffix(s, " ") && !strings.HasSuffix(s, ":") && !strings.Contains(s, ": ") {
+		return s
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// inline returns value written on the line of its key: scalars and empty collections
+func (n *yamlNode) inline() (string, bool) {
+	switch {
+	case n.scalar != nil:
+		return string(n.scalar), true
+	case len(n.items) != 0:
+		return "", false
+	case n.object:
+		return "{}", true
+	}
+	return "[]", true
+}
+
+// write writes collection with indent
+func (n *yamlNode) write(out *strings.Builder, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, item := range n.items {
+		prefix := pad + "- "
+		if n.object {
+			prefix = pad + yamlString(n.keys[i]) + ":"
+		}
+		if inline, ok := item.inline(); ok {
+			if n.object {
+				prefix += " "
+			}
+			out.WriteString(prefix + inline + "\n")
+			continue
+		}
+		if n.object {
+			out.WriteString(prefix + "\n")
+			item.write(out, indent+2)
+			continue
+		}
+		// first line of nested collection is written after dash
+		nested := &strings.Builder{}
+		item.write(nested, indent+2)
+		out.WriteString(prefix + strings.TrimPrefix(nested.String(), pad+"  "))
+	}
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func TestYAMLString(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "example.com", want: `example.com`},
+		{value: "https://example.com/a", want: `https://example.com/a`},
+		{value: "https:example.com:443", want: `https:example.com:443`},
+		{value: "купить слона", want: `купить слона`},
+		{value: "user@example.com", want: `user@example.com`},
+		{value: "", want: `""`},
+		// reserved words
+		{value: "yes", want: `"yes"`},
+		{value: "No", want: `"No"`},
+		{value: "ON", want: `"ON"`},
+		{value: "y", want: `"y"`},
+		{value: "true", want: `"true"`},
+		{value: "null", want: `"null"`},
+		{value: "Null", want: `"Null"`},
+		{value: "~", want: `"~"`},
+		{value: ".inf", want: `".inf"`},
+		{value: "-.Inf", want: `"-.Inf"`},
+		{value: ".NaN", want: `".NaN"`},
+		// numeric looking strings
+		{value: "42", want: `"42"`},
+		{value: "-1", want: `"-1"`},
+		{value: "+1", want: `"+1"`},
+		{value: "1.5", want: `"1.5"`},
+		{value: ".5", want: `".5"`},
+		{value: "1e10", want: `"1e10"`},
+		{value: "1_000", want: `"1_000"`},
+		{value: "0x1F", want: `"0x1F"`},
+		{value: "0o17", want: `"0o17"`},
+		{value: "0b101", want: `"0b101"`},
+		{value: "12:30", want: `"12:30"`},
+		{value: "2026-10-19", want: `"2026-10-19"`},
+		{value: "1a", want: `1a`},
+		// indicators
+		{value: "-a", want: `"-a"`},
+		{value: "- a", want: `"- a"`},
+		{value: "a-b", want: `a-b`},
+		{value: "a:b", want: `a:b`},
+		{value: "a: b", want: `"a: b"`},
+		{value: "a:", want: `"a:"`},
+		{value: ":a", want: `":a"`},
+		{value: "a #b", want: `"a #b"`},
+		{value: "#a", want: `"#a"`},
+		{value: "a#b", want: `"a#b"`},
+		{value: "a ", want: `"a "`},
+		{value: " a", want: `" a"`},
+		{value: "[a]", want: `"[a]"`},
+		{value: "{a}", want: `"{a}"`},
+		{value: "a, b", want: `"a, b"`},
+		{value: "*a", want: `"*a"`},
+		{value: "&a", want: `"&a"`},
+		{value: "!a", want: `"!a"`},
+		{value: "%a", want: `"%a"`},
+		{value: "|", want: `"|"`},
+		{value: ">", want: `">"`},
+		{value: "'a'", want: `"'a'"`},
+		{value: `a"b`, want: `"a\"b"`},
+		{value: "a\nb", want: `"a\nb"`},
+		{value: "a\tb", want: `"a\tb"`},
+		{value: "<a&b>", want: `"<a&b>"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := yamlString(tt.value); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteYAML(t *testing.T) {
+	type inner struct {
+		Name string   `json:"name"`
+		Tags []string `json:"tags"`
+	}
+	type outer struct {
+		ID       int               `json:"id"`
+		Ratio    float64           `json:"ratio"`
+		Enabled  bool              `json:"enabled"`
+		Missing  *inner            `json:"missing"`
+		Inner    inner             `json:"inner"`
+		Items    []inner           `json:"items"`
+		Empty    []string          `json:"empty"`
+		Nil      []string          `json:"nil"`
+		Map      map[string]int    `json:"map"`
+		EmptyMap map[string]string `json:"empty_map"`
+		Omitted  string            `json:"omitted,omitempty"`
+	}
+	tests := []struct {
+		name  string
+		value interface{}
+		want  string
+	}{
+		{name: "string", value: "yes", want: "\"yes\"\n"},
+		{name: "number", value: 42, want: "42\n"},
+		{name: "null", value: nil, want: "null\n"},
+		{name: "empty slice", value: []string{}, want: "[]\n"},
+		{name: "empty map", value: map[string]string{}, want: "{}\n"},
+		{name: "slice", value: []string{"a", "null", "-b"}, want: "- a\n- \"null\"\n- \"-b\"\n"},
+		{name: "map keys", value: map[string]int{"b": 2, "a: b": 1, "on": 3}, want: "\"a: b\": 1\nb: 2\n\"on\": 3\n"},
+		{name: "nested slices", value: [][]int{{1, 2}, {}, {3}}, want: "- - 1\n  - 2\n- []\n- - 3\n"},
+		{
+			name: "nested structs",
+			value: outer{
+				ID:    1,
+				Ratio: 0.25,
+				Inner: inner{Name: "inner", Tags: []string{"x", "1"}},
+				Items: []inner{{Name: "a", Tags: []string{}}, {Name: "b #2"}},
+				Empty: []string{},
+				Map:   map[string]int{"z": 1, "a": 2},
+				// empty map is written as {}
+				EmptyMap: map[string]string{},
+			},
+			want: `id: 1
+ratio: 0.25
+enabled: false
+missing: null
+inner:
+  name: inner
+  tags:
+    - x
+    - "1"
+items:
+  - name: a
+    tags: []
+  - name: "b #2"
+    tags: null
+empty: []
+nil: null
+map:
+  a: 2
+  z: 1
+empty_map: {}
+`,
+		},
+		{name: "raw json keeps key order", value: json.RawMessage(`{"b":1,"a":{"y":true,"x":[]}}`), want: "b: 1\na:\n  \"y\": true\n  x: []\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := writeYAML(&buf, tt.value); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("got:\n%s\nwant:\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteYAMLError(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeYAML(&buf, map[string]interface{}{"f": func() {}}); err == nil {
+		t.Errorf("expected error for unsupported value, got %q", buf.String())
+	}
+}
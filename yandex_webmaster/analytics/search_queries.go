@@ -0,0 +1,270 @@
+// Package analytics - helpers for reports over yandexwebmaster search query responses
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// Metric - value computed for search query
+type Metric string
+
+const (
+	MetricShows                   = Metric(ywm.QueryIndicatorTotalShows)
+	MetricClicks                  = Metric(ywm.QueryIndicatorTotalClicks)
+	MetricAvgShowPosition         = Metric(ywm.QueryIndicatorAvgShowPosition)
+	MetricAvgClickPosition        = Metric(ywm.QueryIndicatorAvgClickPosition)
+	MetricCTR              Metric = "CTR"
+)
+
+// LowerIsBetter reports whether smaller metric values are better, true for positions
+func (m Metric) LowerIsBetter() bool {
+	return m == MetricAvgShowPosition || m == MetricAvgClickPosition
+}
+
+// CTR returns clicks to shows ratio, zero without shows
+func CTR(clicks float64, shows float64) float64 {
+	if shows == 0 {
+		return 0
+	}
+	return clicks / shows
+}
+
+// QueryCTR returns click through rate of query
+func QueryCTR(q *ywm.PopularSearchQuery) float64 {
+	return CTR(q.Indicators.TotalClicks, q.Indicators.TotalShows)
+}
+
+// Value returns metric value of query
+func Value(q *ywm.PopularSearchQuery, m Metric) float64 {
+	switch m {
+	case MetricShows:
+		return q.Indicators.TotalShows
+	case MetricClicks:
+		return q.Indicators.TotalClicks
+	case MetricAvgShowPosition:
+		return q.Indicators.AvgShowPosition
+	case MetricAvgClickPosition:
+		return q.Indicators.AvgClickPosition
+	case MetricCTR:
+		return QueryCTR(q)
+	}
+	return 0
+}
+
+// Summary - aggregated indicators of queries
+type Summary struct {
+	Queries int
+	Shows   float64
+	Clicks  float64
+	CTR     float64
+	// AvgShowPosition - average show position weighted by shows
+	AvgShowPosition float64
+	// AvgClickPosition - average click position weighted by clicks
+	AvgClickPosition float64
+}
+
+// Summarize returns totals, CTR and weighted positions of queries
+func Summarize(queries []*ywm.PopularSearchQuery) Summary {
+	var s Summary
+	var showPositions, clickPositions float64
+	for _, q := range queries {
+		if q == nil {
+			continue
+		}
+		s.Queries++
+		s.Shows += q.Indicators.TotalShows
+		s.Clicks += q.Indicators.TotalClicks
+		showPositions += q.Indicators.AvgShowPosition * q.Indicators.TotalShows
+		clickPositions += q.Indicators.AvgClickPosition * q.Indicators.TotalClicks
+	}
+	s.CTR = CTR(s.Clicks, s.Shows)
+	if s.Shows != 0 {
+		s.AvgShowPosition = showPositions / s.Shows
+	}
+	if s.Clicks != 0 {
+		s.AvgClickPosition = clickPositions / s.Clicks
+	}
+	return s
+}
+
+// TopN returns n best queries by metric, positions are ordered ascending, other metrics descending.
+// Zero position means the query has no shows or clicks, such queries are ordered last.
+// All queries are returned if n is not positive.
+func TopN(queries []*ywm.PopularSearchQuery, m Metric, n int) []*ywm.PopularSearchQuery {
+	top := make([]*ywm.PopularSearchQuery, 0, len(queries))
+	for _, q := range queries {
+		if q != nil {
+			top = append(top, q)
+		}
+	}
+	sort.SliceStable(top, func(i, j int) bool {
+		vi, vj := Value(top[i], m), Value(top[j], m)
+		if m.LowerIsBetter() {
+			if vi == 0 || vj == 0 {
+				return vj == 0 && vi != 0
+			}
+			return vi < vj
+		}
+		return vi > vj
+	})
+	if n > 0 && n < len(top) {
+		top = top[:n]
+	}
+	return top
+}
+
+// QueryDelta - change of query indicators between periods
+type QueryDelta struct {
+	QueryID   string
+	QueryText string
+	Current   *ywm.PopularSearchQuery
+	Previous  *ywm.PopularSearchQuery
+	Shows     float64
+	Clicks    float64
+	CTR       float64
+	// AvgShowPosition - negative value means position improved
+	AvgShowPosition float64
+	// AvgClickPosition - negative value means position improved
+	AvgClickPosition float64
+}
+
+// Comparison - period over period comparison of search queries
+type Comparison struct {
+	Current  Summary
+	Previous Summary
+	// Delta - current summary minus previous summary
+	Delta Summary
+	// Changed - queries present in both periods
+	Changed []*QueryDelta
+	// New - queries present only in current period
+	New []*ywm.PopularSearchQuery
+	// Lost - queries present only in previous period
+	Lost []*ywm.PopularSearchQuery
+}
+
+// queryKey returns query identity, query text is used when id is missing
+func queryKey(q *ywm.PopularSearchQuery) string {
+	if q.QueryID != "" {
+		return q.QueryID
+	}
+	return "text:" + q.QueryText
+}
+
+// Compare compares queries of current period with previous one, both lists must be complete:
+// a query missing from a truncated list is reported as new or lost
+func Compare(current []*ywm.PopularSearchQuery, previous []*ywm.PopularSearchQuery) Comparison {
+	c := Comparison{Current: Summarize(current), Previous: Summarize(previous)}
+	c.Delta = Summary{
+		Queries:          c.Current.Queries - c.Previous.Queries,
+		Shows:            c.Current.Shows - c.Previous.Shows,
+		Clicks:           c.Current.Clicks - c.Previous.Clicks,
+		CTR:              c.Current.CTR - c.Previous.CTR,
+		AvgShowPosition:  c.Current.AvgShowPosition - c.Previous.AvgShowPosition,
+		AvgClickPosition: c.Current.AvgClickPosition - c.Previous.AvgClickPosition,
+	}
+	previousByKey := make(map[string]*ywm.PopularSearchQuery, len(previous))
+	for _, q := range previous {
+		if q != nil {
+			previousByKey[queryKey(q)] = q
+		}
+	}
+	seen := make(map[string]bool, len(current))
+	for _, q := range current {
+		if q == nil {
+			continue
+		}
+		key := queryKey(q)
+		seen[key] = true
+		prev, ok := previousByKey[key]
+		if !ok {
+			c.New = append(c.New, q)
+			continue
+		}
+		c.Changed = append(c.Changed, &QueryDelta{
+			QueryID:          q.QueryID,
+			QueryText:        q.QueryText,
+			Current:          q,
+			Previous:         prev,
+			Shows:            q.Indicators.TotalShows - prev.Indicators.TotalShows,
+			Clicks:           q.Indicators.TotalClicks - prev.Indicators.TotalClicks,
+			CTR:              QueryCTR(q) - QueryCTR(prev),
+			AvgShowPosition:  q.Indicators.AvgShowPosition - prev.Indicators.AvgShowPosition,
+			AvgClickPosition: q.Indicators.AvgClickPosition - prev.Indicators.AvgClickPosition,
+		})
+	}
+	for _, q := range previous {
+		if q != nil && !seen[queryKey(q)] {
+			c.Lost = append(c.Lost, q)
+		}
+	}
+	return c
+}
+
+// ErrInvalidPeriod - period has zero dates or starts after its end
+var ErrInvalidPeriod = errors.New("invalid period")
+
+// PreviousPeriod returns range of the same length right before [from, to], dates are inclusive
+func PreviousPeriod(from time.Time, to time.Time) (time.Time, time.Time, error) {
+	if from.IsZero() || to.IsZero() {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: both dates are required", ErrInvalidPeriod)
+	}
+	if ywm.DateOf(from).After(ywm.DateOf(to).Time) {
+		return time.Time{}, time.Time{}, fmt.Errorf("%w: %s is after %s", ErrInvalidPeriod, ywm.DateOf(from), ywm.DateOf(to))
+	}
+	days := int(ywm.DateOf(to).Sub(ywm.DateOf(from).Time).Hours()/24) + 1
+	prevTo := from.AddDate(0, 0, -1)
+	return prevTo.AddDate(0, 0, -(days - 1)), prevTo, nil
+}
+
+// popularPageSize - max limit of popular queries request
+const popularPageSize = 500
+
+// AllPopularQueries fetches all popular queries of req page by page, Limit and Offset of req are ignored
+func AllPopularQueries(s ywm.SearchQueryAPI, hostID ywm.HostID, req ywm.PopularQueriesRequest) ([]*ywm.PopularSearchQuery, error) {
+	return PopularQueries(s, hostID, req, 0)
+}
+
+// PopularQueries fetches first limit popular queries of req page by page, all queries if limit is zero.
+// Limit and Offset of req are ignored
+func PopularQueries(s ywm.SearchQueryAPI, hostID ywm.HostID, req ywm.PopularQueriesRequest, limit int) ([]*ywm.PopularSearchQuery, error) {
+	var queries []*ywm.PopularSearchQuery
+	for {
+		req.Offset, req.Limit = len(queries), popularPageSize
+		if limit > 0 && limit-len(queries) < popularPageSize {
+			req.Limit = limit - len(queries)
+		}
+		page, err := s.GetPopularSearchQueries(hostID, req)
+		if err != nil {
+			return nil, err
+		}
+		queries = append(queries, page.Queries...)
+		if len(page.Queries) == 0 || len(queries) >= page.Count || (limit > 0 && len(queries) >= limit) {
+			return queries, nil
+		}
+	}
+}
+
+// ComparePeriods fetches all popular queries for req period and previous period of the same length and compares them,
+// Limit and Offset of req are ignored because new and lost queries are only known from complete lists
+func ComparePeriods(s ywm.SearchQueryAPI, hostID ywm.HostID, req ywm.PopularQueriesRequest) (Comparison, error) {
+	prevReq := req
+	var err error
+	prevReq.DateFrom, prevReq.DateTo, err = PreviousPeriod(req.DateFrom, req.DateTo)
+	if err != nil {
+		return Comparison{}, err
+	}
+	current, err := AllPopularQueries(s, hostID, req)
+	if err != nil {
+		return Comparison{}, err
+	}
+	previous, err := AllPopularQueries(s, hostID, prevReq)
+	if err != nil {
+		return Comparison{}, err
+	}
+	return Compare(current, previous), nil
+}
@@ -0,0 +1,204 @@
+package analytics
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func query(id string, shows, clicks, position float64) *ywm.PopularSearchQuery {
+	return &ywm.PopularSearchQuery{QueryID: id, QueryText: "text " + id, Indicators: ywm.SearchIndicator{
+		TotalShows: shows, TotalClicks: clicks, AvgShowPosition: position, AvgClickPosition: position,
+	}}
+}
+
+func queryIDs(queries []*ywm.PopularSearchQuery) []string {
+	ids := []string{}
+	for _, q := range queries {
+		ids = append(ids, q.QueryID)
+	}
+	return ids
+}
+
+func TestTopN(t *testing.T) {
+	queries := []*ywm.PopularSearchQuery{
+		query("a", 100, 1, 5.5),
+		nil,
+		query("b", 300, 30, 0),
+		query("c", 200, 40, 1.2),
+		query("d", 50, 10, 0),
+		query("e", 10, 5, 9),
+	}
+	tests := []struct {
+		name   string
+		metric Metric
+		n      int
+		want   []string
+	}{
+		{name: "shows", metric: MetricShows, n: 2, want: []string{"b", "c"}},
+		{name: "clicks all", metric: MetricClicks, n: 0, want: []string{"c", "b", "d", "e", "a"}},
+		{name: "ctr ties keep order", metric: MetricCTR, n: 3, want: []string{"e", "c", "d"}},
+		{name: "position ascending, zero last", metric: MetricAvgShowPosition, n: 0, want: []string{"c", "a", "e", "b", "d"}},
+		{name: "position top", metric: MetricAvgClickPosition, n: 3, want: []string{"c", "a", "e"}},
+		{name: "n larger than list", metric: MetricShows, n: 10, want: []string{"b", "c", "a", "d", "e"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := queryIDs(TopN(queries, tt.metric, tt.n))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TopN() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	got := Summarize([]*ywm.PopularSearchQuery{query("a", 100, 10, 2), nil, query("b", 300, 0, 4)})
+	want := Summary{Queries: 2, Shows: 400, Clicks: 10, CTR: 0.025, AvgShowPosition: 3.5, AvgClickPosition: 2}
+	if got != want {
+		t.Errorf("Summarize() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	current := []*ywm.PopularSearchQuery{query("a", 120, 12, 2), query("new", 10, 1, 8), {QueryText: "no id", Indicators: ywm.SearchIndicator{TotalShows: 5}}}
+	previous := []*ywm.PopularSearchQuery{query("a", 100, 10, 3), query("lost", 40, 4, 6), {QueryText: "no id", Indicators: ywm.SearchIndicator{TotalShows: 7}}}
+	c := Compare(current, previous)
+	if got := queryIDs(c.New); !reflect.DeepEqual(got, []string{"new"}) {
+		t.Errorf("New = %v", got)
+	}
+	if got := queryIDs(c.Lost); !reflect.DeepEqual(got, []string{"lost"}) {
+		t.Errorf("Lost = %v", got)
+	}
+	if len(c.Changed) != 2 {
+		t.Fatalf("Changed = %+v", c.Changed)
+	}
+	a := c.Changed[0]
+	if a.QueryID != "a" || a.Shows != 20 || a.Clicks != 2 || a.AvgShowPosition != -1 || a.CTR != 0 {
+		t.Errorf("delta of a = %+v", a)
+	}
+	if noID := c.Changed[1]; noID.QueryText != "no id" || noID.Shows != -2 {
+		t.Errorf("delta of query without id = %+v", noID)
+	}
+	if c.Delta.Queries != 0 || c.Delta.Shows != 135-147 {
+		t.Errorf("Delta = %+v", c.Delta)
+	}
+}
+
+func TestPreviousPeriod(t *testing.T) {
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		from, to time.Time
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "28 days", from: day(9, 1), to: day(9, 28), wantFrom: day(8, 4), wantTo: day(8, 31)},
+		{name: "single day", from: day(9, 1), to: day(9, 1), wantFrom: day(8, 31), wantTo: day(8, 31)},
+		{name: "time of day is ignored", from: day(9, 1).Add(23 * time.Hour), to: day(9, 2), wantFrom: day(8, 30).Add(23 * time.Hour), wantTo: day(8, 31).Add(23 * time.Hour)},
+		{name: "zero from", to: day(9, 1), wantErr: true},
+		{name: "zero to", from: day(9, 1), wantErr: true},
+		{name: "reversed", from: day(9, 2), to: day(9, 1), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := PreviousPeriod(tt.from, tt.to)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPeriod) {
+					t.Errorf("PreviousPeriod() error = %v, want ErrInvalidPeriod", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("PreviousPeriod() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+// pagedQueries serves popular queries by period start with api paging
+type pagedQueries struct {
+	ywm.SearchQueryAPI
+	queries map[string][]*ywm.PopularSearchQuery
+	calls   int
+}
+
+func (p *pagedQueries) GetPopularSearchQueries(hostID ywm.HostID, req ywm.PopularQueriesRequest) (ywm.PopularSeachQueryResponse, error) {
+	p.calls++
+	all := p.queries[ywm.DateOf(req.DateFrom).String()]
+	end := req.Offset + req.Limit
+	if end > len(all) {
+		end = len(all)
+	}
+	return ywm.PopularSeachQueryResponse{Queries: all[req.Offset:end], Count: len(all)}, nil
+}
+
+func TestComparePeriods(t *testing.T) {
+	var current, previous []*ywm.PopularSearchQuery
+	for i := 0; i < 700; i++ {
+		id := string(rune('a'+i/26%26)) + string(rune('a'+i%26)) + string(rune('0'+i/676))
+		current = append(current, query(id, float64(1000-i), 1, 1))
+		if i != 0 {
+			previous = append(previous, query(id, float64(1000-i), 1, 1))
+		}
+	}
+	previous = append(previous, query("lost", 1, 0, 1))
+	s := &pagedQueries{queries: map[string][]*ywm.PopularSearchQuery{"2026-09-01": current, "2026-08-25": previous}}
+	req := ywm.PopularQueriesRequest{DateFrom: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), DateTo: time.Date(2026, 9, 7, 0, 0, 0, 0, time.UTC), Limit: 10}
+	c, err := ComparePeriods(s, "https:example.com:443", req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.calls != 4 {
+		t.Errorf("calls = %d, want 4", s.calls)
+	}
+	if len(c.Changed) != 699 || len(c.New) != 1 || len(c.Lost) != 1 || c.Lost[0].QueryID != "lost" {
+		t.Errorf("Compare() changed %d, new %d, lost %d", len(c.Changed), len(c.New), len(c.Lost))
+	}
+
+	if _, err := ComparePeriods(s, "https:example.com:443", ywm.PopularQueriesRequest{}); !errors.Is(err, ErrInvalidPeriod) {
+		t.Errorf("ComparePeriods() without dates error = %v", err)
+	}
+}
+
+func TestPopularQueries(t *testing.T) {
+	var all []*ywm.PopularSearchQuery
+	for i := 0; i < 1200; i++ {
+		all = append(all, query(string(rune('a'+i%26))+string(rune('0'+i/26)), float64(1200-i), 1, 1))
+	}
+	req := ywm.PopularQueriesRequest{DateFrom: time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), Offset: 5, Limit: 3}
+	tests := []struct {
+		name      string
+		limit     int
+		wantLen   int
+		wantCalls int
+	}{
+		{name: "all", limit: 0, wantLen: 1200, wantCalls: 3},
+		{name: "first page", limit: 10, wantLen: 10, wantCalls: 1},
+		{name: "page size", limit: 500, wantLen: 500, wantCalls: 1},
+		{name: "several pages", limit: 700, wantLen: 700, wantCalls: 2},
+		{name: "above count", limit: 5000, wantLen: 1200, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &pagedQueries{queries: map[string][]*ywm.PopularSearchQuery{"2026-09-01": all}}
+			got, err := PopularQueries(s, "https:example.com:443", req, tt.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, all[:tt.wantLen]) {
+				t.Errorf("got %d queries, want first %d", len(got), tt.wantLen)
+			}
+			if s.calls != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", s.calls, tt.wantCalls)
+			}
+		})
+	}
+}
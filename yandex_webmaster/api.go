@@ -0,0 +1,124 @@
+package yandexwebmaster
+
+// HostsAPI - methods of HostService
+type HostsAPI interface {
+	GetHosts() (Hosts, error)
+	GetHost(hostID HostID) (Host, error)
+	AddHost(hostURL string) (CreatedHost, error)
+	DeleteHost(hostID HostID) (interface{}, error)
+}
+
+// SitemapsAPI - methods of SitemapService
+type SitemapsAPI interface {
+	GetSitemaps(hostID HostID, req SitemapsRequest) (Sitemaps, error)
+	GetSitemap(hostID HostID, sitemapID string) (Sitemap, error)
+	GetUserAddedSitemaps(hostID HostID, req UserAddedSitemapsRequest) (AddedUserSitemaps, error)
+	GetUserAddedSitemap(hostID HostID, sitemapID string) (AddedUserSitemap, error)
+	AddSitemap(hostID HostID, url string) (AddedSitemap, error)
+	DeleteSitemap(hostID HostID, sitemapID string) (interface{}, error)
+}
+
+// IndexingAPI - methods of IndexingService
+type IndexingAPI interface {
+	GetIndexingHistory(hostID HostID, req IndexingHistoryRequest) (IndexingHistory, error)
+	GetIndexingSamples(hostID HostID, req IndexingSamplesRequest) (SamplesResult, error)
+}
+
+// ImportantURLAPI - methods of ImportantURLService
+type ImportantURLAPI interface {
+	GetMonitoringImportantURLS(hostID HostID) (ImportantURLS, error)
+	GetImportantURLHistory(hostID HostID, url string) (ImportantURLSHistory, error)
+	GetImportantURLChanges(hostID HostID, url string) ([]ImportantURLDiff, error)
+}
+
+// InsearchURLAPI - methods of InsearchURLService
+type InsearchURLAPI interface {
+	GetInsearchURLHistory(hostID HostID, req InsearchURLHistoryRequest) (InseacrhURLHistory, error)
+	GetInsearchURLSamples(hostID HostID, req InsearchURLSamplesRequest) (InsearchSampleResponse, error)
+	GetInsearchURLEventsHistory(hostID HostID, req InsearchURLHistoryRequest) (SearchURLEventHistoryResponse, error)
+	GetInsearchURLEventSamples(hostID HostID, req InsearchURLSamplesRequest) (InsearchEventSampleResponse, error)
+}
+
+// RecrawlAPI - methods of RecrawlService
+type RecrawlAPI interface {
+	RecrawlURL(hostID HostID, url string) (RecrawlURLResponse, error)
+	GetRecrawlTask(hostID HostID, taskID string) (RecrawlTask, error)
+	GetRecrawlTasks(hostID HostID, req RecrawlTasksRequest) (RecrawlTasks, error)
+	GetRecrawlQuota(hostID HostID) (RecrawlQuota, error)
+}
+
+// SearchQueryAPI - methods of SearchQueryService
+type SearchQueryAPI interface {
+	GetPopularSearchQueries(hostID HostID, req PopularQueriesRequest) (PopularSeachQueryResponse, error)
+	GetQueryAllHistory(hostID HostID, req QueryHistoryRequest) (SearchAllHistoryResponse, error)
+	GetSingleSearchQueryHistory(hostID HostID, queryID string, req QueryHistoryRequest) (SearchSingleHistoryResponse, error)
+}
+
+// DiagnosticAPI - methods of DiagnosticService
+type DiagnosticAPI interface {
+	GetDiagnositcs(hostID HostID) (DiagnosticProblemsResponse, error)
+}
+
+// API - all api services, implemented by Client, accept it in code which should work with fakes
+type API interface {
+	HostsAPI() HostsAPI
+	SitemapsAPI() SitemapsAPI
+	IndexingAPI() IndexingAPI
+	ImportantURLAPI() ImportantURLAPI
+	InsearchURLAPI() InsearchURLAPI
+	RecrawlAPI() RecrawlAPI
+	SearchQueryAPI() SearchQueryAPI
+	DiagnosticAPI() DiagnosticAPI
+}
+
+var (
+	_ API             = (*Client)(nil)
+	_ HostsAPI        = (*HostService)(nil)
+	_ SitemapsAPI     = (*SitemapService)(nil)
+	_ IndexingAPI     = (*IndexingService)(nil)
+	_ ImportantURLAPI = (*ImportantURLService)(nil)
+	_ InsearchURLAPI  = (*InsearchURLService)(nil)
+	_ RecrawlAPI      = (*RecrawlService)(nil)
+	_ SearchQueryAPI  = (*SearchQueryService)(nil)
+	_ DiagnosticAPI   = (*DiagnosticService)(nil)
+)
+
+// HostsAPI returns Hosts service
+func (c *Client) HostsAPI() HostsAPI {
+	return c.Hosts
+}
+
+// SitemapsAPI returns Sitemaps service
+func (c *Client) SitemapsAPI() SitemapsAPI {
+	return c.Sitemaps
+}
+
+// IndexingAPI returns Indexing service
+func (c *Client) IndexingAPI() IndexingAPI {
+	return c.Indexing
+}
+
+// ImportantURLAPI returns ImportantURL service
+func (c *Client) ImportantURLAPI() ImportantURLAPI {
+	return c.ImportantURL
+}
+
+// InsearchURLAPI returns InsearchURL service
+func (c *Client) InsearchURLAPI() InsearchURLAPI {
+	return c.InsearchURL
+}
+
+// RecrawlAPI returns Recrawl service
+func (c *Client) RecrawlAPI() RecrawlAPI {
+	return c.Recrawl
+}
+
+// SearchQueryAPI returns SearchQuery service
+func (c *Client) SearchQueryAPI() SearchQueryAPI {
+	return c.SearchQuery
+}
+
+// DiagnosticAPI returns Diagnostic service
+func (c *Client) DiagnosticAPI() DiagnosticAPI {
+	return c.Diagnostic
+}
@@ -0,0 +1,275 @@
+package yandexwebmaster
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrHistoryTooOld - window starts earlier than api keeps history, such windows are not requested
+var ErrHistoryTooOld = errors.New("date range is older than api history depth")
+
+// HistoryLimits - api caps of date range of single history request
+type HistoryLimits struct {
+	// MaxDays - widest range of single request in days, zero disables splitting
+	MaxDays int
+	// MaxAge - how many days back from today api keeps history, zero means unlimited
+	MaxAge int
+	// Concurrency - number of windows requested at once, one if not positive
+	Concurrency int
+}
+
+// DefaultHistoryLimits - limits used by NewClient. Api does not document range caps, so MaxDays and MaxAge are zero
+// and splitting is opt-in: set them with WithHistoryLimits. GetRecrawlTasks is never split
+var DefaultHistoryLimits = HistoryLimits{Concurrency: 4}
+
+// WithHistoryLimits - split history requests by limits instead of DefaultHistoryLimits
+func WithHistoryLimits(limits HistoryLimits) Option {
+	return func(c *Client) {
+		c.historyLimits = limits
+	}
+}
+
+// DateWindow - part of requested date range sent as single request, dates are inclusive
+type DateWindow struct {
+	From Date
+	To   Date
+}
+
+// String returns window as "from..to"
+func (w DateWindow) String() string {
+	return w.From.String() + ".." + w.To.String()
+}
+
+// WindowError - window of split request that failed
+type WindowError struct {
+	Window DateWindow
+	Err    error
+}
+
+// Error returns string representation of the WindowError
+func (e *WindowError) Error() string {
+	return fmt.Sprintf("window %s: %s", e.Window, e.Err)
+}
+
+// Unwrap returns error of window request
+func (e *WindowError) Unwrap() error {
+	return e.Err
+}
+
+// WindowsError - windows rejected while fetching split date range, result contains data of other windows
+type WindowsError struct {
+	Windows []*WindowError
+}
+
+// Error returns string representation of the WindowsError
+func (e *WindowsError) Error() string {
+	messages := make([]string, 0, len(e.Windows))
+	for _, err := range e.Windows {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Sprintf("%d date windows failed: %s", len(e.Windows), strings.Join(messages, "; "))
+}
+
+// historyWindow - window with local rejection reason
+type historyWindow struct {
+	DateWindow
+	skip error
+}
+
+// historyWindows splits [from, to] by client history limits in client location, range with zero date is not split
+func (c *Client) historyWindows(from time.Time, to time.Time) []historyWindow {
+	if from.IsZero() || to.IsZero() {
+		return []historyWindow{{DateWindow: DateWindow{From: DateIn(from, c.location), To: DateIn(to, c.location)}}}
+	}
+	start, end := DateIn(from, c.location), DateIn(to, c.location)
+	var windows []historyWindow
+	if c.historyLimits.MaxAge > 0 {
+		oldest := DateOf(DateIn(time.Now(), c.location).AddDate(0, 0, -c.historyLimits.MaxAge))
+		if start.Before(oldest.Time) {
+			skipTo := DateOf(oldest.AddDate(0, 0, -1))
+			if end.Before(skipTo.Time) {
+				skipTo = end
+			}
+			windows = append(windows, historyWindow{DateWindow: DateWindow{From: start, To: skipTo}, skip: ErrHistoryTooOld})
+			start = DateOf(skipTo.AddDate(0, 0, 1))
+		}
+	}
+	for !start.After(end.Time) {
+		windowEnd := end
+		if c.historyLimits.MaxDays > 0 {
+			if last := DateOf(start.AddDate(0, 0, c.historyLimits.MaxDays-1)); last.Before(end.Time) {
+				windowEnd = last
+			}
+		}
+		windows = append(windows, historyWindow{DateWindow: DateWindow{From: start, To: windowEnd}})
+		start = DateOf(windowEnd.AddDate(0, 0, 1))
+	}
+	return windows
+}
+
+// fetchWindows calls fetch for every window concurrently.
+// Error of single window request is returned as is, failures of split request are collected into WindowsError.
+func (c *Client) fetchWindows(windows []historyWindow, fetch func(i int, from time.Time, to time.Time) error) error {
+	if len(windows) == 1 {
+		w := windows[0]
+		if w.skip != nil {
+			return &WindowsError{Windows: []*WindowError{{Window: w.DateWindow, Err: w.skip}}}
+		}
+		return fetch(0, midnightIn(w.From, c.location), midnightIn(w.To, c.location))
+	}
+	concurrency := c.historyLimits.Concurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	errs := make([]error, len(windows))
+	sem := make(chan struct{}, concurrency)
+	wg := &sync.WaitGroup{}
+	for i, w := range windows {
+		if w.skip != nil {
+			errs[i] = w.skip
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, w historyWindow) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[i] = fetch(i, midnightIn(w.From, c.location), midnightIn(w.To, c.location))
+		}(i, w)
+	}
+	wg.Wait()
+	var failed []*WindowError
+	for i, err := range errs {
+		if err != nil {
+			failed = append(failed, &WindowError{Window: windows[i].DateWindow, Err: err})
+		}
+	}
+	if len(failed) != 0 {
+		return &WindowsError{Windows: failed}
+	}
+	return nil
+}
+
+// mergeByDate merges items of windows ordered by date, the first item of date wins
+func mergeByDate[T any](parts [][]*T, date func(*T) time.Time) []*T {
+	var merged []*T
+	for _, part := range parts {
+		for _, item := range part {
+			if item != nil {
+				merged = append(merged, item)
+			}
+		}
+	}
+	if merged == nil {
+		return nil
+	}
+	sort.SliceStable(merged, func(i, j int) bool {
+		return date(merged[i]).Before(date(merged[j]))
+	})
+	result := merged[:0]
+	for _, item := range merged {
+		if n := len(result); n == 0 || !date(result[n-1]).Equal(date(item)) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func indicatorDate(ind *Indicator) time.Time {
+	return ind.Date.Time
+}
+
+func queryIndicatorDataDate(item *SeachAllHistoryIndicatorData) time.Time {
+	return item.Date.Time
+}
+
+// mergeIndexingHistory merges indexing history of windows
+func mergeIndexingHistory(parts []IndexingHistory) IndexingHistory {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	var result IndexingHistory
+	byIndicator := make(map[IndexingIndicator][][]*Indicator)
+	for _, part := range parts {
+		if result.Extra == nil {
+			result.Extra = part.Extra
+		}
+		for indicator, values := range part.Indicators {
+			byIndicator[indicator] = append(byIndicator[indicator], values)
+		}
+	}
+	if len(byIndicator) != 0 {
+		result.Indicators = make(map[IndexingIndicator][]*Indicator, len(byIndicator))
+		for indicator, values := range byIndicator {
+			result.Indicators[indicator] = mergeByDate(values, indicatorDate)
+		}
+	}
+	return result
+}
+
+// mergeInsearchURLHistory merges in search urls history of windows
+func mergeInsearchURLHistory(parts []InseacrhURLHistory) InseacrhURLHistory {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	var result InseacrhURLHistory
+	var history [][]*InsearchURLHistoryData
+	for _, part := range parts {
+		if result.Extra == nil {
+			result.Extra = part.Extra
+		}
+		history = append(history, part.History)
+	}
+	result.History = mergeByDate(history, func(item *InsearchURLHistoryData) time.Time {
+		return item.Date.Time
+	})
+	return result
+}
+
+// mergeSearchURLEventHistory merges search events history of windows
+func mergeSearchURLEventHistory(parts []SearchURLEventHistoryResponse) SearchURLEventHistoryResponse {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	var result SearchURLEventHistoryResponse
+	var appeared, removed [][]*Indicator
+	for _, part := range parts {
+		if result.Extra == nil {
+			result.Extra = part.Extra
+			result.Indicators.Extra = part.Indicators.Extra
+		}
+		appeared = append(appeared, part.Indicators.AppeadINSearch)
+		removed = append(removed, part.Indicators.RemovedFromSearch)
+	}
+	result.Indicators.AppeadINSearch = mergeByDate(appeared, indicatorDate)
+	result.Indicators.RemovedFromSearch = mergeByDate(removed, indicatorDate)
+	return result
+}
+
+// mergeSearchAllHistory merges search queries history of windows
+func mergeSearchAllHistory(parts []SearchAllHistoryResponse) SearchAllHistoryResponse {
+	if len(parts) == 1 {
+		return parts[0]
+	}
+	var result SearchAllHistoryResponse
+	var shows, clicks, showPositions, clickPositions [][]*SeachAllHistoryIndicatorData
+	for _, part := range parts {
+		if result.Extra == nil {
+			result.Extra = part.Extra
+			result.Indicators.Extra = part.Indicators.Extra
+		}
+		shows = append(shows, part.Indicators.TotalShows)
+		clicks = append(clicks, part.Indicators.TotalClicks)
+		showPositions = append(showPositions, part.Indicators.AvgShowPosition)
+		clickPositions = append(clickPositions, part.Indicators.AvgClickPosition)
+	}
+	result.Indicators.TotalShows = mergeByDate(shows, queryIndicatorDataDate)
+	result.Indicators.TotalClicks = mergeByDate(clicks, queryIndicatorDataDate)
+	result.Indicators.AvgShowPosition = mergeByDate(showPositions, queryIndicatorDataDate)
+	result.Indicators.AvgClickPosition = mergeByDate(clickPositions, queryIndicatorDataDate)
+	return result
+}
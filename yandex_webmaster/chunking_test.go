@@ -0,0 +1,178 @@
+package yandexwebmaster
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func windowStrings(windows []historyWindow) []string {
+	res := []string{}
+	for _, w := range windows {
+		s := w.String()
+		if w.skip != nil {
+			s += " skip"
+		}
+		res = append(res, s)
+	}
+	return res
+}
+
+func TestHistoryWindows(t *testing.T) {
+	utc := func(month time.Month, day int) time.Time { return time.Date(2026, month, day, 0, 0, 0, 0, time.UTC) }
+	today := DateIn(time.Now(), time.UTC)
+	tests := []struct {
+		name     string
+		limits   HistoryLimits
+		from, to time.Time
+		want     []string
+	}{
+		{
+			name:   "default limits do not split",
+			limits: DefaultHistoryLimits,
+			from:   utc(1, 1), to: utc(9, 30),
+			want: []string{"2026-01-01..2026-09-30"},
+		},
+		{
+			name:   "split by max days",
+			limits: HistoryLimits{MaxDays: 10},
+			from:   utc(9, 1), to: utc(9, 25),
+			want: []string{"2026-09-01..2026-09-10", "2026-09-11..2026-09-20", "2026-09-21..2026-09-25"},
+		},
+		{
+			name:   "range equal to max days",
+			limits: HistoryLimits{MaxDays: 30},
+			from:   utc(9, 1), to: utc(9, 30),
+			want: []string{"2026-09-01..2026-09-30"},
+		},
+		{
+			name:   "zero date is not split",
+			limits: HistoryLimits{MaxDays: 10},
+			to:     utc(9, 30),
+			want:   []string{"..2026-09-30"},
+		},
+		{
+			name:   "old part is skipped",
+			limits: HistoryLimits{MaxAge: 5},
+			from:   today.AddDate(0, 0, -8), to: today.Time,
+			want: []string{
+				DateOf(today.AddDate(0, 0, -8)).String() + ".." + DateOf(today.AddDate(0, 0, -6)).String() + " skip",
+				DateOf(today.AddDate(0, 0, -5)).String() + ".." + today.String(),
+			},
+		},
+		{
+			name:   "whole range too old",
+			limits: HistoryLimits{MaxAge: 5},
+			from:   today.AddDate(0, 0, -20), to: today.AddDate(0, 0, -10),
+			want: []string{DateOf(today.AddDate(0, 0, -20)).String() + ".." + DateOf(today.AddDate(0, 0, -10)).String() + " skip"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &Client{location: time.UTC, historyLimits: tt.limits}
+			if got := windowStrings(c.historyWindows(tt.from, tt.to)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("historyWindows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHistoryWindowsInLocation(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	c := &Client{location: msk, historyLimits: HistoryLimits{MaxDays: 1}}
+	// 22:00 UTC is the next day in Moscow
+	got := windowStrings(c.historyWindows(time.Date(2026, 9, 1, 22, 0, 0, 0, time.UTC), time.Date(2026, 9, 2, 12, 0, 0, 0, time.UTC)))
+	if want := []string{"2026-09-02..2026-09-02"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("historyWindows() = %v, want %v", got, want)
+	}
+}
+
+func TestFetchWindows(t *testing.T) {
+	c := &Client{location: time.UTC, historyLimits: HistoryLimits{MaxDays: 10, Concurrency: 2}}
+	windows := c.historyWindows(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC))
+	failure := errors.New("boom")
+	var mu sync.Mutex
+	var starts []string
+	err := c.fetchWindows(windows, func(i int, from time.Time, to time.Time) error {
+		mu.Lock()
+		starts = append(starts, DateOf(from).String())
+		mu.Unlock()
+		if i == 1 {
+			return failure
+		}
+		return nil
+	})
+	if len(starts) != 3 {
+		t.Errorf("fetched windows %v", starts)
+	}
+	var windowsErr *WindowsError
+	if !errors.As(err, &windowsErr) || len(windowsErr.Windows) != 1 {
+		t.Fatalf("fetchWindows() error = %v", err)
+	}
+	if w := windowsErr.Windows[0]; w.Window.String() != "2026-09-11..2026-09-20" || !errors.Is(w, failure) {
+		t.Errorf("failed window = %v", w)
+	}
+
+	single := c.historyWindows(time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2026, 9, 5, 0, 0, 0, 0, time.UTC))
+	if err := c.fetchWindows(single, func(int, time.Time, time.Time) error { return failure }); err != failure {
+		t.Errorf("single window error = %v, want error as is", err)
+	}
+}
+
+func indicatorsAt(values map[int]int) []*Indicator {
+	res := []*Indicator{}
+	for day, value := range values {
+		res = append(res, &Indicator{Date: NewTimestamp(time.Date(2026, 9, day, 0, 0, 0, 0, time.UTC)), Value: value})
+	}
+	return res
+}
+
+func TestMergeByDate(t *testing.T) {
+	tests := []struct {
+		name  string
+		parts [][]*Indicator
+		want  [][2]int
+	}{
+		{name: "empty", parts: [][]*Indicator{nil, {}}, want: [][2]int{}},
+		{
+			name:  "ordered with nils",
+			parts: [][]*Indicator{indicatorsAt(map[int]int{3: 30}), {nil}, indicatorsAt(map[int]int{1: 10})},
+			want:  [][2]int{{1, 10}, {3, 30}},
+		},
+		{
+			name:  "first of date wins",
+			parts: [][]*Indicator{indicatorsAt(map[int]int{2: 20}), indicatorsAt(map[int]int{2: 99, 4: 40})},
+			want:  [][2]int{{2, 20}, {4, 40}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := [][2]int{}
+			for _, ind := range mergeByDate(tt.parts, indicatorDate) {
+				got = append(got, [2]int{ind.Date.Day(), ind.Value})
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("mergeByDate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMergeIndexingHistory(t *testing.T) {
+	parts := []IndexingHistory{
+		{Indicators: map[IndexingIndicator][]*Indicator{IndexingIndicatorHTTP2XX: indicatorsAt(map[int]int{1: 1})}},
+		{Indicators: map[IndexingIndicator][]*Indicator{
+			IndexingIndicatorHTTP2XX: indicatorsAt(map[int]int{2: 2}),
+			IndexingIndicatorHTTP4XX: indicatorsAt(map[int]int{2: 5}),
+		}},
+	}
+	merged := mergeIndexingHistory(parts)
+	if len(merged.Indicators[IndexingIndicatorHTTP2XX]) != 2 || len(merged.Indicators[IndexingIndicatorHTTP4XX]) != 1 {
+		t.Errorf("mergeIndexingHistory() = %+v", merged.Indicators)
+	}
+	if got := mergeIndexingHistory(parts[:1]); !reflect.DeepEqual(got, parts[0]) {
+		t.Errorf("single part = %+v", got)
+	}
+}
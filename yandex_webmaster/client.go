@@ -7,7 +7,10 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"reflect"
+	"strings"
 	"sync"
+	"time"
 )
 
 const (
@@ -30,10 +33,17 @@ func (e *YandexWebmasterError) Error() string {
 
 // Client to interact with YandexWebmasterAPI
 type Client struct {
-	client       *http.Client
-	token        string
-	userID       int
-	userIDLock   *sync.RWMutex
+	client     *http.Client
+	baseURL    string
+	token      string
+	userID     int
+	userIDLock *sync.RWMutex
+	recorder   *ResponseRecorder
+	strict     bool
+	// historyLimits - splitting of long history requests
+	historyLimits HistoryLimits
+	// location - reporting location of request and response dates
+	location     *time.Location
 	Hosts        *HostService
 	Sitemaps     *SitemapService
 	Indexing     *IndexingService
@@ -44,29 +54,67 @@ type Client struct {
 	Diagnostic   *DiagnosticService
 }
 
+// Option - Client option
+type Option func(*Client)
+
+// WithStrictDecoding - fail requests with UnknownFieldsError when response contains fields unknown to library
+func WithStrictDecoding() Option {
+	return func(c *Client) {
+		c.strict = true
+	}
+}
+
+// WithHTTPClient - use httpClient for api requests instead of http.DefaultClient
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.client = httpClient
+	}
+}
+
+// WithBaseURL - send api requests to baseURL instead of https://api.webmaster.yandex.net/v4/, e.g. to fake server
+func WithBaseURL(baseURL string) Option {
+	return func(c *Client) {
+		if !strings.HasSuffix(baseURL, "/") {
+			baseURL += "/"
+		}
+		c.baseURL = baseURL
+	}
+}
+
 // NewClient creates new Client to YandexWebmaster
-func NewClient(token string) (*Client, error) {
+func NewClient(token string, opts ...Option) (*Client, error) {
 	cl := &Client{
-		client:     http.DefaultClient,
-		token:      token,
-		userID:     0,
-		userIDLock: new(sync.RWMutex),
+		client:        http.DefaultClient,
+		baseURL:       apiBaseUrl,
+		token:         token,
+		userID:        0,
+		userIDLock:    new(sync.RWMutex),
+		historyLimits: DefaultHistoryLimits,
+		location:      DefaultLocation,
+	}
+	for _, opt := range opts {
+		opt(cl)
 	}
 	_, err := cl.getUserID()
 	if err != nil {
 		return nil, err
 	}
-	cl.Hosts = newHostService(cl)
-	cl.Sitemaps = newSitemapService(cl)
-	cl.Indexing = newIndexingService(cl)
-	cl.ImportantURL = newImportURLService(cl)
-	cl.InsearchURL = newInsearchURLService(cl)
-	cl.Recrawl = newRecrawlService(cl)
-	cl.SearchQuery = newSearchQueryService(cl)
-	cl.Diagnostic = newDiagnosticService(cl)
+	cl.initServices()
 	return cl, nil
 }
 
+// init api services
+func (c *Client) initServices() {
+	c.Hosts = newHostService(c)
+	c.Sitemaps = newSitemapService(c)
+	c.Indexing = newIndexingService(c)
+	c.ImportantURL = newImportURLService(c)
+	c.InsearchURL = newInsearchURLService(c)
+	c.Recrawl = newRecrawlService(c)
+	c.SearchQuery = newSearchQueryService(c)
+	c.Diagnostic = newDiagnosticService(c)
+}
+
 // get user id for api requests
 func (c *Client) getUserID() (int, error) {
 	c.userIDLock.RLock()
@@ -75,9 +123,7 @@ func (c *Client) getUserID() (int, error) {
 	if userID != 0 {
 		return userID, nil
 	}
-	var responseData struct {
-		UserID int `json:"user_id"`
-	}
+	var responseData User
 	endpoint := "user"
 	_, err := c.sendAPIRequest(http.MethodGet, endpoint, nil, &responseData)
 	if err != nil {
@@ -94,7 +140,7 @@ func (c *Client) getUserID() (int, error) {
 
 // base method for api requests
 func (c *Client) sendAPIRequest(method string, endpoint string, body interface{}, result interface{}) (*http.Response, error) {
-	fullPath := apiBaseUrl + endpoint
+	fullPath := c.baseURL + endpoint
 	// fmt.Println(fullPath)
 	var buf io.ReadWriter
 	if body != nil {
@@ -114,23 +160,40 @@ func (c *Client) sendAPIRequest(method string, endpoint string, body interface{}
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	start := time.Now()
 	resp, err := c.client.Do(req)
 	if err != nil {
+		if c.recorder != nil {
+			c.recorder.record(method, endpoint, nil, nil, time.Since(start))
+		}
 		return nil, &YandexWebmasterError{http.StatusServiceUnavailable, endpoint, "", err.Error()}
 	}
 
 	defer resp.Body.Close()
 	respBody, err := io.ReadAll(resp.Body)
+	if c.recorder != nil {
+		c.recorder.record(method, endpoint, resp, respBody, time.Since(start))
+	}
 	if err != nil {
 		return nil, &YandexWebmasterError{resp.StatusCode, endpoint, string(respBody), err.Error()}
 	}
-	if resp.StatusCode != http.StatusOK {
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
 		return nil, &YandexWebmasterError{resp.StatusCode, endpoint, string(respBody), ""}
 	}
+	// 201, 202 and 204 responses of mutations may have no body
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return resp, nil
+	}
 
 	if err := json.Unmarshal(respBody, &result); err != nil {
 		return nil, &YandexWebmasterError{resp.StatusCode, endpoint, string(respBody), err.Error()}
 	}
+	localizeTimestamps(result, c.location)
+	if c.strict {
+		if fields := collectExtraFields(result); len(fields) != 0 {
+			return nil, &UnknownFieldsError{Endpoint: endpoint, Fields: fields}
+		}
+	}
 
 	return resp, nil
 }
@@ -142,13 +205,49 @@ func (c *Client) generateURLWithGetParams(endpoint string, params map[string]int
 	}
 	query := url.Query()
 	for param, value := range params {
-		str := fmt.Sprintf("%v", value)
-		query.Add(param, str)
+		for _, str := range encodeParamValue(value, c.location) {
+			query.Add(param, str)
+		}
 	}
 	url.RawQuery = query.Encode()
 	return url.String(), nil
 }
 
+// encodeParamValue converts GET param value to list of query values.
+// Slices and arrays produce repeated keys, time.Time is formatted as date in loc,
+// fmt.Stringer and string based types are used as is, nil produces no values.
+func encodeParamValue(value interface{}, loc *time.Location) []string {
+	switch v := value.(type) {
+	case nil:
+		return nil
+	case string:
+		return []string{v}
+	case []string:
+		return v
+	case time.Time:
+		return []string{v.In(loc).Format(YYYYMMDD)}
+	case fmt.Stringer:
+		return []string{v.String()}
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		values := make([]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			values = append(values, encodeParamValue(rv.Index(i).Interface(), loc)...)
+		}
+		return values
+	case reflect.Pointer:
+		if rv.IsNil() {
+			return nil
+		}
+		return encodeParamValue(rv.Elem().Interface(), loc)
+	case reflect.String:
+		return []string{rv.String()}
+	}
+	return []string{fmt.Sprintf("%v", value)}
+}
+
 func (c *Client) makeGETRequestWithParams(endpoint string, params map[string]interface{}, result interface{}) (*http.Response, error) {
 	endpoint, err := c.generateURLWithGetParams(endpoint, params)
 	if err != nil {
@@ -157,3 +256,28 @@ func (c *Client) makeGETRequestWithParams(endpoint string, params map[string]int
 	r, err := c.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
 	return r, err
 }
+
+// userEndpoint returns endpoint of user resource, path is appended as is
+func (c *Client) userEndpoint(path string) string {
+	c.userIDLock.RLock()
+	endpoint := fmt.Sprintf("user/%d", c.userID)
+	c.userIDLock.RUnlock()
+	if path != "" {
+		endpoint += "/" + path
+	}
+	return endpoint
+}
+
+// hostEndpoint returns endpoint of host resource with validated and escaped host id,
+// path is appended as is, so dynamic path parts must be escaped by caller
+func (c *Client) hostEndpoint(hostID HostID, path string) (string, error) {
+	normalized, err := ParseHostID(string(hostID))
+	if err != nil {
+		return "", err
+	}
+	hostPath := "hosts/" + url.PathEscape(string(normalized))
+	if path != "" {
+		hostPath += "/" + path
+	}
+	return c.userEndpoint(hostPath), nil
+}
@@ -0,0 +1,107 @@
+package yandexwebmaster
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestEncodeParamValue(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	limit := 10
+	var nilLimit *int
+	tests := []struct {
+		name  string
+		value interface{}
+		want  []string
+	}{
+		{name: "nil", value: nil, want: nil},
+		{name: "string", value: "https://example.com/", want: []string{"https://example.com/"}},
+		{name: "string slice", value: []string{"a", "b"}, want: []string{"a", "b"}},
+		{name: "int", value: 42, want: []string{"42"}},
+		{name: "bool", value: true, want: []string{"true"}},
+		{name: "string type", value: QueryIndicatorTotalShows, want: []string{"TOTAL_SHOWS"}},
+		{name: "string type slice", value: []QueryIndicator{QueryIndicatorTotalShows, QueryIndicatorTotalClicks}, want: []string{"TOTAL_SHOWS", "TOTAL_CLICKS"}},
+		{name: "empty slice", value: []IndexingIndicator{}, want: []string{}},
+		{name: "array", value: [2]int{1, 2}, want: []string{"1", "2"}},
+		{name: "pointer", value: &limit, want: []string{"10"}},
+		{name: "nil pointer", value: nilLimit, want: nil},
+		{name: "time in location", value: time.Date(2026, 9, 30, 22, 30, 0, 0, time.UTC), want: []string{"2026-10-01"}},
+		{name: "time before midnight", value: time.Date(2026, 9, 30, 20, 59, 0, 0, time.UTC), want: []string{"2026-09-30"}},
+		{name: "stringer", value: NewDate(2026, 9, 1), want: []string{"2026-09-01"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := encodeParamValue(tt.value, msk)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("encodeParamValue(%#v) = %#v, want %#v", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateURLWithGetParams(t *testing.T) {
+	c := &Client{location: time.UTC}
+	got, err := c.generateURLWithGetParams("user/1/hosts/h/search-queries/popular", map[string]interface{}{
+		"query_indicator": []QueryIndicator{QueryIndicatorTotalShows, QueryIndicatorTotalClicks},
+		"date_from":       time.Date(2026, 9, 1, 0, 0, 0, 0, time.UTC),
+		"limit":           nil,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "user/1/hosts/h/search-queries/popular?date_from=2026-09-01&query_indicator=TOTAL_SHOWS&query_indicator=TOTAL_CLICKS"
+	if got != want {
+		t.Errorf("generateURLWithGetParams() = %q, want %q", got, want)
+	}
+}
+
+func TestSendAPIRequestStatus(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    User
+		wantErr bool
+	}{
+		{name: "ok", status: http.StatusOK, body: `{"user_id":7}`, want: User{UserID: 7}},
+		{name: "created", status: http.StatusCreated, body: `{"user_id":7}`, want: User{UserID: 7}},
+		{name: "accepted without body", status: http.StatusAccepted},
+		{name: "no content", status: http.StatusNoContent},
+		{name: "blank body", status: http.StatusOK, body: " \n"},
+		{name: "redirect", status: http.StatusMultipleChoices, body: `{}`, wantErr: true},
+		{name: "client error", status: http.StatusBadRequest, body: `{"error_code":"FIELD_VALIDATION_ERROR"}`, wantErr: true},
+		{name: "invalid json", status: http.StatusOK, body: `{`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+			client := &Client{client: server.Client(), baseURL: server.URL + "/", location: time.UTC}
+			var got User
+			_, err := client.sendAPIRequest(http.MethodGet, "user", nil, &got)
+			if tt.wantErr {
+				var apiErr *YandexWebmasterError
+				if err == nil {
+					t.Fatal("error is nil")
+				}
+				if tt.status != http.StatusOK && (!errors.As(err, &apiErr) || apiErr.HTTPCode != tt.status) {
+					t.Errorf("error = %v, want status %d", err, tt.status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("result = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,260 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// contractExample - response example of spec operation
+type contractExample struct {
+	operationID string
+	method      string
+	pattern     *regexp.Regexp
+	status      int
+	body        json.RawMessage
+}
+
+var contractPathParam = regexp.MustCompile(`\{[^}]+\}`)
+
+// loadContractExamples reads response examples of all operations of spec
+func loadContractExamples(path string) ([]*contractExample, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Responses   map[string]struct {
+				Content map[string]struct {
+					Example json.RawMessage `json:"example"`
+				} `json:"content"`
+			} `json:"responses"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	var examples []*contractExample
+	for path, methods := range spec.Paths {
+		quoted := regexp.QuoteMeta(contractPathParam.ReplaceAllString(path, "PARAM"))
+		pattern := regexp.MustCompile("^/v4" + strings.ReplaceAll(quoted, "PARAM", "[^/]+") + "$")
+		for method, op := range methods {
+			for code, resp := range op.Responses {
+				status, err := strconv.Atoi(code)
+				if err != nil {
+					return nil, fmt.Errorf("%s: invalid response code %q", op.OperationID, code)
+				}
+				e := &contractExample{operationID: op.OperationID, method: strings.ToUpper(method), pattern: pattern, status: status}
+				if content, ok := resp.Content["application/json"]; ok {
+					e.body = content.Example
+				}
+				examples = append(examples, e)
+			}
+		}
+	}
+	sort.Slice(examples, func(i, j int) bool {
+		return examples[i].operationID < examples[j].operationID
+	})
+	return examples, nil
+}
+
+var (
+	contractHostID   = HostID("https:example.com:443")
+	contractDateTo   = time.Now().AddDate(0, 0, -1)
+	contractDateFrom = contractDateTo.AddDate(0, 0, -30)
+)
+
+// contractCalls - calls of service methods by operation id, result is compared with example
+var contractCalls = map[string]func(c *Client) (interface{}, error){
+	"getHosts": func(c *Client) (interface{}, error) { return c.Hosts.GetHosts() },
+	"addHost":  func(c *Client) (interface{}, error) { return c.Hosts.AddHost("https://example.org") },
+	"getHost":  func(c *Client) (interface{}, error) { return c.Hosts.GetHost(contractHostID) },
+	"deleteHost": func(c *Client) (interface{}, error) {
+		_, err := c.Hosts.DeleteHost(contractHostID)
+		return nil, err
+	},
+	"getSitemaps": func(c *Client) (interface{}, error) {
+		return c.Sitemaps.GetSitemaps(contractHostID, SitemapsRequest{Limit: 10})
+	},
+	"getSitemap": func(c *Client) (interface{}, error) { return c.Sitemaps.GetSitemap(contractHostID, "c7-fe:80-c0") },
+	"addSitemap": func(c *Client) (interface{}, error) {
+		return c.Sitemaps.AddSitemap(contractHostID, "https://example.com/news.xml")
+	},
+	"getUserAddedSitemaps": func(c *Client) (interface{}, error) {
+		return c.Sitemaps.GetUserAddedSitemaps(contractHostID, UserAddedSitemapsRequest{Limit: 10})
+	},
+	"getUserAddedSitemap": func(c *Client) (interface{}, error) {
+		return c.Sitemaps.GetUserAddedSitemap(contractHostID, "c7-fe:80-c1")
+	},
+	"deleteSitemap": func(c *Client) (interface{}, error) {
+		_, err := c.Sitemaps.DeleteSitemap(contractHostID, "c7-fe:80-c1")
+		return nil, err
+	},
+	"getIndexingHistory": func(c *Client) (interface{}, error) {
+		return c.Indexing.GetIndexingHistory(contractHostID, IndexingHistoryRequest{DateFrom: contractDateFrom, DateTo: contractDateTo, Indicators: AllIndexingIndicators})
+	},
+	"getIndexingSamples": func(c *Client) (interface{}, error) {
+		return c.Indexing.GetIndexingSamples(contractHostID, IndexingSamplesRequest{Limit: 10})
+	},
+	"getImportantURLs": func(c *Client) (interface{}, error) {
+		return c.ImportantURL.GetMonitoringImportantURLS(contractHostID)
+	},
+	"getImportantURLHistory": func(c *Client) (interface{}, error) {
+		return c.ImportantURL.GetImportantURLHistory(contractHostID, "https://example.com/")
+	},
+	"getInsearchHistory": func(c *Client) (interface{}, error) {
+		return c.InsearchURL.GetInsearchURLHistory(contractHostID, InsearchURLHistoryRequest{DateFrom: contractDateFrom, DateTo: contractDateTo})
+	},
+	"getInsearchSamples": func(c *Client) (interface{}, error) {
+		return c.InsearchURL.GetInsearchURLSamples(contractHostID, InsearchURLSamplesRequest{Limit: 10})
+	},
+	"getSearchEventsHistory": func(c *Client) (interface{}, error) {
+		return c.InsearchURL.GetInsearchURLEventsHistory(contractHostID, InsearchURLHistoryRequest{DateFrom: contractDateFrom, DateTo: contractDateTo})
+	},
+	"getSearchEventsSamples": func(c *Client) (interface{}, error) {
+		return c.InsearchURL.GetInsearchURLEventSamples(contractHostID, InsearchURLSamplesRequest{Limit: 10})
+	},
+	"getRecrawlTasks": func(c *Client) (interface{}, error) {
+		return c.Recrawl.GetRecrawlTasks(contractHostID, RecrawlTasksRequest{DateFrom: contractDateFrom, DateTo: contractDateTo, Limit: 10})
+	},
+	"recrawlURL": func(c *Client) (interface{}, error) {
+		return c.Recrawl.RecrawlURL(contractHostID, "https://example.com/")
+	},
+	"getRecrawlTask": func(c *Client) (interface{}, error) { return c.Recrawl.GetRecrawlTask(contractHostID, "ab12") },
+	"getRecrawlQuota": func(c *Client) (interface{}, error) {
+		return c.Recrawl.GetRecrawlQuota(contractHostID)
+	},
+	"getPopularQueries": func(c *Client) (interface{}, error) {
+		return c.SearchQuery.GetPopularSearchQueries(contractHostID, PopularQueriesRequest{DateFrom: contractDateFrom, DateTo: contractDateTo, QueryIndicators: AllQueryIndicators, Limit: 10})
+	},
+	"getQueriesAllHistory": func(c *Client) (interface{}, error) {
+		return c.SearchQuery.GetQueryAllHistory(contractHostID, QueryHistoryRequest{DateFrom: contractDateFrom, DateTo: contractDateTo, QueryIndicators: AllQueryIndicators})
+	},
+	"getQueryHistory": func(c *Client) (interface{}, error) {
+		return c.SearchQuery.GetSingleSearchQueryHistory(contractHostID, "q1", QueryHistoryRequest{DateFrom: contractDateFrom, DateTo: contractDateTo, QueryIndicators: AllQueryIndicators})
+	},
+	"getDiagnostics": func(c *Client) (interface{}, error) { return c.Diagnostic.GetDiagnositcs(contractHostID) },
+}
+
+// containsJSON reports whether every value of example is present in decoded,
+// decoded may have additional zero fields for keys missing in example
+func containsJSON(decoded []byte, example []byte) bool {
+	var dv, ev interface{}
+	if json.Unmarshal(decoded, &dv) != nil || json.Unmarshal(example, &ev) != nil {
+		return false
+	}
+	return containsValue(dv, ev)
+}
+
+func containsValue(decoded interface{}, example interface{}) bool {
+	switch ev := example.(type) {
+	case map[string]interface{}:
+		dv, ok := decoded.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range ev {
+			if !containsValue(dv[key], value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		dv, ok := decoded.([]interface{})
+		if !ok || len(dv) != len(ev) {
+			return false
+		}
+		for i := range ev {
+			if !containsValue(dv[i], ev[i]) {
+				return false
+			}
+		}
+		return true
+	}
+	return reflect.DeepEqual(decoded, example)
+}
+
+// TestContract serves every response example of api/openapi.json and decodes it
+// with strict decoding through the service method of the operation
+func TestContract(t *testing.T) {
+	examples, err := loadContractExamples("api/openapi.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	hits := map[string]int{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, e := range examples {
+			if e.method != r.Method || !e.pattern.MatchString(r.URL.Path) {
+				continue
+			}
+			mu.Lock()
+			hits[e.operationID]++
+			mu.Unlock()
+			if e.body != nil {
+				w.Header().Set("Content-Type", "application/json")
+			}
+			w.WriteHeader(e.status)
+			w.Write(e.body)
+			return
+		}
+		http.Error(w, `{"error_code":"ENTITY_NOT_FOUND"}`, http.StatusNotFound)
+	}))
+	defer server.Close()
+	client, err := NewClient("token", WithStrictDecoding(), WithBaseURL(server.URL+"/v4/"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	covered := map[string]bool{"getUser": true}
+	for _, e := range examples {
+		e := e
+		if e.operationID == "getUser" {
+			continue
+		}
+		covered[e.operationID] = true
+		t.Run(e.operationID, func(t *testing.T) {
+			call, ok := contractCalls[e.operationID]
+			if !ok {
+				t.Fatal("no service method")
+			}
+			result, err := call(client)
+			if err != nil {
+				t.Fatal(err)
+			}
+			mu.Lock()
+			hit := hits[e.operationID]
+			mu.Unlock()
+			if hit == 0 {
+				t.Fatalf("request does not match %s %s", e.method, e.pattern)
+			}
+			if e.body == nil {
+				return
+			}
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !containsJSON(encoded, e.body) {
+				t.Errorf("decoded result %s differs from example %s", encoded, e.body)
+			}
+		})
+	}
+	for operationID := range contractCalls {
+		if !covered[operationID] {
+			t.Errorf("%s: operation has no example in spec", operationID)
+		}
+	}
+}
@@ -0,0 +1,350 @@
+package yandexwebmaster
+
+import (
+	"sort"
+)
+
+// DiagnosticSeverity - severity of site problem
+type DiagnosticSeverity string
+
+const (
+	DiagnosticSeverityFatal           DiagnosticSeverity = "FATAL"
+	DiagnosticSeverityCritical        DiagnosticSeverity = "CRITICAL"
+	DiagnosticSeverityPossibleProblem DiagnosticSeverity = "POSSIBLE_PROBLEM"
+	DiagnosticSeverityRecommendation  DiagnosticSeverity = "RECOMMENDATION"
+)
+
+// Level returns severity rank, higher is worse, unknown severities have zero level
+func (s DiagnosticSeverity) Level() int {
+	switch s {
+	case DiagnosticSeverityFatal:
+		return 4
+	case DiagnosticSeverityCritical:
+		return 3
+	case DiagnosticSeverityPossibleProblem:
+		return 2
+	case DiagnosticSeverityRecommendation:
+		return 1
+	}
+	return 0
+}
+
+// AtLeast reports whether severity is the same or worse than other
+func (s DiagnosticSeverity) AtLeast(other DiagnosticSeverity) bool {
+	return s.Level() >= other.Level()
+}
+
+// DiagnosticState - state of site problem
+type DiagnosticState string
+
+const (
+	DiagnosticStatePresent   DiagnosticState = "PRESENT"
+	DiagnosticStateAbsent    DiagnosticState = "ABSENT"
+	DiagnosticStateUndefined DiagnosticState = "UNDEFINED"
+)
+
+// IsPresent reports whether problem is present on site
+func (s DiagnosticState) IsPresent() bool {
+	return s == DiagnosticStatePresent
+}
+
+// DiagnosticProblemType - type of site problem, key of problems map in diagnostics response
+type DiagnosticProblemType string
+
+// problem types of catalog, api may return other types
+const (
+	// fatal
+	DiagnosticProblemConnectFailed      DiagnosticProblemType = "CONNECT_FAILED"
+	DiagnosticProblemDisallowedInRobots DiagnosticProblemType = "DISALLOWED_IN_ROBOTS"
+	DiagnosticProblemDNSError           DiagnosticProblemType = "DNS_ERROR"
+	DiagnosticProblemMainPageError      DiagnosticProblemType = "MAIN_PAGE_ERROR"
+	DiagnosticProblemThreats            DiagnosticProblemType = "THREATS"
+
+	// critical
+	DiagnosticProblemInsignificantCGIParameter DiagnosticProblemType = "INSIGNIFICANT_CGI_PARAMETER"
+	DiagnosticProblemSlowAvgResponseTime       DiagnosticProblemType = "SLOW_AVG_RESPONSE_TIME"
+	DiagnosticProblemSSLCertificateError       DiagnosticProblemType = "SSL_CERTIFICATE_ERROR"
+
+	// possible problems
+	DiagnosticProblemDocumentsMissingDescription  DiagnosticProblemType = "DOCUMENTS_MISSING_DESCRIPTION"
+	DiagnosticProblemDocumentsMissingTitle        DiagnosticProblemType = "DOCUMENTS_MISSING_TITLE"
+	DiagnosticProblemDuplicateContentAttrs        DiagnosticProblemType = "DUPLICATE_CONTENT_ATTRS"
+	DiagnosticProblemDuplicatePages               DiagnosticProblemType = "DUPLICATE_PAGES"
+	DiagnosticProblemErrorInRobotsTxt             DiagnosticProblemType = "ERROR_IN_ROBOTS_TXT"
+	DiagnosticProblemErrorsInSitemaps             DiagnosticProblemType = "ERRORS_IN_SITEMAPS"
+	DiagnosticProblemFaviconError                 DiagnosticProblemType = "FAVICON_ERROR"
+	DiagnosticProblemMainMirrorIsNotHTTPS         DiagnosticProblemType = "MAIN_MIRROR_IS_NOT_HTTPS"
+	DiagnosticProblemMainPageRedirects            DiagnosticProblemType = "MAIN_PAGE_REDIRECTS"
+	DiagnosticProblemNoMetrikaCounterBinding      DiagnosticProblemType = "NO_METRIKA_COUNTER_BINDING"
+	DiagnosticProblemNoMetrikaCounterCrawlEnabled DiagnosticProblemType = "NO_METRIKA_COUNTER_CRAWL_ENABLED"
+	DiagnosticProblemNoRobotsTxt                  DiagnosticProblemType = "NO_ROBOTS_TXT"
+	DiagnosticProblemNoSitemaps                   DiagnosticProblemType = "NO_SITEMAPS"
+	DiagnosticProblemNoSitemapModifications       DiagnosticProblemType = "NO_SITEMAP_MODIFICATIONS"
+	DiagnosticProblemNonWorkingVideo              DiagnosticProblemType = "NON_WORKING_VIDEO"
+	DiagnosticProblemSoft404                      DiagnosticProblemType = "SOFT_404"
+	DiagnosticProblemTooManyDomainsOnSearch       DiagnosticProblemType = "TOO_MANY_DOMAINS_ON_SEARCH"
+	DiagnosticProblemVideohostOfferFailed         DiagnosticProblemType = "VIDEOHOST_OFFER_FAILED"
+	DiagnosticProblemVideohostOfferIsNeeded       DiagnosticProblemType = "VIDEOHOST_OFFER_IS_NEEDED"
+	DiagnosticProblemVideohostOfferNeedsPaper     DiagnosticProblemType = "VIDEOHOST_OFFER_NEEDS_PAPER"
+
+	// recommendations
+	DiagnosticProblemFaviconProblem    DiagnosticProblemType = "FAVICON_PROBLEM"
+	DiagnosticProblemNoMetrikaCounter  DiagnosticProblemType = "NO_METRIKA_COUNTER"
+	DiagnosticProblemNoRegions         DiagnosticProblemType = "NO_REGIONS"
+	DiagnosticProblemNotInSprav        DiagnosticProblemType = "NOT_IN_SPRAV"
+	DiagnosticProblemNotMobileFriendly DiagnosticProblemType = "NOT_MOBILE_FRIENDLY"
+)
+
+// String returns problem type as is
+func (t DiagnosticProblemType) String() string {
+	return string(t)
+}
+
+// IsKnown reports whether problem type is described by catalog
+func (t DiagnosticProblemType) IsKnown() bool {
+	_, ok := diagnosticProblemCatalog[t]
+	return ok
+}
+
+// Language - language of catalog texts
+type Language string
+
+const (
+	LanguageRU Language = "ru"
+	LanguageEN Language = "en"
+)
+
+// LocalizedText - text in supported languages
+type LocalizedText struct {
+	RU string
+	EN string
+}
+
+// Get returns text in given language, english is used for unknown languages
+func (t LocalizedText) Get(lang Language) string {
+	if lang == LanguageRU {
+		return t.RU
+	}
+	return t.EN
+}
+
+// DiagnosticProblemInfo - description of site problem type
+type DiagnosticProblemInfo struct {
+	Type DiagnosticProblemType
+	// Severity - severity of problem type in catalog, severity of reported problem is returned by DiagnosticProblem.Info
+	Severity    DiagnosticSeverity
+	Description LocalizedText
+	Remediation LocalizedText
+	DocURL      string
+	// Known is false for problem types missing in catalog
+	Known bool
+}
+
+const (
+	diagnosticsDocURL = "https://yandex.ru/dev/webmaster/doc/dg/reference/host-diagnostics-get.html#response-format__ap-sites-problem-type"
+	robotsDocURL      = "https://yandex.ru/support/webmaster/controlling-robot/robots-txt.html"
+	sitemapDocURL     = "https://yandex.ru/support/webmaster/controlling-robot/sitemap.html"
+)
+
+// catalog of known site problem types, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-diagnostics-get.html
+var diagnosticProblemCatalog = map[DiagnosticProblemType]DiagnosticProblemInfo{
+	DiagnosticProblemConnectFailed: {
+		Severity:    DiagnosticSeverityFatal,
+		Description: LocalizedText{RU: "Не удалось подключиться к серверу", EN: "Failed to connect to the server"},
+		Remediation: LocalizedText{RU: "Проверьте доступность сервера и настройки файрвола для робота Яндекса.", EN: "Check that the server is reachable and the firewall does not block the Yandex robot."},
+	},
+	DiagnosticProblemDisallowedInRobots: {
+		Severity:    DiagnosticSeverityFatal,
+		Description: LocalizedText{RU: "Сайт закрыт к индексированию в robots.txt", EN: "Site is disallowed in robots.txt"},
+		Remediation: LocalizedText{RU: "Удалите запрещающие директивы Disallow для важных разделов в robots.txt.", EN: "Remove Disallow directives that block important sections in robots.txt."},
+		DocURL:      robotsDocURL,
+	},
+	DiagnosticProblemDNSError: {
+		Severity:    DiagnosticSeverityFatal,
+		Description: LocalizedText{RU: "Ошибка DNS", EN: "DNS error"},
+		Remediation: LocalizedText{RU: "Проверьте DNS-записи домена и доступность DNS-серверов.", EN: "Check the domain DNS records and that the DNS servers respond."},
+	},
+	DiagnosticProblemMainPageError: {
+		Severity:    DiagnosticSeverityFatal,
+		Description: LocalizedText{RU: "Главная страница возвращает ошибку", EN: "Main page returns an error"},
+		Remediation: LocalizedText{RU: "Убедитесь, что главная страница отвечает кодом 200 OK.", EN: "Make sure the main page responds with 200 OK."},
+	},
+	DiagnosticProblemThreats: {
+		Severity:    DiagnosticSeverityFatal,
+		Description: LocalizedText{RU: "Обнаружены угрозы безопасности или нарушения", EN: "Security threats or violations detected"},
+		Remediation: LocalizedText{RU: "Откройте раздел «Безопасность и нарушения», устраните проблемы и запросите перепроверку.", EN: "Open the Security and violations section, fix the issues and request a recheck."},
+	},
+	DiagnosticProblemInsignificantCGIParameter: {
+		Severity:    DiagnosticSeverityCritical,
+		Description: LocalizedText{RU: "Дубли страниц с незначащими GET-параметрами", EN: "Duplicate pages with insignificant GET parameters"},
+		Remediation: LocalizedText{RU: "Добавьте директиву Clean-param в robots.txt или укажите канонические адреса.", EN: "Add a Clean-param directive to robots.txt or set canonical URLs."},
+		DocURL:      robotsDocURL,
+	},
+	DiagnosticProblemSlowAvgResponseTime: {
+		Severity:    DiagnosticSeverityCritical,
+		Description: LocalizedText{RU: "Медленный ответ сервера", EN: "Slow average server response time"},
+		Remediation: LocalizedText{RU: "Оптимизируйте время ответа сервера и проверьте его нагрузку.", EN: "Optimize server response time and check the server load."},
+	},
+	DiagnosticProblemSSLCertificateError: {
+		Severity:    DiagnosticSeverityCritical,
+		Description: LocalizedText{RU: "Ошибка SSL-сертификата", EN: "SSL certificate error"},
+		Remediation: LocalizedText{RU: "Установите действительный сертификат с полной цепочкой и верным доменом.", EN: "Install a valid certificate with a full chain issued for the domain."},
+	},
+	DiagnosticProblemDocumentsMissingDescription: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "На страницах отсутствует meta description", EN: "Pages are missing meta description"},
+		Remediation: LocalizedText{RU: "Добавьте уникальный meta description на страницы.", EN: "Add a unique meta description to the pages."},
+	},
+	DiagnosticProblemDocumentsMissingTitle: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "На страницах отсутствует title", EN: "Pages are missing title"},
+		Remediation: LocalizedText{RU: "Добавьте уникальный элемент title на страницы.", EN: "Add a unique title element to the pages."},
+	},
+	DiagnosticProblemDuplicateContentAttrs: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Одинаковые title и description на разных страницах", EN: "Identical title and description on different pages"},
+		Remediation: LocalizedText{RU: "Сделайте title и description уникальными для каждой страницы.", EN: "Make title and description unique for each page."},
+	},
+	DiagnosticProblemDuplicatePages: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Дубли страниц", EN: "Duplicate pages"},
+		Remediation: LocalizedText{RU: "Настройте редиректы, rel=canonical или Clean-param для дублей.", EN: "Set up redirects, rel=canonical or Clean-param for duplicates."},
+	},
+	DiagnosticProblemErrorInRobotsTxt: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Ошибки в файле robots.txt", EN: "Errors in robots.txt"},
+		Remediation: LocalizedText{RU: "Проверьте robots.txt анализатором и исправьте ошибки.", EN: "Check robots.txt with the analyzer and fix the errors."},
+		DocURL:      robotsDocURL,
+	},
+	DiagnosticProblemErrorsInSitemaps: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Ошибки в файлах Sitemap", EN: "Errors in sitemap files"},
+		Remediation: LocalizedText{RU: "Проверьте файлы Sitemap валидатором и исправьте ошибки.", EN: "Validate the sitemap files and fix the errors."},
+		DocURL:      sitemapDocURL,
+	},
+	DiagnosticProblemFaviconError: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Ошибка загрузки favicon", EN: "Favicon is not available"},
+		Remediation: LocalizedText{RU: "Убедитесь, что файл favicon доступен и указан в разметке.", EN: "Make sure the favicon file is available and referenced in the markup."},
+	},
+	DiagnosticProblemMainMirrorIsNotHTTPS: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Главное зеркало не использует HTTPS", EN: "Main mirror does not use HTTPS"},
+		Remediation: LocalizedText{RU: "Настройте HTTPS и 301-редирект с HTTP-версии сайта.", EN: "Enable HTTPS and set up a 301 redirect from the HTTP version."},
+	},
+	DiagnosticProblemMainPageRedirects: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Главная страница перенаправляет на другой сайт", EN: "Main page redirects to another site"},
+		Remediation: LocalizedText{RU: "Проверьте, что редирект с главной страницы настроен намеренно.", EN: "Check that the main page redirect is intentional."},
+	},
+	DiagnosticProblemNoMetrikaCounterBinding: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Счётчик Метрики не привязан к сайту", EN: "Metrica counter is not linked to the site"},
+		Remediation: LocalizedText{RU: "Привяжите счётчик Яндекс Метрики в настройках Вебмастера.", EN: "Link a Yandex Metrica counter in Webmaster settings."},
+	},
+	DiagnosticProblemNoMetrikaCounterCrawlEnabled: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Обход по счётчику Метрики не включён", EN: "Crawling by Metrica counter is disabled"},
+		Remediation: LocalizedText{RU: "Включите обход страниц по счётчикам Метрики в настройках индексирования.", EN: "Enable crawling by Metrica counters in indexing settings."},
+	},
+	DiagnosticProblemNoRobotsTxt: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Отсутствует файл robots.txt", EN: "robots.txt is missing"},
+		Remediation: LocalizedText{RU: "Разместите файл robots.txt в корне сайта.", EN: "Place a robots.txt file in the site root."},
+		DocURL:      robotsDocURL,
+	},
+	DiagnosticProblemNoSitemaps: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Нет используемых роботом файлов Sitemap", EN: "No sitemap files used by the robot"},
+		Remediation: LocalizedText{RU: "Добавьте файл Sitemap в Вебмастер или укажите его в robots.txt.", EN: "Add a sitemap in Webmaster or declare it in robots.txt."},
+		DocURL:      sitemapDocURL,
+	},
+	DiagnosticProblemNoSitemapModifications: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Файлы Sitemap давно не обновлялись", EN: "Sitemap files have not been updated for a long time"},
+		Remediation: LocalizedText{RU: "Настройте автоматическое обновление файлов Sitemap.", EN: "Set up automatic sitemap regeneration."},
+		DocURL:      sitemapDocURL,
+	},
+	DiagnosticProblemNonWorkingVideo: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Не работает видео на страницах", EN: "Video on pages does not work"},
+		Remediation: LocalizedText{RU: "Проверьте доступность видеофайлов и плееров.", EN: "Check that video files and players are available."},
+	},
+	DiagnosticProblemSoft404: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Несуществующие страницы отвечают кодом 200", EN: "Missing pages respond with 200 (soft 404)"},
+		Remediation: LocalizedText{RU: "Настройте ответ 404 для несуществующих страниц.", EN: "Respond with 404 for missing pages."},
+	},
+	DiagnosticProblemTooManyDomainsOnSearch: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Много поддоменов в поиске", EN: "Too many subdomains in search"},
+		Remediation: LocalizedText{RU: "Проверьте, что поддомены созданы намеренно и не являются дублями.", EN: "Check that subdomains are intentional and are not duplicates."},
+	},
+	DiagnosticProblemVideohostOfferFailed: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Оферта видеохостинга не подтверждена", EN: "Video hosting offer was rejected"},
+		Remediation: LocalizedText{RU: "Исправьте замечания и повторно отправьте оферту видеохостинга.", EN: "Fix the remarks and resubmit the video hosting offer."},
+	},
+	DiagnosticProblemVideohostOfferIsNeeded: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Требуется оферта видеохостинга", EN: "Video hosting offer is required"},
+		Remediation: LocalizedText{RU: "Подключите оферту видеохостинга в Вебмастере.", EN: "Submit the video hosting offer in Webmaster."},
+	},
+	DiagnosticProblemVideohostOfferNeedsPaper: {
+		Severity:    DiagnosticSeverityPossibleProblem,
+		Description: LocalizedText{RU: "Для оферты видеохостинга нужен бумажный договор", EN: "Video hosting offer needs a paper agreement"},
+		Remediation: LocalizedText{RU: "Подпишите бумажный договор для оферты видеохостинга.", EN: "Sign the paper agreement for the video hosting offer."},
+	},
+	DiagnosticProblemFaviconProblem: {
+		Severity:    DiagnosticSeverityRecommendation,
+		Description: LocalizedText{RU: "Не найден favicon подходящего размера", EN: "No favicon of suitable size"},
+		Remediation: LocalizedText{RU: "Добавьте favicon в формате SVG или размером не менее 120×120.", EN: "Add an SVG favicon or one at least 120x120 in size."},
+	},
+	DiagnosticProblemNoMetrikaCounter: {
+		Severity:    DiagnosticSeverityRecommendation,
+		Description: LocalizedText{RU: "Нет счётчика Метрики", EN: "No Metrica counter"},
+		Remediation: LocalizedText{RU: "Установите счётчик Яндекс Метрики на сайт.", EN: "Install a Yandex Metrica counter on the site."},
+	},
+	DiagnosticProblemNoRegions: {
+		Severity:    DiagnosticSeverityRecommendation,
+		Description: LocalizedText{RU: "Не указан регион сайта", EN: "Site region is not set"},
+		Remediation: LocalizedText{RU: "Укажите регион сайта в Вебмастере.", EN: "Set the site region in Webmaster."},
+	},
+	DiagnosticProblemNotInSprav: {
+		Severity:    DiagnosticSeverityRecommendation,
+		Description: LocalizedText{RU: "Организация не добавлена в Яндекс Бизнес", EN: "Organization is not listed in Yandex Business"},
+		Remediation: LocalizedText{RU: "Добавьте организацию в Яндекс Бизнес.", EN: "Add the organization to Yandex Business."},
+	},
+	DiagnosticProblemNotMobileFriendly: {
+		Severity:    DiagnosticSeverityRecommendation,
+		Description: LocalizedText{RU: "Сайт не оптимизирован для мобильных устройств", EN: "Site is not mobile friendly"},
+		Remediation: LocalizedText{RU: "Адаптируйте вёрстку страниц для мобильных устройств.", EN: "Adapt page layout for mobile devices."},
+	},
+}
+
+// LookupDiagnosticProblem returns catalog entry for problem type.
+// Unknown types are returned as is with Known set to false.
+func LookupDiagnosticProblem(problemType DiagnosticProblemType) DiagnosticProblemInfo {
+	info, ok := diagnosticProblemCatalog[problemType]
+	if !ok {
+		return DiagnosticProblemInfo{Type: problemType, DocURL: diagnosticsDocURL}
+	}
+	info.Type = problemType
+	info.Known = true
+	if info.DocURL == "" {
+		info.DocURL = diagnosticsDocURL
+	}
+	return info
+}
+
+// DiagnosticProblemTypes returns all problem types known by catalog sorted by name
+func DiagnosticProblemTypes() []DiagnosticProblemType {
+	types := make([]DiagnosticProblemType, 0, len(diagnosticProblemCatalog))
+	for t := range diagnosticProblemCatalog {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
@@ -0,0 +1,88 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestDiagnosticProblemInfo(t *testing.T) {
+	tests := []struct {
+		name         string
+		problem      DiagnosticProblem
+		wantSeverity DiagnosticSeverity
+		wantKnown    bool
+	}{
+		{
+			name:         "api severity is kept",
+			problem:      DiagnosticProblem{Type: DiagnosticProblemNoSitemaps, Severity: DiagnosticSeverityCritical},
+			wantSeverity: DiagnosticSeverityCritical,
+			wantKnown:    true,
+		},
+		{
+			name:         "catalog severity without api severity",
+			problem:      DiagnosticProblem{Type: DiagnosticProblemConnectFailed},
+			wantSeverity: DiagnosticSeverityFatal,
+			wantKnown:    true,
+		},
+		{
+			name:         "catalog severity for unknown api severity",
+			problem:      DiagnosticProblem{Type: DiagnosticProblemNoRegions, Severity: "SOMETHING_NEW"},
+			wantSeverity: DiagnosticSeverityRecommendation,
+			wantKnown:    true,
+		},
+		{
+			name:         "unknown type",
+			problem:      DiagnosticProblem{Type: "NEW_PROBLEM", Severity: DiagnosticSeverityPossibleProblem},
+			wantSeverity: DiagnosticSeverityPossibleProblem,
+			wantKnown:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info := tt.problem.Info()
+			if info.Severity != tt.wantSeverity {
+				t.Errorf("Severity = %q, want %q", info.Severity, tt.wantSeverity)
+			}
+			if info.Known != tt.wantKnown {
+				t.Errorf("Known = %v, want %v", info.Known, tt.wantKnown)
+			}
+			if info.Type != tt.problem.Type {
+				t.Errorf("Type = %q, want %q", info.Type, tt.problem.Type)
+			}
+			if info.DocURL == "" {
+				t.Error("DocURL is empty")
+			}
+		})
+	}
+}
+
+func TestDiagnosticProblemTypes(t *testing.T) {
+	types := DiagnosticProblemTypes()
+	for i, problemType := range types {
+		info := LookupDiagnosticProblem(problemType)
+		if !problemType.IsKnown() || !info.Known || !info.Severity.IsKnown() {
+			t.Errorf("%s: incomplete catalog entry %+v", problemType, info)
+		}
+		if info.Description.EN == "" || info.Description.RU == "" {
+			t.Errorf("%s: missing description", problemType)
+		}
+		if i > 0 && types[i-1] >= problemType {
+			t.Errorf("types are not sorted: %s before %s", types[i-1], problemType)
+		}
+	}
+}
+
+func TestDiagnosticProblemsJSON(t *testing.T) {
+	var resp DiagnosticProblemsResponse
+	body := `{"problems":{"NO_SITEMAPS":{"severity":"POSSIBLE_PROBLEM","state":"PRESENT","last_state_update":"2026-09-01T10:00:00,000+0300"},
+		"CONNECT_FAILED":{"severity":"FATAL","state":"ABSENT"}}}`
+	if err := json.Unmarshal([]byte(body), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Problems) != 2 || resp.Problems[0].Type != DiagnosticProblemConnectFailed || resp.Problems[1].Type != DiagnosticProblemNoSitemaps {
+		t.Fatalf("problems = %+v", resp.Problems)
+	}
+	if !resp.Problems[1].State.IsPresent() || resp.Problems[0].State.IsPresent() {
+		t.Errorf("wrong states: %+v", resp.Problems)
+	}
+}
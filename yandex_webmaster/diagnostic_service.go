@@ -2,8 +2,8 @@ package yandexwebmaster
 
 import (
 	"encoding/json"
-	"fmt"
 	"net/http"
+	"sort"
 )
 
 // DiagnosticService - service for diagnostic
@@ -17,45 +17,52 @@ func newDiagnosticService(cl *Client) *DiagnosticService {
 }
 
 // GetDiagnositcs - get site diagnostics, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-diagnostics-get.html#response-format__ap-sites-problem-type
-func (s *DiagnosticService) GetDiagnositcs(hostID string) (DiagnosticProblemsResponse, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/diagnostics", s.client.userID, hostID)
+func (s *DiagnosticService) GetDiagnositcs(hostID HostID) (DiagnosticProblemsResponse, error) {
 	var result DiagnosticProblemsResponse
-	_, err := s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "diagnostics")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
 	return result, err
 }
 
 type DiagnosticProblem struct {
-	Type            string
-	Severity        string
-	State           string
-	LastStateUpdate string
+	// Type - problem type, key of problems map in response
+	Type            DiagnosticProblemType `json:"-"`
+	Severity        DiagnosticSeverity    `json:"severity"`
+	State           DiagnosticState       `json:"state"`
+	LastStateUpdate Timestamp             `json:"last_state_update"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
 }
 
-func (d *DiagnosticProblem) UnmarshalJSON(bytes []byte) error {
-	type raw struct {
-		Severity        string `json:"severity"`
-		State           string `json:"state"`
-		LastStateUpdate string `json:"last_state_update"`
+// Info returns catalog description of the problem.
+// Severity reported by api is kept, catalog severity is used only when api severity is unknown.
+func (d DiagnosticProblem) Info() DiagnosticProblemInfo {
+	info := LookupDiagnosticProblem(d.Type)
+	if d.Severity.IsKnown() || !info.Known {
+		info.Severity = d.Severity
 	}
-	var r raw
-	err := json.Unmarshal(bytes, &r)
-	if err != nil {
-		return err
-	}
-	d.Severity = r.Severity
-	d.State = r.State
-	d.LastStateUpdate = r.LastStateUpdate
-	return nil
+	return info
 }
 
-type DiagnosticProblems []DiagnosticProblem
+func (d *DiagnosticProblem) UnmarshalJSON(bytes []byte) error {
+	type plain DiagnosticProblem
+	extra, err := unmarshalWithExtra(bytes, (*plain)(d))
+	d.Extra = extra
+	return err
+}
 
-type DiagnosticProblemsResponse struct {
-	Problems DiagnosticProblems `json:"problems"`
+func (d DiagnosticProblem) MarshalJSON() ([]byte, error) {
+	type plain DiagnosticProblem
+	return marshalWithExtra(plain(d), d.Extra)
 }
 
+type DiagnosticProblems []DiagnosticProblem
+
 func (d *DiagnosticProblems) UnmarshalJSON(bytes []byte) error {
-	dpMap := make(map[string]DiagnosticProblem)
+	dpMap := make(map[DiagnosticProblemType]DiagnosticProblem)
 	err := json.Unmarshal(bytes, &dpMap)
 	if err != nil {
 		return err
@@ -65,7 +72,17 @@ func (d *DiagnosticProblems) UnmarshalJSON(bytes []byte) error {
 		p.Type = t
 		problems = append(problems, p)
 	}
+	sort.Slice(problems, func(i, j int) bool {
+		return problems[i].Type < problems[j].Type
+	})
 	*d = problems
 	return nil
+}
 
+func (d DiagnosticProblems) MarshalJSON() ([]byte, error) {
+	dpMap := make(map[DiagnosticProblemType]DiagnosticProblem, len(d))
+	for _, p := range d {
+		dpMap[p.Type] = p
+	}
+	return json.Marshal(dpMap)
 }
@@ -0,0 +1,159 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// cache of json field names by struct type
+var knownFieldsCache sync.Map
+
+// knownFields returns json names of struct fields
+func knownFields(t reflect.Type) map[string]bool {
+	if fields, ok := knownFieldsCache.Load(t); ok {
+		return fields.(map[string]bool)
+	}
+	fields := make(map[string]bool, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("json"); ok {
+			tagName := strings.Split(tag, ",")[0]
+			if tagName == "-" {
+				continue
+			}
+			if tagName != "" {
+				name = tagName
+			}
+		}
+		fields[name] = true
+	}
+	knownFieldsCache.Store(t, fields)
+	return fields
+}
+
+// unmarshalWithExtra decodes bytes to plain struct pointer v and returns fields unknown to v
+func unmarshalWithExtra(bytes []byte, v interface{}) (map[string]json.RawMessage, error) {
+	if err := json.Unmarshal(bytes, v); err != nil {
+		return nil, err
+	}
+	if string(bytes) == "null" {
+		return nil, nil
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return nil, err
+	}
+	fields := knownFields(reflect.TypeOf(v).Elem())
+	var extra map[string]json.RawMessage
+	for key, value := range raw {
+		if fields[key] || fieldsContainFold(fields, key) {
+			continue
+		}
+		if extra == nil {
+			extra = make(map[string]json.RawMessage)
+		}
+		extra[key] = value
+	}
+	return extra, nil
+}
+
+// fieldsContainFold reports whether key matches field case-insensitively as encoding/json does
+func fieldsContainFold(fields map[string]bool, key string) bool {
+	for field := range fields {
+		if strings.EqualFold(field, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// marshalWithExtra encodes plain struct v and appends extra fields
+func marshalWithExtra(v interface{}, extra map[string]json.RawMessage) ([]byte, error) {
+	bytes, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return bytes, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return nil, err
+	}
+	for key, value := range extra {
+		if _, ok := raw[key]; !ok {
+			raw[key] = value
+		}
+	}
+	return json.Marshal(raw)
+}
+
+// UnknownFieldsError - response contains fields unknown to library, returned in strict decoding mode
+type UnknownFieldsError struct {
+	Endpoint string
+	Fields   []string
+}
+
+// Error returns string representation of the UnknownFieldsError
+func (e *UnknownFieldsError) Error() string {
+	return fmt.Sprintf("endpoint: %s, unknown fields: %s", e.Endpoint, strings.Join(e.Fields, ", "))
+}
+
+var rawMessageMapType = reflect.TypeOf(map[string]json.RawMessage(nil))
+
+// collectExtraFields returns sorted paths of all unknown fields kept in Extra maps of v
+func collectExtraFields(v interface{}) []string {
+	var paths []string
+	walkExtraFields(reflect.ValueOf(v), "", &paths)
+	sort.Strings(paths)
+	return paths
+}
+
+func walkExtraFields(v reflect.Value, path string, paths *[]string) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkExtraFields(v.Elem(), path, paths)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkExtraFields(v.Index(i), fmt.Sprintf("%s[%d]", path, i), paths)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			walkExtraFields(iter.Value(), joinFieldPath(path, fmt.Sprint(iter.Key().Interface())), paths)
+		}
+	case reflect.Struct:
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			if f.Name == "Extra" && f.Type == rawMessageMapType {
+				for key := range v.Field(i).Interface().(map[string]json.RawMessage) {
+					*paths = append(*paths, joinFieldPath(path, key))
+				}
+				continue
+			}
+			name := f.Name
+			if tag := strings.Split(f.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+				name = tag
+			}
+			walkExtraFields(v.Field(i), joinFieldPath(path, name), paths)
+		}
+	}
+}
+
+func joinFieldPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,120 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestExtraRoundTrip(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		wantExtra []string
+		want      string
+	}{
+		{
+			name:  "known fields only",
+			input: `{"user_id":42}`,
+			want:  `{"user_id":42}`,
+		},
+		{
+			name:      "unknown fields are kept",
+			input:     `{"user_id":42,"login":"site-owner","flags":{"beta":true}}`,
+			wantExtra: []string{"flags", "login"},
+			want:      `{"flags":{"beta":true},"login":"site-owner","user_id":42}`,
+		},
+		{
+			name:  "case insensitive match is known",
+			input: `{"USER_ID":42}`,
+			want:  `{"user_id":42}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var user User
+			if err := json.Unmarshal([]byte(tt.input), &user); err != nil {
+				t.Fatal(err)
+			}
+			if user.UserID != 42 {
+				t.Errorf("UserID = %d", user.UserID)
+			}
+			if got := collectExtraFields(user); !reflect.DeepEqual(got, tt.wantExtra) {
+				t.Errorf("extra fields = %v, want %v", got, tt.wantExtra)
+			}
+			out, err := json.Marshal(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", out, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectExtraFieldsPaths(t *testing.T) {
+	var hosts Hosts
+	body := `{"hosts":[
+		{"host_id":"https:example.com:443","verified":true},
+		{"host_id":"https:example.org:443","owner":"x","main_mirror":{"host_id":"https:www.example.org:443","note":1}}
+	],"total":2}`
+	if err := json.Unmarshal([]byte(body), &hosts); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"hosts[1].main_mirror.note", "hosts[1].owner", "total"}
+	if got := collectExtraFields(&hosts); !reflect.DeepEqual(got, want) {
+		t.Errorf("collectExtraFields() = %v, want %v", got, want)
+	}
+}
+
+func TestStrictDecoding(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"user_id":1}`))
+		case "/user/1/hosts":
+			w.Write([]byte(`{"hosts":[{"host_id":"https:example.com:443","color":"red"}]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		strict bool
+	}{
+		{name: "lenient", opts: []Option{WithBaseURL(server.URL)}},
+		{name: "strict", opts: []Option{WithBaseURL(server.URL), WithStrictDecoding()}, strict: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient("token", tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			hosts, err := client.Hosts.GetHosts()
+			if !tt.strict {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(hosts.Hosts[0].Extra["color"]) != `"red"` {
+					t.Errorf("Extra = %v", hosts.Hosts[0].Extra)
+				}
+				return
+			}
+			var unknown *UnknownFieldsError
+			if !errors.As(err, &unknown) {
+				t.Fatalf("GetHosts() error = %v, want UnknownFieldsError", err)
+			}
+			if unknown.Endpoint != "user/1/hosts" || !reflect.DeepEqual(unknown.Fields, []string{"hosts[0].color"}) {
+				t.Errorf("UnknownFieldsError = %+v", unknown)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package yandexwebmaster
+
+// Request and response types are generated from api/openapi.json,
+// contract of spec examples and service methods is checked by TestContract.
+
+//go:generate go run ./internal/modelgen -spec api/openapi.json -out models_gen.go
@@ -0,0 +1,175 @@
+package yandexwebmaster
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// ErrInvalidHostID - returned for malformed host id before any request
+var ErrInvalidHostID = errors.New("invalid host id")
+
+// HostID - site identifier in form "scheme:ascii-host:port", e.g. "https:example.com:443"
+type HostID string
+
+// default ports of supported schemes
+var defaultPorts = map[string]int{
+	"http":  80,
+	"https": 443,
+}
+
+// NewHostID creates HostID from parts, unicode host is converted to punycode, zero port is replaced by scheme default
+func NewHostID(scheme string, host string, port int) (HostID, error) {
+	scheme = strings.ToLower(scheme)
+	defaultPort, ok := defaultPorts[scheme]
+	if !ok {
+		return "", fmt.Errorf("%w: unsupported scheme %q", ErrInvalidHostID, scheme)
+	}
+	if port == 0 {
+		port = defaultPort
+	}
+	if port < 0 || port > 65535 {
+		return "", fmt.Errorf("%w: invalid port %d", ErrInvalidHostID, port)
+	}
+	asciiHost, err := hostToASCII(strings.TrimSuffix(host, "."))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidHostID, err.Error())
+	}
+	if err := validateHostName(asciiHost); err != nil {
+		return "", err
+	}
+	return HostID(fmt.Sprintf("%s:%s:%d", scheme, asciiHost, port)), nil
+}
+
+// ParseHostID parses and normalizes host id in form "scheme:host:port"
+func ParseHostID(s string) (HostID, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return "", fmt.Errorf("%w: %q is not in scheme:host:port form", ErrInvalidHostID, s)
+	}
+	port, err := strconv.Atoi(parts[2])
+	if err != nil || port == 0 {
+		return "", fmt.Errorf("%w: invalid port in %q", ErrInvalidHostID, s)
+	}
+	return NewHostID(parts[0], parts[1], port)
+}
+
+// HostIDFromURL creates HostID from site url like "https://example.com/" or "http://пример.рф:8080"
+func HostIDFromURL(rawURL string) (HostID, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("%w: %s", ErrInvalidHostID, err.Error())
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: url %q has no host", ErrInvalidHostID, rawURL)
+	}
+	port := 0
+	if p := u.Port(); p != "" {
+		port, err = strconv.Atoi(p)
+		if err != nil || port == 0 {
+			return "", fmt.Errorf("%w: invalid port in %q", ErrInvalidHostID, rawURL)
+		}
+	}
+	return NewHostID(u.Scheme, u.Hostname(), port)
+}
+
+// idnaProfile converts host names like browsers do, underscores used by some hosts are allowed
+var idnaProfile = idna.New(idna.MapForLookup(), idna.BidiRule(), idna.StrictDomainName(false))
+
+// hostToASCII converts unicode host name to punycode
+func hostToASCII(host string) (string, error) {
+	return idnaProfile.ToASCII(host)
+}
+
+// hostToUnicode converts punycode host name to unicode
+func hostToUnicode(host string) (string, error) {
+	return idnaProfile.ToUnicode(host)
+}
+
+// validateHostName checks ascii host name labels
+func validateHostName(host string) error {
+	if host == "" || len(host) > 253 {
+		return fmt.Errorf("%w: invalid host %q", ErrInvalidHostID, host)
+	}
+	for _, label := range strings.Split(host, ".") {
+		if label == "" || len(label) > 63 || label[0] == '-' || label[len(label)-1] == '-' {
+			return fmt.Errorf("%w: invalid host %q", ErrInvalidHostID, host)
+		}
+		for i := 0; i < len(label); i++ {
+			c := label[i]
+			if !(c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '-' || c == '_') {
+				return fmt.Errorf("%w: invalid host %q", ErrInvalidHostID, host)
+			}
+		}
+	}
+	return nil
+}
+
+// Validate checks that host id is well formed
+func (h HostID) Validate() error {
+	_, err := ParseHostID(string(h))
+	return err
+}
+
+// String returns host id as is
+func (h HostID) String() string {
+	return string(h)
+}
+
+// parts returns scheme, ascii host and port, host id must be valid
+func (h HostID) parts() (string, string, int) {
+	parts := strings.Split(string(h), ":")
+	if len(parts) != 3 {
+		return "", "", 0
+	}
+	port, _ := strconv.Atoi(parts[2])
+	return parts[0], parts[1], port
+}
+
+// Scheme returns host scheme
+func (h HostID) Scheme() string {
+	scheme, _, _ := h.parts()
+	return scheme
+}
+
+// Host returns ascii (punycode) host name
+func (h HostID) Host() string {
+	_, host, _ := h.parts()
+	return host
+}
+
+// Port returns host port
+func (h HostID) Port() int {
+	_, _, port := h.parts()
+	return port
+}
+
+// buildURL returns site url for host name, default port is omitted
+func (h HostID) buildURL(host string) string {
+	scheme, _, port := h.parts()
+	if scheme == "" {
+		return ""
+	}
+	if defaultPorts[scheme] != port {
+		host = fmt.Sprintf("%s:%d", host, port)
+	}
+	return fmt.Sprintf("%s://%s/", scheme, host)
+}
+
+// ASCIIURL returns site url with punycode host, matches Host.AsciiHostURL
+func (h HostID) ASCIIURL() string {
+	return h.buildURL(h.Host())
+}
+
+// UnicodeURL returns site url with unicode host, matches Host.UnicodeHostURL
+func (h HostID) UnicodeURL() string {
+	host := h.Host()
+	if unicodeHost, err := hostToUnicode(host); err == nil {
+		host = unicodeHost
+	}
+	return h.buildURL(host)
+}
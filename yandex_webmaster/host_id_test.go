@@ -0,0 +1,131 @@
+package yandexwebmaster
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHostToASCII(t *testing.T) {
+	// samples from RFC 3492 section 7.1, lowercased as lookup mapping does
+	tests := []struct {
+		name    string
+		unicode string
+		ascii   string
+	}{
+		{name: "arabic", unicode: "ليهمابتكلموشعربي؟", ascii: "xn--egbpdaj6bu4bxfgehfvwxn"},
+		{name: "chinese simplified", unicode: "他们为什么不说中文", ascii: "xn--ihqwcrb4cv8a8dqg056pqjye"},
+		{name: "chinese traditional", unicode: "他們爲什麽不說中文", ascii: "xn--ihqwctvzc91f659drss3x8bo0yb"},
+		{name: "czech uppercase", unicode: "Pročprostěnemluvíčesky", ascii: "xn--proprostnemluvesky-uyb24dma41a"},
+		{name: "hebrew", unicode: "למההםפשוטלאמדבריםעברית", ascii: "xn--4dbcagdahymbxekheh6e0a7fei0b"},
+		{name: "japanese", unicode: "なぜみんな日本語を話してくれないのか", ascii: "xn--n8jok5ay5dzabd5bym9f0cm5685rrjetr6pdxa"},
+		{name: "russian", unicode: "почемужеонинеговорятпорусски", ascii: "xn--b1abfaaepdrnnbgefbadotcwatmq2g4l"},
+		{name: "spanish", unicode: "PorquénopuedensimplementehablarenEspañol", ascii: "xn--porqunopuedensimplementehablarenespaol-fmd56a"},
+		{name: "mixed script", unicode: "3年B組金八先生", ascii: "xn--3b-ww4c5e180e575a65lsy2b"},
+		{name: "mixed script with hyphens", unicode: "安室奈美恵-with-SUPER-MONKEYS", ascii: "xn---with-super-monkeys-pc58ag80a8qai00g7n9n"},
+		{name: "cyrillic domain", unicode: "Пример.РФ", ascii: "xn--e1afmkfd.xn--p1ai"},
+		{name: "ascii uppercase", unicode: "WWW.Example.COM", ascii: "www.example.com"},
+		{name: "underscore", unicode: "my_site.example.com", ascii: "my_site.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := hostToASCII(tt.unicode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.ascii {
+				t.Errorf("hostToASCII(%q) = %q, want %q", tt.unicode, got, tt.ascii)
+			}
+			back, err := hostToUnicode(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			again, err := hostToASCII(back)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if again != tt.ascii {
+				t.Errorf("round trip of %q gives %q", tt.ascii, again)
+			}
+		})
+	}
+}
+
+func TestHostToUnicodeUppercasePrefix(t *testing.T) {
+	got, err := hostToUnicode("XN--E1AFMKFD.xn--p1ai")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "пример.рф" {
+		t.Errorf("hostToUnicode() = %q", got)
+	}
+}
+
+func TestParseHostID(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    HostID
+		wantErr bool
+	}{
+		{input: "https:example.com:443", want: "https:example.com:443"},
+		{input: "HTTP:Example.COM:80", want: "http:example.com:80"},
+		{input: "http:example.com.:8080", want: "http:example.com:8080"},
+		{input: "https:пример.рф:443", want: "https:xn--e1afmkfd.xn--p1ai:443"},
+		{input: "https:xn--e1afmkfd.xn--p1ai:443", want: "https:xn--e1afmkfd.xn--p1ai:443"},
+		{input: "example.com", wantErr: true},
+		{input: "https://example.com/", wantErr: true},
+		{input: "ftp:example.com:21", wantErr: true},
+		{input: "https:example.com:0", wantErr: true},
+		{input: "https:example.com:70000", wantErr: true},
+		{input: "https:example.com:port", wantErr: true},
+		{input: "https:-example.com:443", wantErr: true},
+		{input: "https:exa mple.com:443", wantErr: true},
+		{input: "https::443", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseHostID(tt.input)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidHostID) {
+					t.Errorf("ParseHostID(%q) = %q, %v, want ErrInvalidHostID", tt.input, got, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("ParseHostID(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHostIDURLs(t *testing.T) {
+	tests := []struct {
+		rawURL     string
+		want       HostID
+		asciiURL   string
+		unicodeURL string
+	}{
+		{rawURL: "https://example.com/", want: "https:example.com:443", asciiURL: "https://example.com/", unicodeURL: "https://example.com/"},
+		{rawURL: "http://пример.рф:8080/path", want: "http:xn--e1afmkfd.xn--p1ai:8080", asciiURL: "http://xn--e1afmkfd.xn--p1ai:8080/", unicodeURL: "http://пример.рф:8080/"},
+		{rawURL: "HTTPS://WWW.Example.com:443", want: "https:www.example.com:443", asciiURL: "https://www.example.com/", unicodeURL: "https://www.example.com/"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rawURL, func(t *testing.T) {
+			got, err := HostIDFromURL(tt.rawURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("HostIDFromURL() = %q, want %q", got, tt.want)
+			}
+			if got.ASCIIURL() != tt.asciiURL {
+				t.Errorf("ASCIIURL() = %q, want %q", got.ASCIIURL(), tt.asciiURL)
+			}
+			if got.UnicodeURL() != tt.unicodeURL {
+				t.Errorf("UnicodeURL() = %q, want %q", got.UnicodeURL(), tt.unicodeURL)
+			}
+		})
+	}
+}
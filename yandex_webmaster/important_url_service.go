@@ -1,8 +1,8 @@
 package yandexwebmaster
 
 import (
-	"fmt"
 	"net/http"
+	"sort"
 )
 
 // Important url service for important url management
@@ -15,52 +15,192 @@ func newImportURLService(cl *Client) *ImportantURLService {
 	return &ImportantURLService{client: cl}
 }
 
-type IndexingStatus struct {
-	Status     string `json:"status"`
-	HTTPCode   string `json:"http_code"`
-	AccessDate string `json:"access_date"`
+// ChangeIndicator - indicator of important url change
+type ChangeIndicator string
+
+const (
+	ChangeIndicatorIndexingHTTPCode ChangeIndicator = "INDEXING_HTTP_CODE"
+	ChangeIndicatorSearchStatus     ChangeIndicator = "SEARCH_STATUS"
+	ChangeIndicatorTitle            ChangeIndicator = "TITLE"
+	ChangeIndicatorDescription      ChangeIndicator = "DESCRIPTION"
+)
+
+// ExcludedURLStatus - reason of url exclusion from search
+type ExcludedURLStatus string
+
+const (
+	ExcludedURLStatusNothingFound          ExcludedURLStatus = "NOTHING_FOUND"
+	ExcludedURLStatusHostError             ExcludedURLStatus = "HOST_ERROR"
+	ExcludedURLStatusRedirectNotSearchable ExcludedURLStatus = "REDIRECT_NOTSEARCHABLE"
+	ExcludedURLStatusHTTPError             ExcludedURLStatus = "HTTP_ERROR"
+	ExcludedURLStatusNotCanonical          ExcludedURLStatus = "NOT_CANONICAL"
+	ExcludedURLStatusNotMainMirror         ExcludedURLStatus = "NOT_MAIN_MIRROR"
+	ExcludedURLStatusParserError           ExcludedURLStatus = "PARSER_ERROR"
+	ExcludedURLStatusRobotsHostError       ExcludedURLStatus = "ROBOTS_HOST_ERROR"
+	ExcludedURLStatusRobotsURLError        ExcludedURLStatus = "ROBOTS_URL_ERROR"
+	ExcludedURLStatusDuplicate             ExcludedURLStatus = "DUPLICATE"
+	ExcludedURLStatusLowQuality            ExcludedURLStatus = "LOW_QUALITY"
+	ExcludedURLStatusCleanParams           ExcludedURLStatus = "CLEAN_PARAMS"
+	ExcludedURLStatusNoIndex               ExcludedURLStatus = "NO_INDEX"
+	ExcludedURLStatusOther                 ExcludedURLStatus = "OTHER"
+)
+
+// IsExcluded reports whether status is a reason of exclusion from search. Unknown statuses
+// are exclusion reasons too, NOTHING_FOUND means api found no reason and empty status means
+// url is not excluded
+func (s ExcludedURLStatus) IsExcluded() bool {
+	return s != "" && s != ExcludedURLStatusNothingFound
+}
+
+// HasChange reports whether the url was marked with given change indicator
+func (u *ImportantURL) HasChange(indicator ChangeIndicator) bool {
+	for _, ci := range u.ChangeIndicators {
+		if ci == indicator {
+			return true
+		}
+	}
+	return false
+}
+
+// StringChange - old and new value of changed string field
+type StringChange struct {
+	Old string
+	New string
 }
 
-type SearchStatus struct {
-	Title             string `json:"title"`
-	Description       string `json:"description"`
-	LastAccess        string `json:"last_access"`
-	ExcludedURLStatus string `json:"excluded_url_status"`
-	BadHTTPStatus     int    `json:"bad_http_status"`
-	Searchable        bool   `json:"searchable"`
-	TargetURL         string `json:"target_url"`
+// IntChange - old and new value of changed int field
+type IntChange struct {
+	Old int
+	New int
 }
 
-type ImportantURL struct {
-	URL              string          `json:"url"`
-	UpdateDate       string          `json:"update_date"`
-	ChangeIndicators []string        `json:"change_indicators"`
-	IndexingStatus   *IndexingStatus `json:"indexing_status"`
-	SearchStatus     *SearchStatus   `json:"search_status"`
+// BoolChange - old and new value of changed bool field
+type BoolChange struct {
+	Old bool
+	New bool
 }
 
-type ImportantURLS struct {
-	URLS []*ImportantURL `json:"urls"`
+// ExcludedURLStatusChange - old and new reason of url exclusion, empty status means url is not excluded
+type ExcludedURLStatusChange struct {
+	Old ExcludedURLStatus
+	New ExcludedURLStatus
 }
 
-type ImportantURLSHistory struct {
-	History []*ImportantURL `json:"history"`
+// ImportantURLDiff - difference between two consecutive important url history entries.
+// Only fields reported by ChangeIndicators of the newer entry are filled.
+type ImportantURLDiff struct {
+	UpdateDate  Timestamp
+	Indicators  []ChangeIndicator
+	Title       *StringChange
+	Description *StringChange
+	HTTPCode    *IntChange
+	Searchable  *BoolChange
+	// ExcludedURLStatus - change of exclusion reason reported by SEARCH_STATUS
+	ExcludedURLStatus *ExcludedURLStatusChange
+}
+
+// Diffs returns changes between consecutive history entries ordered by update date
+func (h ImportantURLSHistory) Diffs() []ImportantURLDiff {
+	history := make([]*ImportantURL, 0, len(h.History))
+	for _, item := range h.History {
+		if item != nil {
+			history = append(history, item)
+		}
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return history[i].UpdateDate.Before(history[j].UpdateDate.Time)
+	})
+	diffs := make([]ImportantURLDiff, 0, len(history))
+	for i := 1; i < len(history); i++ {
+		if diff, ok := diffImportantURL(history[i-1], history[i]); ok {
+			diffs = append(diffs, diff)
+		}
+	}
+	return diffs
+}
+
+// diffImportantURL compares prev and next entries using next change indicators
+func diffImportantURL(prev *ImportantURL, next *ImportantURL) (ImportantURLDiff, bool) {
+	diff := ImportantURLDiff{UpdateDate: next.UpdateDate, Indicators: next.ChangeIndicators}
+	if len(next.ChangeIndicators) == 0 {
+		return diff, false
+	}
+	var prevSearch, nextSearch SearchStatus
+	if prev.SearchStatus != nil {
+		prevSearch = *prev.SearchStatus
+	}
+	if next.SearchStatus != nil {
+		nextSearch = *next.SearchStatus
+	}
+	var prevIndexing, nextIndexing IndexingStatus
+	if prev.IndexingStatus != nil {
+		prevIndexing = *prev.IndexingStatus
+	}
+	if next.IndexingStatus != nil {
+		nextIndexing = *next.IndexingStatus
+	}
+	if next.HasChange(ChangeIndicatorTitle) {
+		diff.Title = &StringChange{Old: prevSearch.Title, New: nextSearch.Title}
+	}
+	if next.HasChange(ChangeIndicatorDescription) {
+		diff.Description = &StringChange{Old: prevSearch.Description, New: nextSearch.Description}
+	}
+	if next.HasChange(ChangeIndicatorIndexingHTTPCode) {
+		diff.HTTPCode = &IntChange{Old: prevIndexing.HTTPCode, New: nextIndexing.HTTPCode}
+	}
+	if next.HasChange(ChangeIndicatorSearchStatus) && prevSearch.Searchable != nextSearch.Searchable {
+		diff.Searchable = &BoolChange{Old: prevSearch.Searchable, New: nextSearch.Searchable}
+	}
+	if next.HasChange(ChangeIndicatorSearchStatus) && prevSearch.ExcludedURLStatus != nextSearch.ExcludedURLStatus {
+		diff.ExcludedURLStatus = &ExcludedURLStatusChange{Old: prevSearch.ExcludedURLStatus, New: nextSearch.ExcludedURLStatus}
+	}
+	return diff, true
 }
 
 // get monigorint important urls, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-id-important-urls.html
-func (s *IndexingService) GetMonitoringImportantURLS(hostID string) (ImportantURLS, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/important-urls", s.client.userID, hostID)
+func (s *ImportantURLService) GetMonitoringImportantURLS(hostID HostID) (ImportantURLS, error) {
 	var result ImportantURLS
-	_, err := s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "important-urls")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
 	return result, err
 }
 
 // get important url history, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-id-important-urls-history.html
-func (s *IndexingService) GetImportantURLHistory(hostID string, url string) (ImportantURLSHistory, error) {
+func (s *ImportantURLService) GetImportantURLHistory(hostID HostID, url string) (ImportantURLSHistory, error) {
 	data := make(map[string]interface{})
 	data["url"] = url
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/important-urls", s.client.userID, hostID)
 	var result ImportantURLSHistory
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
+	v := &validator{}
+	v.required("URL", url)
+	if err := v.err(); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "important-urls/history")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.makeGETRequestWithParams(endpoint, data, &result)
 	return result, err
 }
+
+// get important url history changes between consecutive entries
+func (s *ImportantURLService) GetImportantURLChanges(hostID HostID, url string) ([]ImportantURLDiff, error) {
+	history, err := s.GetImportantURLHistory(hostID, url)
+	if err != nil {
+		return nil, err
+	}
+	return history.Diffs(), nil
+}
+
+// Deprecated: use ImportantURLService.GetMonitoringImportantURLS
+func (s *IndexingService) GetMonitoringImportantURLS(hostID HostID) (ImportantURLS, error) {
+	return s.client.ImportantURL.GetMonitoringImportantURLS(hostID)
+}
+
+// Deprecated: use ImportantURLService.GetImportantURLHistory
+func (s *IndexingService) GetImportantURLHistory(hostID HostID, url string) (ImportantURLSHistory, error) {
+	return s.client.ImportantURL.GetImportantURLHistory(hostID, url)
+}
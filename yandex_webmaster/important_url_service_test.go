@@ -0,0 +1,101 @@
+package yandexwebmaster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func importantURLAt(day int, indicators []ChangeIndicator, indexing *IndexingStatus, search *SearchStatus) *ImportantURL {
+	return &ImportantURL{
+		URL:              "https://example.com/",
+		UpdateDate:       NewTimestamp(time.Date(2026, 9, day, 10, 0, 0, 0, time.UTC)),
+		ChangeIndicators: indicators,
+		IndexingStatus:   indexing,
+		SearchStatus:     search,
+	}
+}
+
+func TestImportantURLHistoryDiffs(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []*ImportantURL
+		want    []ImportantURLDiff
+	}{
+		{
+			name:    "empty",
+			history: nil,
+			want:    []ImportantURLDiff{},
+		},
+		{
+			name: "entries without indicators are skipped",
+			history: []*ImportantURL{
+				importantURLAt(1, nil, nil, &SearchStatus{Title: "a"}),
+				importantURLAt(2, nil, nil, &SearchStatus{Title: "b"}),
+			},
+			want: []ImportantURLDiff{},
+		},
+		{
+			name: "title and http code, unordered history",
+			history: []*ImportantURL{
+				importantURLAt(2, []ChangeIndicator{ChangeIndicatorTitle, ChangeIndicatorIndexingHTTPCode},
+					&IndexingStatus{HTTPCode: 404}, &SearchStatus{Title: "new"}),
+				nil,
+				importantURLAt(1, nil, &IndexingStatus{HTTPCode: 200}, &SearchStatus{Title: "old"}),
+			},
+			want: []ImportantURLDiff{{
+				UpdateDate: NewTimestamp(time.Date(2026, 9, 2, 10, 0, 0, 0, time.UTC)),
+				Indicators: []ChangeIndicator{ChangeIndicatorTitle, ChangeIndicatorIndexingHTTPCode},
+				Title:      &StringChange{Old: "old", New: "new"},
+				HTTPCode:   &IntChange{Old: 200, New: 404},
+			}},
+		},
+		{
+			name: "search status excluded",
+			history: []*ImportantURL{
+				importantURLAt(1, nil, nil, &SearchStatus{Searchable: true}),
+				importantURLAt(2, []ChangeIndicator{ChangeIndicatorSearchStatus}, nil,
+					&SearchStatus{ExcludedURLStatus: ExcludedURLStatusNoIndex}),
+			},
+			want: []ImportantURLDiff{{
+				UpdateDate:        NewTimestamp(time.Date(2026, 9, 2, 10, 0, 0, 0, time.UTC)),
+				Indicators:        []ChangeIndicator{ChangeIndicatorSearchStatus},
+				Searchable:        &BoolChange{Old: true, New: false},
+				ExcludedURLStatus: &ExcludedURLStatusChange{Old: "", New: ExcludedURLStatusNoIndex},
+			}},
+		},
+		{
+			name: "exclusion reason changed, url stays excluded",
+			history: []*ImportantURL{
+				importantURLAt(1, nil, nil, &SearchStatus{ExcludedURLStatus: ExcludedURLStatusDuplicate}),
+				importantURLAt(2, []ChangeIndicator{ChangeIndicatorSearchStatus}, nil,
+					&SearchStatus{ExcludedURLStatus: ExcludedURLStatusLowQuality}),
+			},
+			want: []ImportantURLDiff{{
+				UpdateDate:        NewTimestamp(time.Date(2026, 9, 2, 10, 0, 0, 0, time.UTC)),
+				Indicators:        []ChangeIndicator{ChangeIndicatorSearchStatus},
+				ExcludedURLStatus: &ExcludedURLStatusChange{Old: ExcludedURLStatusDuplicate, New: ExcludedURLStatusLowQuality},
+			}},
+		},
+		{
+			name: "missing statuses compare as zero values",
+			history: []*ImportantURL{
+				importantURLAt(1, nil, nil, nil),
+				importantURLAt(2, []ChangeIndicator{ChangeIndicatorDescription}, nil, &SearchStatus{Description: "d"}),
+			},
+			want: []ImportantURLDiff{{
+				UpdateDate:  NewTimestamp(time.Date(2026, 9, 2, 10, 0, 0, 0, time.UTC)),
+				Indicators:  []ChangeIndicator{ChangeIndicatorDescription},
+				Description: &StringChange{Old: "", New: "d"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ImportantURLSHistory{History: tt.history}.Diffs()
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diffs() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package yandexwebmaster
 
 import (
-	"fmt"
 	"time"
 )
 
@@ -15,46 +14,61 @@ func newIndexingService(cl *Client) *IndexingService {
 	return &IndexingService{client: cl}
 }
 
-type Indicator struct {
-	Date  string `json:"date"`
-	Value int    `json:"value"`
-}
+// IndexingIndicator - class of http status of indexed pages
+type IndexingIndicator string
 
-type Indicators struct {
-	Indicators map[string]*Indicator `json:"inidicators"`
-}
+const (
+	IndexingIndicatorHTTP2XX IndexingIndicator = "HTTP_2XX"
+	IndexingIndicatorHTTP3XX IndexingIndicator = "HTTP_3XX"
+	IndexingIndicatorHTTP4XX IndexingIndicator = "HTTP_4XX"
+	IndexingIndicatorHTTP5XX IndexingIndicator = "HTTP_5XX"
+	IndexingIndicatorOther   IndexingIndicator = "OTHER"
+)
 
-type Sample struct {
-	Status     string `json:"status"`
-	HTTPCode   string `json:"http_code"`
-	URL        string `json:"url"`
-	AccessDate string `json:"access_date"`
+// AllIndexingIndicators - all known indexing indicators
+var AllIndexingIndicators = []IndexingIndicator{
+	IndexingIndicatorHTTP2XX,
+	IndexingIndicatorHTTP3XX,
+	IndexingIndicatorHTTP4XX,
+	IndexingIndicatorHTTP5XX,
+	IndexingIndicatorOther,
 }
 
-type SamplesResult struct {
-	Count   int       `json:"count"`
-	Samples []*Sample `json:"samples"`
-}
+// Deprecated: use IndexingHistory
+type Indicators = IndexingHistory
 
 // get indexing history, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/hosts-indexing-history.html
-func (s *IndexingService) GetIndexingHistory(hostID string, dateFrom time.Time, dateTo time.Time) (Indicators, error) {
-	data := make(map[string]interface{})
-	data["date_from"] = dateFrom.Format(YYYYMMDD)
-	data["date_to"] = dateTo.Format(YYYYMMDD)
-	var result Indicators
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/indexing/history", s.client.userID, hostID)
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
-	return result, err
+// Long ranges are split by client HistoryLimits and fetched concurrently, failed windows are reported by WindowsError
+func (s *IndexingService) GetIndexingHistory(hostID HostID, req IndexingHistoryRequest) (IndexingHistory, error) {
+	var result IndexingHistory
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "indexing/history")
+	if err != nil {
+		return result, err
+	}
+	windows := s.client.historyWindows(req.DateFrom, req.DateTo)
+	parts := make([]IndexingHistory, len(windows))
+	err = s.client.fetchWindows(windows, func(i int, from time.Time, to time.Time) error {
+		windowReq := req
+		windowReq.DateFrom, windowReq.DateTo = from, to
+		_, err := s.client.makeGETRequestWithParams(endpoint, windowReq.params(), &parts[i])
+		return err
+	})
+	return mergeIndexingHistory(parts), err
 }
 
 // get indexing samples, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/hosts-indexing-samples.html
-func (s *IndexingService) GetIndexingSamples(hostID string, limit int, offset int) (SamplesResult, error) {
-	data := make(map[string]interface{})
-	data["limit"] = limit
-	data["offset"] = offset
-
+func (s *IndexingService) GetIndexingSamples(hostID HostID, req IndexingSamplesRequest) (SamplesResult, error) {
 	var result SamplesResult
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/indexing/samples", s.client.userID, hostID)
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "indexing/samples")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.makeGETRequestWithParams(endpoint, req.params(), &result)
 	return result, err
 }
@@ -0,0 +1,468 @@
+// Command modelgen generates request and response types of yandexwebmaster package from api/openapi.json.
+//
+// Generation is controlled by x-go-* extensions of the spec:
+//
+//	x-go-type     - use existing Go type instead of generating one, for string enums
+//	                String, IsKnown and json methods of the type are generated
+//	x-go-name     - Go name of field
+//	x-go-pointer  - use pointer for object property
+//	x-go-key-type - Go type of map keys
+//	x-go-manual   - schema type is written by hand
+//	x-go-skip     - schema is not used by package
+//	x-go-request  - name of request struct generated from query params of operation
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"strings"
+)
+
+// schema - subset of OpenAPI schema object used by generator
+type schema struct {
+	Ref                  string      `json:"$ref"`
+	Type                 string      `json:"type"`
+	Format               string      `json:"format"`
+	Description          string      `json:"description"`
+	Items                *schema     `json:"items"`
+	AdditionalProperties *schema     `json:"additionalProperties"`
+	Enum                 []string    `json:"enum"`
+	Default              interface{} `json:"default"`
+	Minimum              *float64    `json:"minimum"`
+	Maximum              *float64    `json:"maximum"`
+	GoType               string      `json:"x-go-type"`
+	GoName               string      `json:"x-go-name"`
+	GoPointer            bool        `json:"x-go-pointer"`
+	GoKeyType            string      `json:"x-go-key-type"`
+	GoManual             bool        `json:"x-go-manual"`
+	GoSkip               bool        `json:"x-go-skip"`
+
+	Properties orderedSchemas `json:"properties"`
+}
+
+// parameter - OpenAPI parameter object
+type parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description"`
+	Required    bool    `json:"required"`
+	Schema      *schema `json:"schema"`
+	GoName      string  `json:"x-go-name"`
+}
+
+// operation - OpenAPI operation object
+type operation struct {
+	OperationID string       `json:"operationId"`
+	Parameters  []*parameter `json:"parameters"`
+	GoRequest   string       `json:"x-go-request"`
+}
+
+// spec - subset of OpenAPI document used by generator
+type spec struct {
+	Paths      orderedObject `json:"paths"`
+	Components struct {
+		Schemas orderedSchemas `json:"schemas"`
+	} `json:"components"`
+}
+
+// orderedObject - json object with keys in document order
+type orderedObject struct {
+	Keys   []string
+	Values map[string]json.RawMessage
+}
+
+func (o *orderedObject) UnmarshalJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	o.Values = make(map[string]json.RawMessage)
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := token.(string)
+		var value json.RawMessage
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		o.Keys = append(o.Keys, key)
+		o.Values[key] = value
+	}
+	_, err := dec.Token()
+	return err
+}
+
+// orderedSchemas - schemas by name in document order
+type orderedSchemas struct {
+	Keys   []string
+	Values map[string]*schema
+}
+
+func (o *orderedSchemas) UnmarshalJSON(data []byte) error {
+	var raw orderedObject
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	o.Keys = raw.Keys
+	o.Values = make(map[string]*schema, len(raw.Keys))
+	for _, key := range raw.Keys {
+		var s schema
+		if err := json.Unmarshal(raw.Values[key], &s); err != nil {
+			return fmt.Errorf("%s: %w", key, err)
+		}
+		o.Values[key] = &s
+	}
+	return nil
+}
+
+var initialisms = map[string]string{"id": "ID", "url": "URL", "http": "HTTP"}
+
+// goName converts snake case json name to Go name
+func goName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.ReplaceAll(name, "-", "_"), "_") {
+		part = strings.ToLower(part)
+		if initialism, ok := initialisms[part]; ok {
+			b.WriteString(initialism)
+			continue
+		}
+		if part != "" {
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+type generator struct {
+	spec *spec
+	buf  bytes.Buffer
+}
+
+func (g *generator) printf(format string, args ...interface{}) {
+	fmt.Fprintf(&g.buf, format, args...)
+}
+
+// resolve returns schema name and schema referenced by s
+func (g *generator) resolve(s *schema) (string, *schema) {
+	if s.Ref == "" {
+		return "", s
+	}
+	name := strings.TrimPrefix(s.Ref, "#/components/schemas/")
+	target, ok := g.spec.Components.Schemas.Values[name]
+	if !ok {
+		log.Fatalf("unknown schema reference %s", s.Ref)
+	}
+	return name, target
+}
+
+// goType returns Go type of schema
+func (g *generator) goType(s *schema) string {
+	if s.GoType != "" {
+		return s.GoType
+	}
+	name, target := g.resolve(s)
+	if target.GoType != "" {
+		return target.GoType
+	}
+	switch target.Type {
+	case "object":
+		if name != "" {
+			if s.GoPointer {
+				return "*" + name
+			}
+			return name
+		}
+		if target.AdditionalProperties != nil {
+			keyType := target.GoKeyType
+			if keyType == "" {
+				keyType = "string"
+			}
+			return "map[" + keyType + "]" + g.goType(target.AdditionalProperties)
+		}
+		return "map[string]json.RawMessage"
+	case "array":
+		itemName, item := g.resolve(target.Items)
+		if itemName != "" && item.Type == "object" && item.GoType == "" {
+			return "[]*" + itemName
+		}
+		return "[]" + g.goType(target.Items)
+	case "integer":
+		return "int"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	case "string":
+		if target.Format == "date" {
+			return "time.Time"
+		}
+		return "string"
+	}
+	log.Fatalf("unsupported schema type %q", target.Type)
+	return ""
+}
+
+// writeComment writes doc comment, first letter is lowercased unless description starts with acronym
+func (g *generator) writeComment(indent string, name string, description string) {
+	if description == "" {
+		return
+	}
+	if len(description) > 1 && !(description[1] >= 'A' && description[1] <= 'Z') {
+		description = strings.ToLower(description[:1]) + description[1:]
+	}
+	if name != "" {
+		g.printf("%s// %s - %s\n", indent, name, description)
+		return
+	}
+	g.printf("%s// %s\n", indent, description)
+}
+
+// generateModel writes response struct with json methods keeping unknown fields
+func (g *generator) generateModel(name string, s *schema) {
+	g.writeComment("", name, s.Description)
+	g.printf("type %s struct {\n", name)
+	for _, key := range s.Properties.Keys {
+		prop := s.Properties.Values[key]
+		field := prop.GoName
+		if field == "" {
+			field = goName(key)
+		}
+		_, target := g.resolve(prop)
+		description := prop.Description
+		if description == "" && prop.Ref == "" {
+			description = target.Description
+		}
+		g.writeComment("\t", field, description)
+		g.printf("\t%s %s `json:\"%s\"`\n", field, g.goType(prop), key)
+	}
+	g.printf("\t// Extra - fields unknown to library\n")
+	g.printf("\tExtra map[string]json.RawMessage `json:\"-\"`\n")
+	g.printf("}\n\n")
+	g.printf("func (m *%s) UnmarshalJSON(bytes []byte) error {\n", name)
+	g.printf("\ttype plain %s\n", name)
+	g.printf("\textra, err := unmarshalWithExtra(bytes, (*plain)(m))\n")
+	g.printf("\tm.Extra = extra\n")
+	g.printf("\treturn err\n")
+	g.printf("}\n\n")
+	g.printf("func (m %s) MarshalJSON() ([]byte, error) {\n", name)
+	g.printf("\ttype plain %s\n", name)
+	g.printf("\treturn marshalWithExtra(plain(m), m.Extra)\n")
+	g.printf("}\n\n")
+}
+
+// generateEnum writes methods of hand-written string enum type, unknown values are kept as is
+func (g *generator) generateEnum(s *schema) {
+	name := s.GoType
+	values := make([]string, 0, len(s.Enum))
+	for _, value := range s.Enum {
+		values = append(values, fmt.Sprintf("%q", value))
+	}
+	g.printf("// String returns value as is\n")
+	g.printf("func (v %s) String() string {\n", name)
+	g.printf("\treturn string(v)\n")
+	g.printf("}\n\n")
+	g.printf("// IsKnown reports whether value is known by library\n")
+	g.printf("func (v %s) IsKnown() bool {\n", name)
+	g.printf("\tswitch v {\n")
+	g.printf("\tcase %s:\n", strings.Join(values, ", "))
+	g.printf("\t\treturn true\n")
+	g.printf("\t}\n")
+	g.printf("\treturn false\n")
+	g.printf("}\n\n")
+	g.printf("func (v %s) MarshalJSON() ([]byte, error) {\n", name)
+	g.printf("\treturn json.Marshal(string(v))\n")
+	g.printf("}\n\n")
+	g.printf("func (v *%s) UnmarshalJSON(bytes []byte) error {\n", name)
+	g.printf("\tvalue, err := unmarshalEnum(bytes)\n")
+	g.printf("\t*v = %s(value)\n", name)
+	g.printf("\treturn err\n")
+	g.printf("}\n\n")
+}
+
+// requestField - query param of request struct
+type requestField struct {
+	param *parameter
+	name  string
+	typ   string
+}
+
+// generateRequest writes request struct with validation and GET params from query params of operation,
+// operationIDs are all operations sharing the request
+func (g *generator) generateRequest(name string, op *operation, operationIDs []string) {
+	var fields []requestField
+	hasDate := map[string]bool{}
+	for _, p := range op.Parameters {
+		if p.In != "query" {
+			continue
+		}
+		field := p.GoName
+		if field == "" {
+			field = goName(p.Name)
+		}
+		fields = append(fields, requestField{param: p, name: field, typ: g.goType(p.Schema)})
+		if p.Schema.Format == "date" {
+			hasDate[p.Name] = true
+		}
+	}
+
+	g.printf("// %s - params of %s requests\n", name, strings.Join(operationIDs, " and "))
+	g.printf("type %s struct {\n", name)
+	for _, f := range fields {
+		g.writeComment("\t", f.name, f.param.Description)
+		g.printf("\t%s %s\n", f.name, f.typ)
+	}
+	g.printf("}\n\n")
+
+	g.printf("// Validate checks request before sending, dates are checked in DefaultLocation\n")
+	g.printf("func (r %s) Validate() error {\n", name)
+	g.printf("\treturn r.validate(DefaultLocation)\n")
+	g.printf("}\n\n")
+
+	g.printf("// validate checks request with dates in loc\n")
+	g.printf("func (r %s) validate(loc *time.Location) error {\n", name)
+	g.printf("\tv := &validator{loc: loc}\n")
+	if hasDate["date_from"] && hasDate["date_to"] {
+		g.printf("\tv.dateRange(\"DateFrom\", r.DateFrom, \"DateTo\", r.DateTo)\n")
+	}
+	for _, f := range fields {
+		_, target := g.resolve(f.param.Schema)
+		switch {
+		case f.param.Required && f.typ == "string":
+			g.printf("\tv.required(%q, r.%s)\n", f.name, f.name)
+		case target.Type == "integer" && target.Maximum != nil:
+			g.printf("\tv.limit(%q, r.%s, %d)\n", f.name, f.name, int(*target.Maximum))
+		case target.Type == "integer" && target.Minimum != nil:
+			g.printf("\tv.offset(%q, r.%s)\n", f.name, f.name)
+		case target.Type == "array" && len(g.itemEnum(target)) != 0:
+			g.printf("\tfor _, value := range r.%s {\n", f.name)
+			g.printf("\t\tv.enum(%q, value, value.IsKnown())\n", f.name)
+			g.printf("\t}\n")
+		case len(f.param.Schema.Enum) != 0:
+			checks := make([]string, 0, len(f.param.Schema.Enum))
+			for _, value := range f.param.Schema.Enum {
+				checks = append(checks, fmt.Sprintf("r.%s != %q", f.name, value))
+			}
+			g.printf("\tif r.%s != \"\" && %s {\n", f.name, strings.Join(checks, " && "))
+			g.printf("\t\tv.add(%q, r.%s, %q)\n", f.name, f.name, "must be one of "+strings.Join(f.param.Schema.Enum, ", "))
+			g.printf("\t}\n")
+		case len(target.Enum) != 0:
+			g.printf("\tif r.%s != \"\" {\n", f.name)
+			g.printf("\t\tv.enum(%q, r.%s, r.%s.IsKnown())\n", f.name, f.name, f.name)
+			g.printf("\t}\n")
+		}
+	}
+	g.printf("\treturn v.err()\n")
+	g.printf("}\n\n")
+
+	g.printf("func (r %s) params() map[string]interface{} {\n", name)
+	g.printf("\tdata := make(map[string]interface{})\n")
+	for _, f := range fields {
+		key := f.param.Name
+		switch {
+		case f.typ == "time.Time":
+			g.printf("\tsetDateParam(data, %q, r.%s)\n", key, f.name)
+		case f.typ == "int":
+			g.printf("\tsetIntParam(data, %q, r.%s)\n", key, f.name)
+		case strings.HasPrefix(f.typ, "[]"):
+			g.printf("\tif len(r.%s) != 0 {\n", f.name)
+			g.printf("\t\tdata[%q] = r.%s\n", key, f.name)
+			g.printf("\t}\n")
+		default:
+			g.printf("\tif r.%s != \"\" {\n", f.name)
+			g.printf("\t\tdata[%q] = r.%s\n", key, f.name)
+			if f.param.Schema.Default != nil {
+				g.printf("\t} else {\n")
+				g.printf("\t\tdata[%q] = %s(%q)\n", key, f.typ, fmt.Sprint(f.param.Schema.Default))
+			}
+			g.printf("\t}\n")
+		}
+	}
+	g.printf("\treturn data\n")
+	g.printf("}\n\n")
+}
+
+// itemEnum returns enum values of array items
+func (g *generator) itemEnum(s *schema) []string {
+	if s.Items == nil {
+		return nil
+	}
+	_, item := g.resolve(s.Items)
+	return item.Enum
+}
+
+func (g *generator) generate() ([]byte, error) {
+	g.printf("// Code generated by modelgen from api/openapi.json. DO NOT EDIT.\n\n")
+	g.printf("package yandexwebmaster\n\n")
+	g.printf("import (\n\t\"encoding/json\"\n\t\"time\"\n)\n\n")
+
+	schemas := g.spec.Components.Schemas
+	for _, name := range schemas.Keys {
+		s := schemas.Values[name]
+		if s.Type != "object" || s.GoType != "" || s.GoManual || s.GoSkip {
+			continue
+		}
+		g.generateModel(name, s)
+	}
+	for _, name := range schemas.Keys {
+		s := schemas.Values[name]
+		if s.Type == "string" && s.GoType != "" && len(s.Enum) != 0 && !s.GoSkip {
+			g.generateEnum(s)
+		}
+	}
+
+	var requests []*operation
+	operationIDs := map[string][]string{}
+	for _, path := range g.spec.Paths.Keys {
+		var methods orderedObject
+		if err := json.Unmarshal(g.spec.Paths.Values[path], &methods); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		for _, method := range methods.Keys {
+			op := &operation{}
+			if err := json.Unmarshal(methods.Values[method], op); err != nil {
+				return nil, fmt.Errorf("%s %s: %w", method, path, err)
+			}
+			if op.GoRequest == "" {
+				continue
+			}
+			if _, ok := operationIDs[op.GoRequest]; !ok {
+				requests = append(requests, op)
+			}
+			operationIDs[op.GoRequest] = append(operationIDs[op.GoRequest], op.OperationID)
+		}
+	}
+	for _, op := range requests {
+		g.generateRequest(op.GoRequest, op, operationIDs[op.GoRequest])
+	}
+	return format.Source(g.buf.Bytes())
+}
+
+func main() {
+	specPath := flag.String("spec", "api/openapi.json", "path to OpenAPI spec")
+	outPath := flag.String("out", "models_gen.go", "path to generated file")
+	flag.Parse()
+
+	data, err := os.ReadFile(*specPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var s spec
+	if err := json.Unmarshal(data, &s); err != nil {
+		log.Fatal(err)
+	}
+	g := &generator{spec: &s}
+	source, err := g.generate()
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.WriteFile(*outPath, source, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
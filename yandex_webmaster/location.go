@@ -0,0 +1,96 @@
+package yandexwebmaster
+
+import (
+	"reflect"
+	"time"
+)
+
+// DefaultLocation - reporting time zone of api, Europe/Moscow or fixed UTC+3 when tz database is unavailable
+var DefaultLocation = loadDefaultLocation()
+
+func loadDefaultLocation() *time.Location {
+	loc, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		return time.FixedZone("MSK", 3*60*60)
+	}
+	return loc
+}
+
+// WithLocation - use loc as reporting location instead of DefaultLocation.
+// Request dates are converted to loc before taking calendar day and response timestamps are returned in loc.
+func WithLocation(loc *time.Location) Option {
+	return func(c *Client) {
+		if loc != nil {
+			c.location = loc
+		}
+	}
+}
+
+// Location returns reporting location of client
+func (c *Client) Location() *time.Location {
+	return c.location
+}
+
+// DateIn returns calendar date of t in loc
+func DateIn(t time.Time, loc *time.Location) Date {
+	return DateOf(t.In(loc))
+}
+
+// midnightIn returns start of date in loc
+func midnightIn(d Date, loc *time.Location) time.Time {
+	if d.IsZero() {
+		return time.Time{}
+	}
+	return time.Date(d.Year(), d.Month(), d.Day(), 0, 0, 0, 0, loc)
+}
+
+var timestampType = reflect.TypeOf(Timestamp{})
+
+// localizeTimestamps converts all timestamps reachable from v to loc
+func localizeTimestamps(v interface{}, loc *time.Location) {
+	walkTimestamps(reflect.ValueOf(v), loc)
+}
+
+func walkTimestamps(v reflect.Value, loc *time.Location) {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if !v.IsNil() {
+			walkTimestamps(v.Elem(), loc)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < v.Len(); i++ {
+			walkTimestamps(v.Index(i), loc)
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			value := iter.Value()
+			if value.Kind() == reflect.Struct {
+				// map values are not addressable, so convert a copy and put it back
+				elem := reflect.New(value.Type()).Elem()
+				elem.Set(value)
+				walkTimestamps(elem, loc)
+				v.SetMapIndex(iter.Key(), elem)
+				continue
+			}
+			walkTimestamps(value, loc)
+		}
+	case reflect.Struct:
+		if v.Type() == timestampType {
+			if v.CanSet() {
+				ts := v.Interface().(Timestamp)
+				if !ts.IsZero() {
+					ts.Time = ts.Time.In(loc)
+					v.Set(reflect.ValueOf(ts))
+				}
+			}
+			return
+		}
+		t := v.Type()
+		for i := 0; i < t.NumField(); i++ {
+			if t.Field(i).IsExported() {
+				walkTimestamps(v.Field(i), loc)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestLocalizeTimestamps(t *testing.T) {
+	utc := time.Date(2026, 9, 1, 21, 30, 0, 0, time.UTC)
+	msk := time.FixedZone("MSK", 3*60*60)
+	type nested struct {
+		At   Timestamp
+		Ptr  *Timestamp
+		List []*Indicator
+		ByID map[string]Indicator
+		Any  interface{}
+		when Timestamp
+	}
+	ptr := NewTimestamp(utc)
+	anyIndicator := &Indicator{Date: NewTimestamp(utc)}
+	v := &nested{
+		At:   NewTimestamp(utc),
+		Ptr:  &ptr,
+		List: []*Indicator{{Date: NewTimestamp(utc)}, nil},
+		ByID: map[string]Indicator{"a": {Date: NewTimestamp(utc)}},
+		Any:  anyIndicator,
+		when: NewTimestamp(utc),
+	}
+	localizeTimestamps(v, msk)
+	tests := []struct {
+		name string
+		ts   Timestamp
+		loc  *time.Location
+	}{
+		{name: "field", ts: v.At, loc: msk},
+		{name: "pointer", ts: *v.Ptr, loc: msk},
+		{name: "slice of pointers", ts: v.List[0].Date, loc: msk},
+		{name: "map value", ts: v.ByID["a"].Date, loc: msk},
+		{name: "interface", ts: anyIndicator.Date, loc: msk},
+		{name: "unexported field is kept", ts: v.when, loc: time.UTC},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.ts.Location() != tt.loc {
+				t.Errorf("location = %v, want %v", tt.ts.Location(), tt.loc)
+			}
+			if !tt.ts.Equal(utc) {
+				t.Errorf("instant changed: %v", tt.ts)
+			}
+		})
+	}
+	if got := v.At.CalendarDate().String(); got != "2026-09-02" {
+		t.Errorf("CalendarDate() = %s, want 2026-09-02", got)
+	}
+}
+
+func TestLocalizeTimestampsKeepsZeroAndRaw(t *testing.T) {
+	var history InseacrhURLHistory
+	body := `{"history":[{"date":"2026-09-01T00:00:00,000+0300","value":1},{"date":null,"value":2}]}`
+	if err := json.Unmarshal([]byte(body), &history); err != nil {
+		t.Fatal(err)
+	}
+	localizeTimestamps(&history, time.FixedZone("", 3*60*60))
+	if !history.History[1].Date.IsZero() {
+		t.Errorf("zero timestamp changed: %v", history.History[1].Date)
+	}
+	out, _ := json.Marshal(history.History[0].Date)
+	if string(out) != `"2026-09-01T00:00:00,000+0300"` {
+		t.Errorf("Marshal() = %s, want raw api value", out)
+	}
+	localizeTimestamps(&history, time.UTC)
+	out, _ = json.Marshal(history.History[0].Date)
+	if string(out) != `"2026-08-31T21:00:00Z"` {
+		t.Errorf("Marshal() in UTC = %s", out)
+	}
+}
@@ -0,0 +1,1366 @@
+// Code generated by modelgen from api/openapi.json. DO NOT EDIT.
+
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// User - current user
+type User struct {
+	// UserID - user id used in request paths
+	UserID int `json:"user_id"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *User) UnmarshalJSON(bytes []byte) error {
+	type plain User
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m User) MarshalJSON() ([]byte, error) {
+	type plain User
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// MainMirror - main mirror of host
+type MainMirror struct {
+	HostID HostID `json:"host_id"`
+	// AsciiHostURL - site url with punycode host
+	AsciiHostURL string `json:"ascii_host_url"`
+	// UnicodeHostURL - site url with unicode host
+	UnicodeHostURL string `json:"unicode_host_url"`
+	// Verified - rights on site are verified
+	Verified bool `json:"verified"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *MainMirror) UnmarshalJSON(bytes []byte) error {
+	type plain MainMirror
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m MainMirror) MarshalJSON() ([]byte, error) {
+	type plain MainMirror
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// Host - site added by user
+type Host struct {
+	HostID HostID `json:"host_id"`
+	// AsciiHostURL - site url with punycode host
+	AsciiHostURL string `json:"ascii_host_url"`
+	// UnicodeHostURL - site url with unicode host
+	UnicodeHostURL string `json:"unicode_host_url"`
+	// Verified - rights on site are verified
+	Verified   bool       `json:"verified"`
+	MainMirror MainMirror `json:"main_mirror"`
+	// HostDataStatus - site data availability: NOT_INDEXED, NOT_LOADED or OK
+	HostDataStatus string `json:"host_data_status"`
+	// HostDisplayName - site name shown in Webmaster
+	HostDisplayName string `json:"host_display_name"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Host) UnmarshalJSON(bytes []byte) error {
+	type plain Host
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Host) MarshalJSON() ([]byte, error) {
+	type plain Host
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// Hosts - hosts response
+type Hosts struct {
+	Hosts []*Host `json:"hosts"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Hosts) UnmarshalJSON(bytes []byte) error {
+	type plain Hosts
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Hosts) MarshalJSON() ([]byte, error) {
+	type plain Hosts
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// CreatedHost - added host response
+type CreatedHost struct {
+	HostID HostID `json:"host_id"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *CreatedHost) UnmarshalJSON(bytes []byte) error {
+	type plain CreatedHost
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m CreatedHost) MarshalJSON() ([]byte, error) {
+	type plain CreatedHost
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// Sitemap - sitemap known by robot
+type Sitemap struct {
+	SitemapID  string `json:"sitemap_id"`
+	SitemapURL string `json:"sitemap_url"`
+	// LastAccessDate - last robot access
+	LastAccessDate Timestamp `json:"last_access_date"`
+	// ErrorsCount - number of errors in sitemap
+	ErrorsCount int `json:"errors_count"`
+	// URLsCount - number of urls in sitemap
+	URLsCount int `json:"urls_count"`
+	// ChildrenCount - number of child sitemaps of sitemap index
+	ChildrenCount int `json:"children_count"`
+	// Sources - where robot found sitemap: ROBOTS_TXT, WEBMASTER, INDEX_SITEMAP
+	Sources []string `json:"sources"`
+	// SitemapType - SITEMAP or INDEX_SITEMAP
+	SitemapType string `json:"sitemap_type"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Sitemap) UnmarshalJSON(bytes []byte) error {
+	type plain Sitemap
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Sitemap) MarshalJSON() ([]byte, error) {
+	type plain Sitemap
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// Sitemaps - sitemaps response
+type Sitemaps struct {
+	Sitemaps []*Sitemap `json:"sitemaps"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Sitemaps) UnmarshalJSON(bytes []byte) error {
+	type plain Sitemaps
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Sitemaps) MarshalJSON() ([]byte, error) {
+	type plain Sitemaps
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// AddedUserSitemap - sitemap added by user
+type AddedUserSitemap struct {
+	SitemapID  string `json:"sitemap_id"`
+	SitemapURL string `json:"sitemap_url"`
+	// AddedDate - date of adding
+	AddedDate Timestamp `json:"added_date"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *AddedUserSitemap) UnmarshalJSON(bytes []byte) error {
+	type plain AddedUserSitemap
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m AddedUserSitemap) MarshalJSON() ([]byte, error) {
+	type plain AddedUserSitemap
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// AddedUserSitemaps - user added sitemaps response
+type AddedUserSitemaps struct {
+	Sitemaps []*AddedUserSitemap `json:"sitemaps"`
+	// Count - total number of user added sitemaps
+	Count int `json:"count"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *AddedUserSitemaps) UnmarshalJSON(bytes []byte) error {
+	type plain AddedUserSitemaps
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m AddedUserSitemaps) MarshalJSON() ([]byte, error) {
+	type plain AddedUserSitemaps
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// AddedSitemap - added sitemap response
+type AddedSitemap struct {
+	SitemapID string `json:"sitemap_id"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *AddedSitemap) UnmarshalJSON(bytes []byte) error {
+	type plain AddedSitemap
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m AddedSitemap) MarshalJSON() ([]byte, error) {
+	type plain AddedSitemap
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// IndexingStatus - indexing status of important url
+type IndexingStatus struct {
+	Status   IndexingIndicator `json:"status"`
+	HTTPCode int               `json:"http_code"`
+	// AccessDate - last robot access
+	AccessDate Timestamp `json:"access_date"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *IndexingStatus) UnmarshalJSON(bytes []byte) error {
+	type plain IndexingStatus
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m IndexingStatus) MarshalJSON() ([]byte, error) {
+	type plain IndexingStatus
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchStatus - search status of important url
+type SearchStatus struct {
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	// LastAccess - last robot access
+	LastAccess        Timestamp         `json:"last_access"`
+	ExcludedURLStatus ExcludedURLStatus `json:"excluded_url_status"`
+	BadHTTPStatus     int               `json:"bad_http_status"`
+	Searchable        bool              `json:"searchable"`
+	TargetURL         string            `json:"target_url"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchStatus) UnmarshalJSON(bytes []byte) error {
+	type plain SearchStatus
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchStatus) MarshalJSON() ([]byte, error) {
+	type plain SearchStatus
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// ImportantURL - important url state
+type ImportantURL struct {
+	URL              string            `json:"url"`
+	UpdateDate       Timestamp         `json:"update_date"`
+	ChangeIndicators []ChangeIndicator `json:"change_indicators"`
+	IndexingStatus   *IndexingStatus   `json:"indexing_status"`
+	SearchStatus     *SearchStatus     `json:"search_status"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *ImportantURL) UnmarshalJSON(bytes []byte) error {
+	type plain ImportantURL
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m ImportantURL) MarshalJSON() ([]byte, error) {
+	type plain ImportantURL
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// ImportantURLS - important urls response
+type ImportantURLS struct {
+	URLS []*ImportantURL `json:"urls"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *ImportantURLS) UnmarshalJSON(bytes []byte) error {
+	type plain ImportantURLS
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m ImportantURLS) MarshalJSON() ([]byte, error) {
+	type plain ImportantURLS
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// ImportantURLSHistory - important url history response
+type ImportantURLSHistory struct {
+	History []*ImportantURL `json:"history"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *ImportantURLSHistory) UnmarshalJSON(bytes []byte) error {
+	type plain ImportantURLSHistory
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m ImportantURLSHistory) MarshalJSON() ([]byte, error) {
+	type plain ImportantURLSHistory
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// Indicator - indicator value at date
+type Indicator struct {
+	Date  Timestamp `json:"date"`
+	Value int       `json:"value"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Indicator) UnmarshalJSON(bytes []byte) error {
+	type plain Indicator
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Indicator) MarshalJSON() ([]byte, error) {
+	type plain Indicator
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// IndexingHistory - indexing history time series by indexing indicator
+type IndexingHistory struct {
+	Indicators map[IndexingIndicator][]*Indicator `json:"indicators"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *IndexingHistory) UnmarshalJSON(bytes []byte) error {
+	type plain IndexingHistory
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m IndexingHistory) MarshalJSON() ([]byte, error) {
+	type plain IndexingHistory
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// Sample - indexed page sample
+type Sample struct {
+	Status     IndexingIndicator `json:"status"`
+	HTTPCode   int               `json:"http_code"`
+	URL        string            `json:"url"`
+	AccessDate Timestamp         `json:"access_date"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *Sample) UnmarshalJSON(bytes []byte) error {
+	type plain Sample
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m Sample) MarshalJSON() ([]byte, error) {
+	type plain Sample
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SamplesResult - indexing samples response
+type SamplesResult struct {
+	Count   int       `json:"count"`
+	Samples []*Sample `json:"samples"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SamplesResult) UnmarshalJSON(bytes []byte) error {
+	type plain SamplesResult
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SamplesResult) MarshalJSON() ([]byte, error) {
+	type plain SamplesResult
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// InsearchURLHistoryData - number of pages in search at date
+type InsearchURLHistoryData struct {
+	Date  Timestamp `json:"date"`
+	Value int       `json:"value"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *InsearchURLHistoryData) UnmarshalJSON(bytes []byte) error {
+	type plain InsearchURLHistoryData
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m InsearchURLHistoryData) MarshalJSON() ([]byte, error) {
+	type plain InsearchURLHistoryData
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// InseacrhURLHistory - pages in search history response
+type InseacrhURLHistory struct {
+	History []*InsearchURLHistoryData `json:"history"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *InseacrhURLHistory) UnmarshalJSON(bytes []byte) error {
+	type plain InseacrhURLHistory
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m InseacrhURLHistory) MarshalJSON() ([]byte, error) {
+	type plain InseacrhURLHistory
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// InsearchSample - page in search sample
+type InsearchSample struct {
+	URL        string    `json:"url"`
+	LastAccess Timestamp `json:"last_access"`
+	Title      string    `json:"title"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *InsearchSample) UnmarshalJSON(bytes []byte) error {
+	type plain InsearchSample
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m InsearchSample) MarshalJSON() ([]byte, error) {
+	type plain InsearchSample
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// InsearchSampleResponse - pages in search samples response
+type InsearchSampleResponse struct {
+	Count   int               `json:"count"`
+	Samples []*InsearchSample `json:"samples"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *InsearchSampleResponse) UnmarshalJSON(bytes []byte) error {
+	type plain InsearchSampleResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m InsearchSampleResponse) MarshalJSON() ([]byte, error) {
+	type plain InsearchSampleResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchURLEventHistory - search events history by event
+type SearchURLEventHistory struct {
+	AppeadINSearch    []*Indicator `json:"APPEARED_IN_SEARCH"`
+	RemovedFromSearch []*Indicator `json:"REMOVED_FROM_SEARCH"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchURLEventHistory) UnmarshalJSON(bytes []byte) error {
+	type plain SearchURLEventHistory
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchURLEventHistory) MarshalJSON() ([]byte, error) {
+	type plain SearchURLEventHistory
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchURLEventHistoryResponse - search events history response
+type SearchURLEventHistoryResponse struct {
+	Indicators SearchURLEventHistory `json:"indicators"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchURLEventHistoryResponse) UnmarshalJSON(bytes []byte) error {
+	type plain SearchURLEventHistoryResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchURLEventHistoryResponse) MarshalJSON() ([]byte, error) {
+	type plain SearchURLEventHistoryResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// InsearchEventSample - page appeared in or removed from search
+type InsearchEventSample struct {
+	URL               string            `json:"url"`
+	Title             string            `json:"title"`
+	EventDate         Timestamp         `json:"event_date"`
+	LastAccess        Timestamp         `json:"last_access"`
+	Event             SearchEvent       `json:"event"`
+	ExcludedURLStatus ExcludedURLStatus `json:"excluded_url_status"`
+	BadHTTPStatus     int               `json:"bad_http_status"`
+	TargetURL         string            `json:"target_url"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *InsearchEventSample) UnmarshalJSON(bytes []byte) error {
+	type plain InsearchEventSample
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m InsearchEventSample) MarshalJSON() ([]byte, error) {
+	type plain InsearchEventSample
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// InsearchEventSampleResponse - search events samples response
+type InsearchEventSampleResponse struct {
+	Count   int                    `json:"count"`
+	Samples []*InsearchEventSample `json:"samples"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *InsearchEventSampleResponse) UnmarshalJSON(bytes []byte) error {
+	type plain InsearchEventSampleResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m InsearchEventSampleResponse) MarshalJSON() ([]byte, error) {
+	type plain InsearchEventSampleResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// RecrawlURLResponse - recrawl task created response
+type RecrawlURLResponse struct {
+	TaskID string `json:"task_id"`
+	// QuotaRemainder - recrawl quota left for today
+	QuotaRemainder int `json:"quota_remainder"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *RecrawlURLResponse) UnmarshalJSON(bytes []byte) error {
+	type plain RecrawlURLResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m RecrawlURLResponse) MarshalJSON() ([]byte, error) {
+	type plain RecrawlURLResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// RecrawlTask - recrawl task
+type RecrawlTask struct {
+	TaskID    string           `json:"task_id"`
+	URL       string           `json:"url"`
+	AddedTime Timestamp        `json:"added_time"`
+	State     RecrawlTaskState `json:"state"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *RecrawlTask) UnmarshalJSON(bytes []byte) error {
+	type plain RecrawlTask
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m RecrawlTask) MarshalJSON() ([]byte, error) {
+	type plain RecrawlTask
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// RecrawlTasks - recrawl tasks response
+type RecrawlTasks struct {
+	Tasks []*RecrawlTask `json:"tasks"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *RecrawlTasks) UnmarshalJSON(bytes []byte) error {
+	type plain RecrawlTasks
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m RecrawlTasks) MarshalJSON() ([]byte, error) {
+	type plain RecrawlTasks
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// RecrawlQuota - recrawl quota
+type RecrawlQuota struct {
+	DailyQuota     int `json:"daily_quota"`
+	QuotaRemainder int `json:"quota_remainder"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *RecrawlQuota) UnmarshalJSON(bytes []byte) error {
+	type plain RecrawlQuota
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m RecrawlQuota) MarshalJSON() ([]byte, error) {
+	type plain RecrawlQuota
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchIndicator - search query indicators
+type SearchIndicator struct {
+	TotalShows       float64 `json:"TOTAL_SHOWS"`
+	TotalClicks      float64 `json:"TOTAL_CLICKS"`
+	AvgShowPosition  float64 `json:"AVG_SHOW_POSITION"`
+	AvgClickPosition float64 `json:"AVG_CLICK_POSITION"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchIndicator) UnmarshalJSON(bytes []byte) error {
+	type plain SearchIndicator
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchIndicator) MarshalJSON() ([]byte, error) {
+	type plain SearchIndicator
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// PopularSearchQuery - popular search query
+type PopularSearchQuery struct {
+	QueryID    string          `json:"query_id"`
+	QueryText  string          `json:"query_text"`
+	Indicators SearchIndicator `json:"indicators"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *PopularSearchQuery) UnmarshalJSON(bytes []byte) error {
+	type plain PopularSearchQuery
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m PopularSearchQuery) MarshalJSON() ([]byte, error) {
+	type plain PopularSearchQuery
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// PopularSeachQueryResponse - popular search queries response
+type PopularSeachQueryResponse struct {
+	Queries  []*PopularSearchQuery `json:"queries"`
+	DateFrom Date                  `json:"date_from"`
+	DateTo   Date                  `json:"date_to"`
+	Count    int                   `json:"count"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *PopularSeachQueryResponse) UnmarshalJSON(bytes []byte) error {
+	type plain PopularSeachQueryResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m PopularSeachQueryResponse) MarshalJSON() ([]byte, error) {
+	type plain PopularSeachQueryResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SeachAllHistoryIndicatorData - search query indicator value at date
+type SeachAllHistoryIndicatorData struct {
+	Date  Timestamp `json:"date"`
+	Value float64   `json:"value"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SeachAllHistoryIndicatorData) UnmarshalJSON(bytes []byte) error {
+	type plain SeachAllHistoryIndicatorData
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SeachAllHistoryIndicatorData) MarshalJSON() ([]byte, error) {
+	type plain SeachAllHistoryIndicatorData
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchAllHistoryIndicator - search query indicators history
+type SearchAllHistoryIndicator struct {
+	TotalShows       []*SeachAllHistoryIndicatorData `json:"TOTAL_SHOWS"`
+	TotalClicks      []*SeachAllHistoryIndicatorData `json:"TOTAL_CLICKS"`
+	AvgShowPosition  []*SeachAllHistoryIndicatorData `json:"AVG_SHOW_POSITION"`
+	AvgClickPosition []*SeachAllHistoryIndicatorData `json:"AVG_CLICK_POSITION"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchAllHistoryIndicator) UnmarshalJSON(bytes []byte) error {
+	type plain SearchAllHistoryIndicator
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchAllHistoryIndicator) MarshalJSON() ([]byte, error) {
+	type plain SearchAllHistoryIndicator
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchAllHistoryResponse - all search queries history response
+type SearchAllHistoryResponse struct {
+	Indicators SearchAllHistoryIndicator `json:"indicators"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchAllHistoryResponse) UnmarshalJSON(bytes []byte) error {
+	type plain SearchAllHistoryResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchAllHistoryResponse) MarshalJSON() ([]byte, error) {
+	type plain SearchAllHistoryResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// SearchSingleHistoryResponse - single search query history response
+type SearchSingleHistoryResponse struct {
+	QueryID    string                    `json:"query_id"`
+	QueryText  string                    `json:"query_text"`
+	Indicators SearchAllHistoryIndicator `json:"indicators"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *SearchSingleHistoryResponse) UnmarshalJSON(bytes []byte) error {
+	type plain SearchSingleHistoryResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m SearchSingleHistoryResponse) MarshalJSON() ([]byte, error) {
+	type plain SearchSingleHistoryResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// DiagnosticProblemsResponse - site diagnostics response
+type DiagnosticProblemsResponse struct {
+	Problems DiagnosticProblems `json:"problems"`
+	// Extra - fields unknown to library
+	Extra map[string]json.RawMessage `json:"-"`
+}
+
+func (m *DiagnosticProblemsResponse) UnmarshalJSON(bytes []byte) error {
+	type plain DiagnosticProblemsResponse
+	extra, err := unmarshalWithExtra(bytes, (*plain)(m))
+	m.Extra = extra
+	return err
+}
+
+func (m DiagnosticProblemsResponse) MarshalJSON() ([]byte, error) {
+	type plain DiagnosticProblemsResponse
+	return marshalWithExtra(plain(m), m.Extra)
+}
+
+// String returns value as is
+func (v IndexingIndicator) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v IndexingIndicator) IsKnown() bool {
+	switch v {
+	case "HTTP_2XX", "HTTP_3XX", "HTTP_4XX", "HTTP_5XX", "OTHER":
+		return true
+	}
+	return false
+}
+
+func (v IndexingIndicator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *IndexingIndicator) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = IndexingIndicator(value)
+	return err
+}
+
+// String returns value as is
+func (v ChangeIndicator) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v ChangeIndicator) IsKnown() bool {
+	switch v {
+	case "INDEXING_HTTP_CODE", "SEARCH_STATUS", "TITLE", "DESCRIPTION":
+		return true
+	}
+	return false
+}
+
+func (v ChangeIndicator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *ChangeIndicator) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = ChangeIndicator(value)
+	return err
+}
+
+// String returns value as is
+func (v ExcludedURLStatus) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v ExcludedURLStatus) IsKnown() bool {
+	switch v {
+	case "NOTHING_FOUND", "HOST_ERROR", "REDIRECT_NOTSEARCHABLE", "HTTP_ERROR", "NOT_CANONICAL", "NOT_MAIN_MIRROR", "PARSER_ERROR", "ROBOTS_HOST_ERROR", "ROBOTS_URL_ERROR", "DUPLICATE", "LOW_QUALITY", "CLEAN_PARAMS", "NO_INDEX", "OTHER":
+		return true
+	}
+	return false
+}
+
+func (v ExcludedURLStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *ExcludedURLStatus) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = ExcludedURLStatus(value)
+	return err
+}
+
+// String returns value as is
+func (v SearchEvent) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v SearchEvent) IsKnown() bool {
+	switch v {
+	case "APPEARED_IN_SEARCH", "REMOVED_FROM_SEARCH":
+		return true
+	}
+	return false
+}
+
+func (v SearchEvent) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *SearchEvent) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = SearchEvent(value)
+	return err
+}
+
+// String returns value as is
+func (v RecrawlTaskState) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v RecrawlTaskState) IsKnown() bool {
+	switch v {
+	case "IN_PROGRESS", "DONE", "FAILED":
+		return true
+	}
+	return false
+}
+
+func (v RecrawlTaskState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *RecrawlTaskState) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = RecrawlTaskState(value)
+	return err
+}
+
+// String returns value as is
+func (v QueryIndicator) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v QueryIndicator) IsKnown() bool {
+	switch v {
+	case "TOTAL_SHOWS", "TOTAL_CLICKS", "AVG_SHOW_POSITION", "AVG_CLICK_POSITION":
+		return true
+	}
+	return false
+}
+
+func (v QueryIndicator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *QueryIndicator) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = QueryIndicator(value)
+	return err
+}
+
+// String returns value as is
+func (v DeviceTypeIndicator) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v DeviceTypeIndicator) IsKnown() bool {
+	switch v {
+	case "ALL", "DESKTOP", "MOBILE_AND_TABLET", "MOBILE", "TABLET":
+		return true
+	}
+	return false
+}
+
+func (v DeviceTypeIndicator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *DeviceTypeIndicator) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = DeviceTypeIndicator(value)
+	return err
+}
+
+// String returns value as is
+func (v DiagnosticSeverity) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v DiagnosticSeverity) IsKnown() bool {
+	switch v {
+	case "FATAL", "CRITICAL", "POSSIBLE_PROBLEM", "RECOMMENDATION":
+		return true
+	}
+	return false
+}
+
+func (v DiagnosticSeverity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *DiagnosticSeverity) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = DiagnosticSeverity(value)
+	return err
+}
+
+// String returns value as is
+func (v DiagnosticState) String() string {
+	return string(v)
+}
+
+// IsKnown reports whether value is known by library
+func (v DiagnosticState) IsKnown() bool {
+	switch v {
+	case "PRESENT", "ABSENT", "UNDEFINED":
+		return true
+	}
+	return false
+}
+
+func (v DiagnosticState) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(v))
+}
+
+func (v *DiagnosticState) UnmarshalJSON(bytes []byte) error {
+	value, err := unmarshalEnum(bytes)
+	*v = DiagnosticState(value)
+	return err
+}
+
+// SitemapsRequest - params of getSitemaps requests
+type SitemapsRequest struct {
+	// ParentID - id of parent sitemap index, top level sitemaps if empty
+	ParentID string
+	// Limit - number of sitemaps, 10 by default
+	Limit int
+	// From - sitemap id to continue listing after
+	From string
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r SitemapsRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r SitemapsRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.limit("Limit", r.Limit, 100)
+	return v.err()
+}
+
+func (r SitemapsRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	if r.ParentID != "" {
+		data["parent_id"] = r.ParentID
+	}
+	setIntParam(data, "limit", r.Limit)
+	if r.From != "" {
+		data["from"] = r.From
+	}
+	return data
+}
+
+// UserAddedSitemapsRequest - params of getUserAddedSitemaps requests
+type UserAddedSitemapsRequest struct {
+	// Offset - number of sitemaps to skip
+	Offset int
+	// Limit - number of sitemaps, 10 by default
+	Limit int
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r UserAddedSitemapsRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r UserAddedSitemapsRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.offset("Offset", r.Offset)
+	v.limit("Limit", r.Limit, 100)
+	return v.err()
+}
+
+func (r UserAddedSitemapsRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setIntParam(data, "offset", r.Offset)
+	setIntParam(data, "limit", r.Limit)
+	return data
+}
+
+// IndexingHistoryRequest - params of getIndexingHistory requests
+type IndexingHistoryRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	// Indicators - limit returned status classes, all if empty
+	Indicators []IndexingIndicator
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r IndexingHistoryRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r IndexingHistoryRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.dateRange("DateFrom", r.DateFrom, "DateTo", r.DateTo)
+	for _, value := range r.Indicators {
+		v.enum("Indicators", value, value.IsKnown())
+	}
+	return v.err()
+}
+
+func (r IndexingHistoryRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setDateParam(data, "date_from", r.DateFrom)
+	setDateParam(data, "date_to", r.DateTo)
+	if len(r.Indicators) != 0 {
+		data["indexing_indicator"] = r.Indicators
+	}
+	return data
+}
+
+// IndexingSamplesRequest - params of getIndexingSamples requests
+type IndexingSamplesRequest struct {
+	Limit  int
+	Offset int
+	// Indicators - limit returned status classes, all if empty
+	Indicators []IndexingIndicator
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r IndexingSamplesRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r IndexingSamplesRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.limit("Limit", r.Limit, 100)
+	v.offset("Offset", r.Offset)
+	for _, value := range r.Indicators {
+		v.enum("Indicators", value, value.IsKnown())
+	}
+	return v.err()
+}
+
+func (r IndexingSamplesRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setIntParam(data, "limit", r.Limit)
+	setIntParam(data, "offset", r.Offset)
+	if len(r.Indicators) != 0 {
+		data["indexing_indicator"] = r.Indicators
+	}
+	return data
+}
+
+// InsearchURLHistoryRequest - params of getInsearchHistory and getSearchEventsHistory requests
+type InsearchURLHistoryRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r InsearchURLHistoryRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r InsearchURLHistoryRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.dateRange("DateFrom", r.DateFrom, "DateTo", r.DateTo)
+	return v.err()
+}
+
+func (r InsearchURLHistoryRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setDateParam(data, "date_from", r.DateFrom)
+	setDateParam(data, "date_to", r.DateTo)
+	return data
+}
+
+// InsearchURLSamplesRequest - params of getInsearchSamples and getSearchEventsSamples requests
+type InsearchURLSamplesRequest struct {
+	Limit  int
+	Offset int
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r InsearchURLSamplesRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r InsearchURLSamplesRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.limit("Limit", r.Limit, 100)
+	v.offset("Offset", r.Offset)
+	return v.err()
+}
+
+func (r InsearchURLSamplesRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setIntParam(data, "limit", r.Limit)
+	setIntParam(data, "offset", r.Offset)
+	return data
+}
+
+// RecrawlTasksRequest - params of getRecrawlTasks requests
+type RecrawlTasksRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	Limit    int
+	Offset   int
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r RecrawlTasksRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r RecrawlTasksRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.dateRange("DateFrom", r.DateFrom, "DateTo", r.DateTo)
+	v.limit("Limit", r.Limit, 100)
+	v.offset("Offset", r.Offset)
+	return v.err()
+}
+
+func (r RecrawlTasksRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setDateParam(data, "date_from", r.DateFrom)
+	setDateParam(data, "date_to", r.DateTo)
+	setIntParam(data, "limit", r.Limit)
+	setIntParam(data, "offset", r.Offset)
+	return data
+}
+
+// PopularQueriesRequest - params of getPopularQueries requests
+type PopularQueriesRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	// QueryIndicators - indicators requested in one call
+	QueryIndicators []QueryIndicator
+	// OrderBy - TOTAL_SHOWS (default) or TOTAL_CLICKS
+	OrderBy QueryIndicator
+	// DeviceType - ALL by default
+	DeviceType DeviceTypeIndicator
+	Limit      int
+	Offset     int
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r PopularQueriesRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r PopularQueriesRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.dateRange("DateFrom", r.DateFrom, "DateTo", r.DateTo)
+	for _, value := range r.QueryIndicators {
+		v.enum("QueryIndicators", value, value.IsKnown())
+	}
+	if r.OrderBy != "" && r.OrderBy != "TOTAL_SHOWS" && r.OrderBy != "TOTAL_CLICKS" {
+		v.add("OrderBy", r.OrderBy, "must be one of TOTAL_SHOWS, TOTAL_CLICKS")
+	}
+	if r.DeviceType != "" {
+		v.enum("DeviceType", r.DeviceType, r.DeviceType.IsKnown())
+	}
+	v.limit("Limit", r.Limit, 500)
+	v.offset("Offset", r.Offset)
+	return v.err()
+}
+
+func (r PopularQueriesRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setDateParam(data, "date_from", r.DateFrom)
+	setDateParam(data, "date_to", r.DateTo)
+	if len(r.QueryIndicators) != 0 {
+		data["query_indicator"] = r.QueryIndicators
+	}
+	if r.OrderBy != "" {
+		data["order_by"] = r.OrderBy
+	} else {
+		data["order_by"] = QueryIndicator("TOTAL_SHOWS")
+	}
+	if r.DeviceType != "" {
+		data["device_type_indicator"] = r.DeviceType
+	} else {
+		data["device_type_indicator"] = DeviceTypeIndicator("ALL")
+	}
+	setIntParam(data, "limit", r.Limit)
+	setIntParam(data, "offset", r.Offset)
+	return data
+}
+
+// QueryHistoryRequest - params of getQueriesAllHistory and getQueryHistory requests
+type QueryHistoryRequest struct {
+	DateFrom time.Time
+	DateTo   time.Time
+	// QueryIndicators - indicators requested in one call
+	QueryIndicators []QueryIndicator
+	// DeviceType - ALL by default
+	DeviceType DeviceTypeIndicator
+}
+
+// Validate checks request before sending, dates are checked in DefaultLocation
+func (r QueryHistoryRequest) Validate() error {
+	return r.validate(DefaultLocation)
+}
+
+// validate checks request with dates in loc
+func (r QueryHistoryRequest) validate(loc *time.Location) error {
+	v := &validator{loc: loc}
+	v.dateRange("DateFrom", r.DateFrom, "DateTo", r.DateTo)
+	for _, value := range r.QueryIndicators {
+		v.enum("QueryIndicators", value, value.IsKnown())
+	}
+	if r.DeviceType != "" {
+		v.enum("DeviceType", r.DeviceType, r.DeviceType.IsKnown())
+	}
+	return v.err()
+}
+
+func (r QueryHistoryRequest) params() map[string]interface{} {
+	data := make(map[string]interface{})
+	setDateParam(data, "date_from", r.DateFrom)
+	setDateParam(data, "date_to", r.DateTo)
+	if len(r.QueryIndicators) != 0 {
+		data["query_indicator"] = r.QueryIndicators
+	}
+	if r.DeviceType != "" {
+		data["device_type_indicator"] = r.DeviceType
+	} else {
+		data["device_type_indicator"] = DeviceTypeIndicator("ALL")
+	}
+	return data
+}
@@ -0,0 +1,117 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+type enum interface {
+	String() string
+	IsKnown() bool
+}
+
+func TestEnumsKnown(t *testing.T) {
+	known := []enum{
+		IndexingIndicatorHTTP2XX, IndexingIndicatorOther,
+		ChangeIndicatorIndexingHTTPCode, ChangeIndicatorDescription,
+		ExcludedURLStatusNothingFound, ExcludedURLStatusRedirectNotSearchable, ExcludedURLStatusOther,
+		SearchEventAppearedInSearch, SearchEventRemovedFromSearch,
+		RecrawlTaskStateInProgress, RecrawlTaskStateFailed,
+		QueryIndicatorTotalShows, QueryIndicatorAvgClickPosition,
+		DeviceTypeAll, DeviceTypeMobileAndTablet,
+		DiagnosticSeverityFatal, DiagnosticSeverityRecommendation,
+		DiagnosticStatePresent, DiagnosticStateUndefined,
+	}
+	for _, value := range known {
+		if !value.IsKnown() {
+			t.Errorf("%T(%s).IsKnown() = false", value, value)
+		}
+	}
+	unknown := []enum{IndexingIndicator("HTTP_1XX"), ExcludedURLStatus(""), RecrawlTaskState("done"), DeviceTypeIndicator("TV")}
+	for _, value := range unknown {
+		if value.IsKnown() {
+			t.Errorf("%T(%q).IsKnown() = true", value, value)
+		}
+	}
+}
+
+func TestEnumJSON(t *testing.T) {
+	tests := []struct {
+		input string
+		want  RecrawlTaskState
+		out   string
+	}{
+		{input: `"DONE"`, want: RecrawlTaskStateDone, out: `"DONE"`},
+		{input: `"QUEUED"`, want: RecrawlTaskState("QUEUED"), out: `"QUEUED"`},
+		{input: `null`, want: "", out: `""`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			var got RecrawlTaskState
+			if err := json.Unmarshal([]byte(tt.input), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("Unmarshal() = %q, want %q", got, tt.want)
+			}
+			out, err := json.Marshal(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(out) != tt.out {
+				t.Errorf("Marshal() = %s, want %s", out, tt.out)
+			}
+		})
+	}
+	var state RecrawlTaskState
+	if err := json.Unmarshal([]byte(`1`), &state); err == nil {
+		t.Error("Unmarshal(1) succeeded")
+	}
+}
+
+func TestRecrawlTaskStateIsTerminal(t *testing.T) {
+	tests := []struct {
+		state RecrawlTaskState
+		want  bool
+	}{
+		{state: RecrawlTaskStateInProgress, want: false},
+		{state: RecrawlTaskStateDone, want: true},
+		{state: RecrawlTaskStateFailed, want: true},
+		{state: RecrawlTaskState("QUEUED"), want: false},
+		{state: "", want: false},
+	}
+	for _, tt := range tests {
+		if got := tt.state.IsTerminal(); got != tt.want {
+			t.Errorf("%q.IsTerminal() = %v, want %v", tt.state, got, tt.want)
+		}
+	}
+}
+
+func TestExcludedURLStatusIsExcluded(t *testing.T) {
+	tests := []struct {
+		status ExcludedURLStatus
+		want   bool
+	}{
+		{status: "", want: false},
+		{status: ExcludedURLStatusNothingFound, want: false},
+		{status: ExcludedURLStatusHostError, want: true},
+		{status: ExcludedURLStatusRedirectNotSearchable, want: true},
+		{status: ExcludedURLStatusHTTPError, want: true},
+		{status: ExcludedURLStatusNotCanonical, want: true},
+		{status: ExcludedURLStatusNotMainMirror, want: true},
+		{status: ExcludedURLStatusParserError, want: true},
+		{status: ExcludedURLStatusRobotsHostError, want: true},
+		{status: ExcludedURLStatusRobotsURLError, want: true},
+		{status: ExcludedURLStatusDuplicate, want: true},
+		{status: ExcludedURLStatusLowQuality, want: true},
+		{status: ExcludedURLStatusCleanParams, want: true},
+		{status: ExcludedURLStatusNoIndex, want: true},
+		{status: ExcludedURLStatusOther, want: true},
+		{status: ExcludedURLStatus("NEW_REASON"), want: true},
+	}
+	for _, tt := range tests {
+		if got := tt.status.IsExcluded(); got != tt.want {
+			t.Errorf("%q.IsExcluded() = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
@@ -1,9 +1,8 @@
 package yandexwebmaster
 
 import (
-	"fmt"
 	"net/http"
-	"time"
+	"net/url"
 )
 
 // RecrawlService - service for manage recrawl tasks
@@ -16,63 +15,66 @@ func newRecrawlService(cl *Client) *RecrawlService {
 	return &RecrawlService{client: cl}
 }
 
-// RecrawlURLResponse ...
-type RecrawlURLResponse struct {
-	TaskID string `json:"task_id"`
-	URL    string `json:"url"`
-}
-
-type RecrawlTask struct {
-	TaskID    string `json:"task_id"`
-	URL       string `json:"url"`
-	AddedTime string `json:"added_time"`
-	State     string `json:"state"`
-}
+// RecrawlTaskState - state of recrawl task
+type RecrawlTaskState string
 
-type RecrawlTasks struct {
-	Tasks []*RecrawlTask `json:"tasks"`
-}
+const (
+	RecrawlTaskStateInProgress RecrawlTaskState = "IN_PROGRESS"
+	RecrawlTaskStateDone       RecrawlTaskState = "DONE"
+	RecrawlTaskStateFailed     RecrawlTaskState = "FAILED"
+)
 
-type RecrawlQuota struct {
-	DailyQuota     int `json:"daily_quota"`
-	QuotaRemainder int `json:"quota_remainder"`
+// IsTerminal reports whether task will not change state anymore
+func (s RecrawlTaskState) IsTerminal() bool {
+	return s == RecrawlTaskStateDone || s == RecrawlTaskStateFailed
 }
 
 // start recrawl url, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-recrawl-post.html
-func (s *RecrawlService) RecrawlURL(hostID string, url string) (RecrawlURLResponse, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/recrawl/queue", s.client.userID, hostID)
+func (s *RecrawlService) RecrawlURL(hostID HostID, url string) (RecrawlURLResponse, error) {
+	var result RecrawlURLResponse
+	endpoint, err := s.client.hostEndpoint(hostID, "recrawl/queue")
+	if err != nil {
+		return result, err
+	}
 	data := make(map[string]interface{})
 	data["url"] = url
-	var result RecrawlURLResponse
-	_, err := s.client.sendAPIRequest(http.MethodPost, endpoint, data, &result)
+	_, err = s.client.sendAPIRequest(http.MethodPost, endpoint, data, &result)
 	return result, err
 }
 
 // get recrawl task, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-recrawl-task-get.html
-func (s *RecrawlService) GetRecrawlTask(hostID string, taskID string) (RecrawlTask, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/recrawl/queue/%s", s.client.userID, hostID, taskID)
+func (s *RecrawlService) GetRecrawlTask(hostID HostID, taskID string) (RecrawlTask, error) {
 	var result RecrawlTask
-	_, err := s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "recrawl/queue/"+url.PathEscape(taskID))
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
 	return result, err
 }
 
 // get recrawl tasks, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-recrawl-get.html
-func (s *RecrawlService) GetRecrawlTasks(hostID string, dateFrom time.Time, dateTo time.Time, limit int, offset int) (RecrawlTasks, error) {
-	data := make(map[string]interface{})
-	data["date_from"] = dateFrom.Format(YYYYMMDD)
-	data["date_to"] = dateTo.Format(YYYYMMDD)
-	data["limit"] = limit
-	data["offset"] = limit
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/recrawl/queue", s.client.userID, hostID)
+// The range is sent as single request because Limit and Offset page over the whole range.
+func (s *RecrawlService) GetRecrawlTasks(hostID HostID, req RecrawlTasksRequest) (RecrawlTasks, error) {
 	var result RecrawlTasks
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "recrawl/queue")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.makeGETRequestWithParams(endpoint, req.params(), &result)
 	return result, err
 }
 
 // get recrawl quota, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-recrawl-quota-get.html
-func (s *RecrawlService) GetRecrawlQuota(hostID string) (RecrawlQuota, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/recrawl/quota", s.client.userID, hostID)
+func (s *RecrawlService) GetRecrawlQuota(hostID HostID) (RecrawlQuota, error) {
 	var result RecrawlQuota
-	_, err := s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "recrawl/quota")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
 	return result, err
 }
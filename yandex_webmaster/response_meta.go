@@ -0,0 +1,114 @@
+package yandexwebmaster
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// request id headers returned by api
+var requestIDHeaders = []string{"X-Request-Id", "X-Req-Id"}
+
+// RateLimit - rate limit headers of response, -1 if header is missing
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     int
+}
+
+// ResponseMeta - metadata of single api response
+type ResponseMeta struct {
+	Method     string
+	Endpoint   string
+	StatusCode int
+	Header     http.Header
+	RequestID  string
+	Latency    time.Duration
+	RateLimit  RateLimit
+	// Body - raw response body, filled only with ResponseRecorder.CaptureBody
+	Body []byte
+}
+
+// ResponseRecorder - collects metadata of responses made by client returned from Client.WithResponseRecorder
+type ResponseRecorder struct {
+	// CaptureBody - keep raw response body in ResponseMeta
+	CaptureBody bool
+
+	mu        sync.Mutex
+	responses []*ResponseMeta
+}
+
+// Last returns metadata of the last response or nil
+func (r *ResponseRecorder) Last() *ResponseMeta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if len(r.responses) == 0 {
+		return nil
+	}
+	return r.responses[len(r.responses)-1]
+}
+
+// All returns metadata of all recorded responses in order of arrival
+func (r *ResponseRecorder) All() []*ResponseMeta {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	responses := make([]*ResponseMeta, len(r.responses))
+	copy(responses, r.responses)
+	return responses
+}
+
+// Reset removes recorded responses
+func (r *ResponseRecorder) Reset() {
+	r.mu.Lock()
+	r.responses = nil
+	r.mu.Unlock()
+}
+
+// record adds response metadata, resp may be nil on transport errors
+func (r *ResponseRecorder) record(method string, endpoint string, resp *http.Response, body []byte, latency time.Duration) {
+	meta := &ResponseMeta{
+		Method:    method,
+		Endpoint:  endpoint,
+		Latency:   latency,
+		RateLimit: RateLimit{Limit: -1, Remaining: -1, Reset: -1},
+	}
+	if resp != nil {
+		meta.StatusCode = resp.StatusCode
+		meta.Header = resp.Header.Clone()
+		for _, h := range requestIDHeaders {
+			if id := resp.Header.Get(h); id != "" {
+				meta.RequestID = id
+				break
+			}
+		}
+		meta.RateLimit = RateLimit{
+			Limit:     headerInt(resp.Header, "X-RateLimit-Limit"),
+			Remaining: headerInt(resp.Header, "X-RateLimit-Remaining"),
+			Reset:     headerInt(resp.Header, "X-RateLimit-Reset"),
+		}
+	}
+	if r.CaptureBody {
+		meta.Body = body
+	}
+	r.mu.Lock()
+	r.responses = append(r.responses, meta)
+	r.mu.Unlock()
+}
+
+// headerInt returns int header value or -1
+func headerInt(header http.Header, key string) int {
+	value, err := strconv.Atoi(header.Get(key))
+	if err != nil {
+		return -1
+	}
+	return value
+}
+
+// WithResponseRecorder returns copy of client which records metadata of every response to recorder
+func (c *Client) WithResponseRecorder(recorder *ResponseRecorder) *Client {
+	cl := *c
+	cl.recorder = recorder
+	cl.initServices()
+	return &cl
+}
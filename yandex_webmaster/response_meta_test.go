@@ -0,0 +1,180 @@
+package yandexwebmaster
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// newMetaServer returns server answering user and hosts requests with headers
+func newMetaServer(t *testing.T, header http.Header, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"user_id":1}`))
+		case "/user/1/hosts":
+			time.Sleep(delay)
+			for key, values := range header {
+				w.Header()[key] = values
+			}
+			w.Write([]byte(`{"hosts":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestResponseRecorderFields(t *testing.T) {
+	tests := []struct {
+		name          string
+		header        http.Header
+		wantRequestID string
+		wantRateLimit RateLimit
+	}{
+		{
+			name:          "no headers",
+			wantRateLimit: RateLimit{Limit: -1, Remaining: -1, Reset: -1},
+		},
+		{
+			name:          "x-request-id",
+			header:        http.Header{"X-Request-Id": {"abc"}, "X-Req-Id": {"other"}},
+			wantRequestID: "abc",
+			wantRateLimit: RateLimit{Limit: -1, Remaining: -1, Reset: -1},
+		},
+		{
+			name:          "x-req-id",
+			header:        http.Header{"X-Req-Id": {"def"}},
+			wantRequestID: "def",
+			wantRateLimit: RateLimit{Limit: -1, Remaining: -1, Reset: -1},
+		},
+		{
+			name:          "rate limit",
+			header:        http.Header{"X-Ratelimit-Limit": {"100"}, "X-Ratelimit-Remaining": {"7"}, "X-Ratelimit-Reset": {"30"}},
+			wantRateLimit: RateLimit{Limit: 100, Remaining: 7, Reset: 30},
+		},
+		{
+			name:          "invalid rate limit",
+			header:        http.Header{"X-Ratelimit-Limit": {"many"}, "X-Ratelimit-Remaining": {"3"}},
+			wantRateLimit: RateLimit{Limit: -1, Remaining: 3, Reset: -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMetaServer(t, tt.header, 0)
+			client, err := NewClient("token", WithBaseURL(server.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+			recorder := &ResponseRecorder{}
+			if _, err := client.WithResponseRecorder(recorder).Hosts.GetHosts(); err != nil {
+				t.Fatal(err)
+			}
+			meta := recorder.Last()
+			if meta == nil {
+				t.Fatal("Last() = nil")
+			}
+			if meta.Method != http.MethodGet || meta.Endpoint != "user/1/hosts" || meta.StatusCode != http.StatusOK {
+				t.Errorf("meta = %s %s %d", meta.Method, meta.Endpoint, meta.StatusCode)
+			}
+			if meta.RequestID != tt.wantRequestID {
+				t.Errorf("RequestID = %q, want %q", meta.RequestID, tt.wantRequestID)
+			}
+			if meta.RateLimit != tt.wantRateLimit {
+				t.Errorf("RateLimit = %+v, want %+v", meta.RateLimit, tt.wantRateLimit)
+			}
+			if meta.Body != nil {
+				t.Errorf("Body = %s without CaptureBody", meta.Body)
+			}
+		})
+	}
+}
+
+func TestResponseRecorderLatencyAndBody(t *testing.T) {
+	server := newMetaServer(t, nil, 20*time.Millisecond)
+	client, err := NewClient("token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := &ResponseRecorder{CaptureBody: true}
+	if _, err := client.WithResponseRecorder(recorder).Hosts.GetHosts(); err != nil {
+		t.Fatal(err)
+	}
+	meta := recorder.Last()
+	if meta.Latency < 20*time.Millisecond {
+		t.Errorf("Latency = %s, want at least 20ms", meta.Latency)
+	}
+	if string(meta.Body) != `{"hosts":[]}` {
+		t.Errorf("Body = %s", meta.Body)
+	}
+	if meta.Header.Get("Content-Type") == "" {
+		t.Errorf("Header = %v", meta.Header)
+	}
+}
+
+func TestResponseRecorderStatusAndTransportError(t *testing.T) {
+	server := newMetaServer(t, nil, 0)
+	client, err := NewClient("token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := &ResponseRecorder{}
+	recording := client.WithResponseRecorder(recorder)
+	if _, err := recording.Hosts.GetHost("https:example.com:443"); err == nil {
+		t.Fatal("GetHost() of unknown host succeeded")
+	}
+	server.Close()
+	if _, err := recording.Hosts.GetHosts(); err == nil {
+		t.Fatal("GetHosts() of closed server succeeded")
+	}
+	var got []int
+	for _, meta := range recorder.All() {
+		got = append(got, meta.StatusCode)
+	}
+	if want := []int{http.StatusNotFound, 0}; !reflect.DeepEqual(got, want) {
+		t.Errorf("statuses = %v, want %v", got, want)
+	}
+	if last := recorder.Last(); last.RateLimit.Limit != -1 || last.Header != nil {
+		t.Errorf("transport error meta = %+v", last)
+	}
+	recorder.Reset()
+	if recorder.Last() != nil || len(recorder.All()) != 0 {
+		t.Error("Reset() kept responses")
+	}
+}
+
+func TestWithResponseRecorderKeepsOriginalClient(t *testing.T) {
+	server := newMetaServer(t, nil, 0)
+	client, err := NewClient("token", WithBaseURL(server.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts := client.Hosts
+	recorder := &ResponseRecorder{}
+	recording := client.WithResponseRecorder(recorder)
+	if recording == client || recording.Hosts == client.Hosts {
+		t.Fatal("WithResponseRecorder() shares client or services")
+	}
+	if client.recorder != nil || client.Hosts != hosts || client.Hosts.client != client {
+		t.Error("original client is changed")
+	}
+	if recording.Hosts.client != recording || recording.Sitemaps.client != recording {
+		t.Error("services of copy use other client")
+	}
+	if _, err := client.Hosts.GetHosts(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(recorder.All()); n != 0 {
+		t.Errorf("original client recorded %d responses", n)
+	}
+	if _, err := recording.Hosts.GetHosts(); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(recorder.All()); n != 1 {
+		t.Errorf("copy recorded %d responses, want 1", n)
+	}
+}
@@ -1,7 +1,7 @@
 package yandexwebmaster
 
 import (
-	"fmt"
+	"net/url"
 	"time"
 )
 
@@ -15,100 +15,86 @@ func newSearchQueryService(cl *Client) *SearchQueryService {
 	return &SearchQueryService{client: cl}
 }
 
-type SearchIndicator struct {
-	TotalShows       float64 `json:"TOTAL_SHOWS"`
-	TotalClicks      float64 `json:"TOTAL_CLICKS"`
-	AvgShowPosition  float64 `json:"AVG_SHOW_POSITION"`
-	AvgClickPosition float64 `json:"AVG_CLICK_POSITION"`
-}
-
-type PopularSearchQuery struct {
-	QueryID    string          `json:"query_id"`
-	QueryText  string          `json:"query_text"`
-	Indicators SearchIndicator `json:"indicators"`
-}
-
-type PopularSeachQueryResponse struct {
-	Queries  []*PopularSearchQuery `json:"queries"`
-	DateFrom string                `json:"date_from"`
-	DateTo   string                `json:"date_to"`
-	Count    int                   `json:"count"`
-}
+// QueryIndicator - search query indicator
+type QueryIndicator string
 
-type SeachAllHistoryIndicatorData struct {
-	Date  string  `json:"date"`
-	Value float64 `json:"value"`
-}
+const (
+	QueryIndicatorTotalShows       QueryIndicator = "TOTAL_SHOWS"
+	QueryIndicatorTotalClicks      QueryIndicator = "TOTAL_CLICKS"
+	QueryIndicatorAvgShowPosition  QueryIndicator = "AVG_SHOW_POSITION"
+	QueryIndicatorAvgClickPosition QueryIndicator = "AVG_CLICK_POSITION"
+)
 
-type SearchAllHistoryIndicator struct {
-	TotalShows  []*SeachAllHistoryIndicatorData `json:"TOTAL_SHOWS"`
-	TotalClicks []*SeachAllHistoryIndicatorData `json:"TOTAL_Clicks"`
+// AllQueryIndicators - all known search query indicators
+var AllQueryIndicators = []QueryIndicator{
+	QueryIndicatorTotalShows,
+	QueryIndicatorTotalClicks,
+	QueryIndicatorAvgShowPosition,
+	QueryIndicatorAvgClickPosition,
 }
 
-type SearchAllHistoryResponse struct {
-	Indicators SearchAllHistoryIndicator `json:"indicators"`
-}
+// DeviceTypeIndicator - device type of search queries
+type DeviceTypeIndicator string
 
-type SearchSingleHistoryResponse struct {
-	QueryID    string                    `json:"query_id"`
-	QueryText  string                    `json:"query_text"`
-	Indicators SearchAllHistoryIndicator `json:"indicators"`
-}
+const (
+	DeviceTypeAll             DeviceTypeIndicator = "ALL"
+	DeviceTypeDesktop         DeviceTypeIndicator = "DESKTOP"
+	DeviceTypeMobileAndTablet DeviceTypeIndicator = "MOBILE_AND_TABLET"
+	DeviceTypeMobile          DeviceTypeIndicator = "MOBILE"
+	DeviceTypeTablet          DeviceTypeIndicator = "TABLET"
+)
 
 // GetPopularSearchQueries - get popular queries, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-search-queries-popular.html
-func (s *SearchQueryService) GetPopularSearchQueries(hostID string, dateFrom time.Time, dateTo time.Time, queryIndicator string, orderBy string, deviceTypeIndicator string, limit int, offset int) (PopularSeachQueryResponse, error) {
-	data := make(map[string]interface{})
-	data["date_from"] = dateFrom.Format(YYYYMMDD)
-	data["date_to"] = dateTo.Format(YYYYMMDD)
-	data["query_indicator"] = queryIndicator
-	if orderBy != "" {
-		data["order_by"] = orderBy
-	} else {
-		data["order_by"] = "TOTAL_SHOWS"
+func (s *SearchQueryService) GetPopularSearchQueries(hostID HostID, req PopularQueriesRequest) (PopularSeachQueryResponse, error) {
+	var result PopularSeachQueryResponse
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
 	}
-	if deviceTypeIndicator != "" {
-		data["device_type_indicator"] = deviceTypeIndicator
-	} else {
-		data["device_type_indicator"] = "ALL"
+	endpoint, err := s.client.hostEndpoint(hostID, "search-queries/popular")
+	if err != nil {
+		return result, err
 	}
-	data["limit"] = limit
-	data["offset"] = offset
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/search-queries/popular", s.client.userID, hostID)
-	var result PopularSeachQueryResponse
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
+	_, err = s.client.makeGETRequestWithParams(endpoint, req.params(), &result)
 	return result, err
 }
 
 // GetQueryAllHistory - get all query history, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-search-queries-history-all.html
-func (s *SearchQueryService) GetQueryAllHistory(hostID string, dateFrom time.Time, dateTo time.Time, queryIndicator string, deviceTypeIndicator string) (SearchAllHistoryResponse, error) {
-	data := make(map[string]interface{})
-	data["date_from"] = dateFrom.Format(YYYYMMDD)
-	data["date_to"] = dateTo.Format(YYYYMMDD)
-	data["query_indicator"] = queryIndicator
-	if deviceTypeIndicator != "" {
-		data["device_type_indicator"] = deviceTypeIndicator
-	} else {
-		data["device_type_indicator"] = "ALL"
-	}
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/search-queries/all/history", s.client.userID, hostID)
+// Long ranges are split by client HistoryLimits and fetched concurrently, failed windows are reported by WindowsError
+func (s *SearchQueryService) GetQueryAllHistory(hostID HostID, req QueryHistoryRequest) (SearchAllHistoryResponse, error) {
 	var result SearchAllHistoryResponse
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
-	return result, err
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "search-queries/all/history")
+	if err != nil {
+		return result, err
+	}
+	windows := s.client.historyWindows(req.DateFrom, req.DateTo)
+	parts := make([]SearchAllHistoryResponse, len(windows))
+	err = s.client.fetchWindows(windows, func(i int, from time.Time, to time.Time) error {
+		windowReq := req
+		windowReq.DateFrom, windowReq.DateTo = from, to
+		_, err := s.client.makeGETRequestWithParams(endpoint, windowReq.params(), &parts[i])
+		return err
+	})
+	return mergeSearchAllHistory(parts), err
 }
 
-// SearchSingleHistoryResponse - get sing search query history, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-search-queries-history.html
-func (s *SearchQueryService) GetSingleSearchQueryHistory(hostID string, QueryID string, dateFrom time.Time, dateTo time.Time, queryIndicator string, deviceTypeIndicator string) (SearchSingleHistoryResponse, error) {
-	data := make(map[string]interface{})
-	data["date_from"] = dateFrom.Format(YYYYMMDD)
-	data["date_to"] = dateTo.Format(YYYYMMDD)
-	data["query_indicator"] = queryIndicator
-	if deviceTypeIndicator != "" {
-		data["device_type_indicator"] = deviceTypeIndicator
-	} else {
-		data["device_type_indicator"] = "ALL"
-	}
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/search-queries/%s/history", s.client.userID, hostID, QueryID)
+// GetSingleSearchQueryHistory - get single search query history, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-search-queries-history.html
+func (s *SearchQueryService) GetSingleSearchQueryHistory(hostID HostID, queryID string, req QueryHistoryRequest) (SearchSingleHistoryResponse, error) {
 	var result SearchSingleHistoryResponse
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
+	v := &validator{}
+	v.required("QueryID", queryID)
+	if err := v.err(); err != nil {
+		return result, err
+	}
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "search-queries/"+url.PathEscape(queryID)+"/history")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.makeGETRequestWithParams(endpoint, req.params(), &result)
 	return result, err
 }
@@ -1,8 +1,8 @@
 package yandexwebmaster
 
 import (
-	"fmt"
 	"net/http"
+	"net/url"
 )
 
 // Sitemap service for sitemap management
@@ -15,76 +15,76 @@ func newSitemapService(cl *Client) *SitemapService {
 	return &SitemapService{client: cl}
 }
 
-type Sitemap struct {
-	SitemapID      string `json:"sitemap_id"`
-	SitemapURL     string `json:"sitemap_url"`
-	LastAccessDate string `json:"last_access_date"`
-}
-
-type Sitemaps struct {
-	Sitemaps []*Sitemap `json:"sitemaps"`
-}
-
-type AddedUserSitemap struct {
-	SitemapID  string `json:"sitemap_id"`
-	SitemapURL string `json:"sitemap_url"`
-	AddedDate  string `json:"added_date"`
-}
-
-type AddedSitemap struct {
-	SitemapID string `json:"sitemap_id"`
-}
-
 // get sitemaps, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-sitemaps-get.html
-func (s *SitemapService) GetSitemaps(hostID string, limit int, parentID string, fromSiteID string) (Sitemaps, error) {
-	data := make(map[string]interface{})
-	if limit != 0 {
-		data["limit"] = limit
-	} else {
-		data["limit"] = 10
-	}
-	if parentID != "" {
-		data["parent_id"] = parentID
+func (s *SitemapService) GetSitemaps(hostID HostID, req SitemapsRequest) (Sitemaps, error) {
+	var result Sitemaps
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
 	}
-	if fromSiteID != "" {
-		data["from_site_id"] = fromSiteID
+	endpoint, err := s.client.hostEndpoint(hostID, "sitemaps")
+	if err != nil {
+		return result, err
 	}
-	var result Sitemaps
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/sitemaps", s.client.userID, hostID)
-	_, err := s.client.makeGETRequestWithParams(endpoint, data, &result)
+	_, err = s.client.makeGETRequestWithParams(endpoint, req.params(), &result)
 	return result, err
 }
 
 // get site map, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-sitemaps-sitemap-id-get.html
-func (s *SitemapService) GetSitemap(hostID string, sitemapID string) (Sitemap, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/sitemaps/%s", s.client.userID, hostID, sitemapID)
+func (s *SitemapService) GetSitemap(hostID HostID, sitemapID string) (Sitemap, error) {
 	var result Sitemap
-	_, err := s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "sitemaps/"+url.PathEscape(sitemapID))
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	return result, err
+}
+
+// get sitemaps added by user, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-user-added-sitemaps-get.html
+func (s *SitemapService) GetUserAddedSitemaps(hostID HostID, req UserAddedSitemapsRequest) (AddedUserSitemaps, error) {
+	var result AddedUserSitemaps
+	if err := req.validate(s.client.location); err != nil {
+		return result, err
+	}
+	endpoint, err := s.client.hostEndpoint(hostID, "user-added-sitemaps")
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.makeGETRequestWithParams(endpoint, req.params(), &result)
 	return result, err
 }
 
 // get user added sitemaps, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-user-added-sitemaps-sitemap-id-get.html
-func (s *SitemapService) GetUserAddedSitemap(hostID string, sitemapID string) (AddedUserSitemap, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/user-added-sitemaps/%s", s.client.userID, hostID, sitemapID)
+func (s *SitemapService) GetUserAddedSitemap(hostID HostID, sitemapID string) (AddedUserSitemap, error) {
 	var result AddedUserSitemap
-	_, err := s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "user-added-sitemaps/"+url.PathEscape(sitemapID))
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodGet, endpoint, nil, &result)
 	return result, err
 }
 
 // add sitemap, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-user-added-sitemaps-post.html
-func (s *SitemapService) AddSitemap(hostID string, url string) (AddedSitemap, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/user-added-sitemaps", s.client.userID, hostID)
+func (s *SitemapService) AddSitemap(hostID HostID, url string) (AddedSitemap, error) {
 	var result AddedSitemap
+	endpoint, err := s.client.hostEndpoint(hostID, "user-added-sitemaps")
+	if err != nil {
+		return result, err
+	}
 	data := make(map[string]interface{})
 	data["url"] = url
-	_, err := s.client.sendAPIRequest(http.MethodPost, endpoint, data, &result)
+	_, err = s.client.sendAPIRequest(http.MethodPost, endpoint, data, &result)
 	return result, err
 }
 
 // Delete sitemap, doc: https://yandex.ru/dev/webmaster/doc/dg/reference/host-user-added-sitemaps-sitemap-id-delete.html
-func (s *SitemapService) DeleteSitemap(hostID string, sitemapID string) (interface{}, error) {
-	endpoint := fmt.Sprintf("user/%d/hosts/%s/user-added-sitemaps/%s", s.client.userID, hostID, sitemapID)
+func (s *SitemapService) DeleteSitemap(hostID HostID, sitemapID string) (interface{}, error) {
 	var result interface{}
-	_, err := s.client.sendAPIRequest(http.MethodDelete, endpoint, nil, &result)
+	endpoint, err := s.client.hostEndpoint(hostID, "user-added-sitemaps/"+url.PathEscape(sitemapID))
+	if err != nil {
+		return result, err
+	}
+	_, err = s.client.sendAPIRequest(http.MethodDelete, endpoint, nil, &result)
 	return result, err
 }
@@ -0,0 +1,331 @@
+package yandexwebmaster
+
+import (
+	"sort"
+)
+
+// Point - value of series on calendar date
+type Point struct {
+	Date  Date
+	Value float64
+}
+
+// TimeSeries - values ordered by date with at most one point per date
+type TimeSeries struct {
+	Name   string
+	Points []Point
+}
+
+// Aggregation - method of combining values of one resampling bucket
+type Aggregation string
+
+const (
+	AggregationSum  Aggregation = "SUM"
+	AggregationMean Aggregation = "MEAN"
+)
+
+// GapFill - method of filling dates missing in series
+type GapFill string
+
+const (
+	// GapFillZero - missing dates get zero value
+	GapFillZero GapFill = "ZERO"
+	// GapFillPrevious - missing dates repeat previous value, zero before the first point
+	GapFillPrevious GapFill = "PREVIOUS"
+	// GapFillLinear - missing dates are interpolated between neighbours, edges repeat the nearest point
+	GapFillLinear GapFill = "LINEAR"
+)
+
+// NewTimeSeries creates series from points in any order, the last of points with the same date wins
+func NewTimeSeries(name string, points []Point) TimeSeries {
+	byDate := make(map[Date]float64, len(points))
+	for _, p := range points {
+		byDate[DateOf(p.Date.Time)] = p.Value
+	}
+	ts := TimeSeries{Name: name, Points: make([]Point, 0, len(byDate))}
+	for date, value := range byDate {
+		ts.Points = append(ts.Points, Point{Date: date, Value: value})
+	}
+	sort.Slice(ts.Points, func(i, j int) bool {
+		return ts.Points[i].Date.Before(ts.Points[j].Date.Time)
+	})
+	return ts
+}
+
+// TimeSeriesFromIndicators creates series from indexing or search event history
+func TimeSeriesFromIndicators(name string, indicators []*Indicator) TimeSeries {
+	points := make([]Point, 0, len(indicators))
+	for _, ind := range indicators {
+		if ind != nil {
+			points = append(points, Point{Date: ind.Date.CalendarDate(), Value: float64(ind.Value)})
+		}
+	}
+	return NewTimeSeries(name, points)
+}
+
+// Series returns history of indicator
+func (h IndexingHistory) Series(indicator IndexingIndicator) TimeSeries {
+	return TimeSeriesFromIndicators(indicator.String(), h.Indicators[indicator])
+}
+
+// Series returns history of urls count in search
+func (h InseacrhURLHistory) Series() TimeSeries {
+	points := make([]Point, 0, len(h.History))
+	for _, item := range h.History {
+		if item != nil {
+			points = append(points, Point{Date: item.Date.CalendarDate(), Value: float64(item.Value)})
+		}
+	}
+	return NewTimeSeries("IN_SEARCH", points)
+}
+
+// Series returns history of event
+func (h SearchURLEventHistoryResponse) Series(event SearchEvent) TimeSeries {
+	switch event {
+	case SearchEventAppearedInSearch:
+		return TimeSeriesFromIndicators(event.String(), h.Indicators.AppeadINSearch)
+	case SearchEventRemovedFromSearch:
+		return TimeSeriesFromIndicators(event.String(), h.Indicators.RemovedFromSearch)
+	}
+	return TimeSeries{Name: event.String()}
+}
+
+// Series returns history of query indicator
+func (h SearchAllHistoryIndicator) Series(indicator QueryIndicator) TimeSeries {
+	var data []*SeachAllHistoryIndicatorData
+	switch indicator {
+	case QueryIndicatorTotalShows:
+		data = h.TotalShows
+	case QueryIndicatorTotalClicks:
+		data = h.TotalClicks
+	case QueryIndicatorAvgShowPosition:
+		data = h.AvgShowPosition
+	case QueryIndicatorAvgClickPosition:
+		data = h.AvgClickPosition
+	}
+	points := make([]Point, 0, len(data))
+	for _, item := range data {
+		if item != nil {
+			points = append(points, Point{Date: item.Date.CalendarDate(), Value: item.Value})
+		}
+	}
+	return NewTimeSeries(indicator.String(), points)
+}
+
+// Series returns history of query indicator for all queries
+func (r SearchAllHistoryResponse) Series(indicator QueryIndicator) TimeSeries {
+	return r.Indicators.Series(indicator)
+}
+
+// Series returns history of query indicator for single query
+func (r SearchSingleHistoryResponse) Series(indicator QueryIndicator) TimeSeries {
+	return r.Indicators.Series(indicator)
+}
+
+// Len returns number of points
+func (ts TimeSeries) Len() int {
+	return len(ts.Points)
+}
+
+// Dates returns dates of points
+func (ts TimeSeries) Dates() []Date {
+	dates := make([]Date, len(ts.Points))
+	for i, p := range ts.Points {
+		dates[i] = p.Date
+	}
+	return dates
+}
+
+// Values returns values of points
+func (ts TimeSeries) Values() []float64 {
+	values := make([]float64, len(ts.Points))
+	for i, p := range ts.Points {
+		values[i] = p.Value
+	}
+	return values
+}
+
+// Value returns value on date, false if series has no point on date
+func (ts TimeSeries) Value(date Date) (float64, bool) {
+	date = DateOf(date.Time)
+	i := sort.Search(len(ts.Points), func(i int) bool {
+		return !ts.Points[i].Date.Before(date.Time)
+	})
+	if i < len(ts.Points) && ts.Points[i].Date.Equal(date.Time) {
+		return ts.Points[i].Value, true
+	}
+	return 0, false
+}
+
+// Sum returns sum of values
+func (ts TimeSeries) Sum() float64 {
+	var sum float64
+	for _, p := range ts.Points {
+		sum += p.Value
+	}
+	return sum
+}
+
+// Mean returns mean of values, zero for empty series
+func (ts TimeSeries) Mean() float64 {
+	if len(ts.Points) == 0 {
+		return 0
+	}
+	return ts.Sum() / float64(len(ts.Points))
+}
+
+// weekStart returns monday of week containing date
+func weekStart(date Date) Date {
+	offset := (int(date.Weekday()) + 6) % 7
+	return DateOf(date.AddDate(0, 0, -offset))
+}
+
+// monthStart returns first day of month containing date
+func monthStart(date Date) Date {
+	return NewDate(date.Year(), date.Month(), 1)
+}
+
+// resample groups points into buckets starting at bucket(date)
+func (ts TimeSeries) resample(bucket func(Date) Date, agg Aggregation) TimeSeries {
+	res := TimeSeries{Name: ts.Name}
+	count := 0
+	for _, p := range ts.Points {
+		start := bucket(p.Date)
+		if n := len(res.Points); n == 0 || !res.Points[n-1].Date.Equal(start.Time) {
+			if agg == AggregationMean && count > 0 {
+				res.Points[n-1].Value /= float64(count)
+			}
+			res.Points = append(res.Points, Point{Date: start})
+			count = 0
+		}
+		res.Points[len(res.Points)-1].Value += p.Value
+		count++
+	}
+	if agg == AggregationMean && count > 0 {
+		res.Points[len(res.Points)-1].Value /= float64(count)
+	}
+	return res
+}
+
+// Weekly resamples series to ISO weeks, points are dated by monday
+func (ts TimeSeries) Weekly(agg Aggregation) TimeSeries {
+	return ts.resample(weekStart, agg)
+}
+
+// Monthly resamples series to months, points are dated by first day of month
+func (ts TimeSeries) Monthly(agg Aggregation) TimeSeries {
+	return ts.resample(monthStart, agg)
+}
+
+// FillGaps returns series with point for every day between the first and the last point
+func (ts TimeSeries) FillGaps(fill GapFill) TimeSeries {
+	if len(ts.Points) == 0 {
+		return TimeSeries{Name: ts.Name}
+	}
+	return ts.fillRange(ts.Points[0].Date, ts.Points[len(ts.Points)-1].Date, fill)
+}
+
+// fillRange returns series with point for every day in [from, to]
+func (ts TimeSeries) fillRange(from Date, to Date, fill GapFill) TimeSeries {
+	res := TimeSeries{Name: ts.Name}
+	i := 0
+	for date := from; !date.After(to.Time); date = DateOf(date.AddDate(0, 0, 1)) {
+		for i < len(ts.Points) && ts.Points[i].Date.Before(date.Time) {
+			i++
+		}
+		if i < len(ts.Points) && ts.Points[i].Date.Equal(date.Time) {
+			res.Points = append(res.Points, ts.Points[i])
+			continue
+		}
+		res.Points = append(res.Points, Point{Date: date, Value: ts.missingValue(i, date, fill)})
+	}
+	return res
+}
+
+// missingValue returns value for date missing in series, next is index of the first point after date
+func (ts TimeSeries) missingValue(next int, date Date, fill GapFill) float64 {
+	hasPrev := next > 0
+	hasNext := next < len(ts.Points)
+	switch fill {
+	case GapFillPrevious:
+		if hasPrev {
+			return ts.Points[next-1].Value
+		}
+	case GapFillLinear:
+		switch {
+		case hasPrev && hasNext:
+			prev, nxt := ts.Points[next-1], ts.Points[next]
+			span := nxt.Date.Sub(prev.Date.Time).Hours()
+			part := date.Sub(prev.Date.Time).Hours()
+			return prev.Value + (nxt.Value-prev.Value)*part/span
+		case hasPrev:
+			return ts.Points[next-1].Value
+		case hasNext:
+			return ts.Points[next].Value
+		}
+	}
+	return 0
+}
+
+// MovingAverage returns trailing mean over window points, points before the window is full are dropped
+func (ts TimeSeries) MovingAverage(window int) TimeSeries {
+	res := TimeSeries{Name: ts.Name}
+	if window <= 0 {
+		return res
+	}
+	var sum float64
+	for i, p := range ts.Points {
+		sum += p.Value
+		if i >= window {
+			sum -= ts.Points[i-window].Value
+		}
+		if i >= window-1 {
+			res.Points = append(res.Points, Point{Date: p.Date, Value: sum / float64(window)})
+		}
+	}
+	return res
+}
+
+// PercentChange returns change of every point against the previous one in percent,
+// points following zero value are dropped
+func (ts TimeSeries) PercentChange() TimeSeries {
+	res := TimeSeries{Name: ts.Name}
+	for i := 1; i < len(ts.Points); i++ {
+		prev := ts.Points[i-1].Value
+		if prev == 0 {
+			continue
+		}
+		res.Points = append(res.Points, Point{
+			Date:  ts.Points[i].Date,
+			Value: (ts.Points[i].Value - prev) / prev * 100,
+		})
+	}
+	return res
+}
+
+// AlignSeries puts series onto shared daily axis from the earliest to the latest point of all series,
+// missing dates are filled by fill
+func AlignSeries(fill GapFill, series ...TimeSeries) []TimeSeries {
+	var from, to Date
+	for _, ts := range series {
+		if len(ts.Points) == 0 {
+			continue
+		}
+		first, last := ts.Points[0].Date, ts.Points[len(ts.Points)-1].Date
+		if from.IsZero() || first.Before(from.Time) {
+			from = first
+		}
+		if to.IsZero() || last.After(to.Time) {
+			to = last
+		}
+	}
+	aligned := make([]TimeSeries, len(series))
+	for i, ts := range series {
+		if from.IsZero() {
+			aligned[i] = TimeSeries{Name: ts.Name}
+			continue
+		}
+		aligned[i] = ts.fillRange(from, to, fill)
+	}
+	return aligned
+}
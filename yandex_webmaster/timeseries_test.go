@@ -0,0 +1,151 @@
+package yandexwebmaster
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// series creates series of september 2026 from day to value pairs
+func series(values map[int]float64) TimeSeries {
+	points := make([]Point, 0, len(values))
+	for day, value := range values {
+		points = append(points, Point{Date: NewDate(2026, 9, day), Value: value})
+	}
+	return NewTimeSeries("test", points)
+}
+
+// pointsOf returns day to value pairs of september points in order
+func pointsOf(ts TimeSeries) [][2]float64 {
+	res := [][2]float64{}
+	for _, p := range ts.Points {
+		res = append(res, [2]float64{float64(p.Date.Day()), p.Value})
+	}
+	return res
+}
+
+func TestNewTimeSeries(t *testing.T) {
+	ts := NewTimeSeries("x", []Point{
+		{Date: NewDate(2026, 9, 3), Value: 3},
+		{Date: DateOf(time.Date(2026, 9, 1, 18, 0, 0, 0, time.UTC)), Value: 1},
+		{Date: NewDate(2026, 9, 3), Value: 30},
+	})
+	want := [][2]float64{{1, 1}, {3, 30}}
+	if got := pointsOf(ts); !reflect.DeepEqual(got, want) {
+		t.Errorf("points = %v, want %v", got, want)
+	}
+	if v, ok := ts.Value(NewDate(2026, 9, 3)); !ok || v != 30 {
+		t.Errorf("Value(3) = %v, %v", v, ok)
+	}
+	if _, ok := ts.Value(NewDate(2026, 9, 2)); ok {
+		t.Error("Value(2) found")
+	}
+	if ts.Sum() != 31 || ts.Mean() != 15.5 || ts.Len() != 2 {
+		t.Errorf("Sum = %v, Mean = %v, Len = %v", ts.Sum(), ts.Mean(), ts.Len())
+	}
+}
+
+func TestTimeSeriesFillGaps(t *testing.T) {
+	ts := series(map[int]float64{2: 10, 5: 40, 6: 0})
+	tests := []struct {
+		fill GapFill
+		want [][2]float64
+	}{
+		{fill: GapFillZero, want: [][2]float64{{2, 10}, {3, 0}, {4, 0}, {5, 40}, {6, 0}}},
+		{fill: GapFillPrevious, want: [][2]float64{{2, 10}, {3, 10}, {4, 10}, {5, 40}, {6, 0}}},
+		{fill: GapFillLinear, want: [][2]float64{{2, 10}, {3, 20}, {4, 30}, {5, 40}, {6, 0}}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.fill), func(t *testing.T) {
+			if got := pointsOf(ts.FillGaps(tt.fill)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FillGaps() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := pointsOf(TimeSeries{}.FillGaps(GapFillZero)); len(got) != 0 {
+		t.Errorf("FillGaps() of empty series = %v", got)
+	}
+}
+
+func TestTimeSeriesResample(t *testing.T) {
+	// 2026-09-06 is sunday, 2026-09-07 is monday
+	ts := series(map[int]float64{1: 1, 6: 5, 7: 10, 8: 20, 30: 4})
+	tests := []struct {
+		name string
+		got  TimeSeries
+		want [][2]float64
+	}{
+		{name: "weekly sum", got: ts.Weekly(AggregationSum), want: [][2]float64{{31, 6}, {7, 30}, {28, 4}}},
+		{name: "weekly mean", got: ts.Weekly(AggregationMean), want: [][2]float64{{31, 3}, {7, 15}, {28, 4}}},
+		{name: "monthly sum", got: ts.Monthly(AggregationSum), want: [][2]float64{{1, 40}}},
+		{name: "monthly mean", got: ts.Monthly(AggregationMean), want: [][2]float64{{1, 8}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pointsOf(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("points = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if first := ts.Weekly(AggregationSum).Points[0].Date; first.String() != "2026-08-31" {
+		t.Errorf("first week starts %s", first)
+	}
+}
+
+func TestTimeSeriesTransforms(t *testing.T) {
+	ts := series(map[int]float64{1: 10, 2: 20, 3: 0, 4: 30, 5: 15})
+	tests := []struct {
+		name string
+		got  TimeSeries
+		want [][2]float64
+	}{
+		{name: "moving average", got: ts.MovingAverage(2), want: [][2]float64{{2, 15}, {3, 10}, {4, 15}, {5, 22.5}}},
+		{name: "moving average larger than series", got: ts.MovingAverage(10), want: [][2]float64{}},
+		{name: "moving average zero window", got: ts.MovingAverage(0), want: [][2]float64{}},
+		{name: "percent change skips zero base", got: ts.PercentChange(), want: [][2]float64{{2, 100}, {3, -100}, {5, -50}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pointsOf(tt.got); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("points = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAlignSeries(t *testing.T) {
+	a := series(map[int]float64{2: 1, 3: 2})
+	b := series(map[int]float64{1: 5, 4: 8})
+	aligned := AlignSeries(GapFillPrevious, a, b, TimeSeries{Name: "empty"})
+	want := [][][2]float64{
+		{{1, 0}, {2, 1}, {3, 2}, {4, 2}},
+		{{1, 5}, {2, 5}, {3, 5}, {4, 8}},
+		{{1, 0}, {2, 0}, {3, 0}, {4, 0}},
+	}
+	for i := range want {
+		if got := pointsOf(aligned[i]); !reflect.DeepEqual(got, want[i]) {
+			t.Errorf("series %d = %v, want %v", i, got, want[i])
+		}
+	}
+	if aligned[2].Name != "empty" {
+		t.Errorf("name = %q", aligned[2].Name)
+	}
+}
+
+func TestSearchHistorySeries(t *testing.T) {
+	msk := time.FixedZone("MSK", 3*60*60)
+	h := SearchAllHistoryIndicator{
+		TotalShows: []*SeachAllHistoryIndicatorData{
+			{Date: NewTimestamp(time.Date(2026, 9, 2, 0, 0, 0, 0, msk)), Value: 20},
+			nil,
+			{Date: NewTimestamp(time.Date(2026, 9, 1, 0, 0, 0, 0, msk)), Value: 10},
+		},
+	}
+	shows := h.Series(QueryIndicatorTotalShows)
+	if shows.Name != "TOTAL_SHOWS" || !reflect.DeepEqual(pointsOf(shows), [][2]float64{{1, 10}, {2, 20}}) {
+		t.Errorf("Series() = %+v", shows)
+	}
+	if clicks := h.Series(QueryIndicatorTotalClicks); clicks.Len() != 0 {
+		t.Errorf("Series(clicks) = %+v", clicks)
+	}
+}
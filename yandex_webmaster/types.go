@@ -1 +1,161 @@
 package yandexwebmaster
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// layouts of dates returned by api
+var apiTimeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05,000-0700",
+	"2006-01-02T15:04:05.000-0700",
+	"2006-01-02T15:04:05-0700",
+	YYYYMMDD,
+}
+
+// parseAPITime parses date or datetime string returned by api
+func parseAPITime(value string) (time.Time, error) {
+	for _, layout := range apiTimeLayouts {
+		t, err := time.Parse(layout, value)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unsupported date format: %q", value)
+}
+
+// sameInstant reports whether a and b are the same instant in the same offset
+func sameInstant(a time.Time, b time.Time) bool {
+	_, aOffset := a.Zone()
+	_, bOffset := b.Zone()
+	return a.Equal(b) && aOffset == bOffset
+}
+
+// unmarshalAPITime decodes json string or null with api date
+func unmarshalAPITime(bytes []byte) (time.Time, string, error) {
+	if string(bytes) == "null" {
+		return time.Time{}, "", nil
+	}
+	var raw string
+	if err := json.Unmarshal(bytes, &raw); err != nil {
+		return time.Time{}, "", err
+	}
+	if raw == "" {
+		return time.Time{}, "", nil
+	}
+	t, err := parseAPITime(raw)
+	if err != nil {
+		return time.Time{}, "", err
+	}
+	return t, raw, nil
+}
+
+// Date - calendar date returned by api as "2006-01-02"
+type Date struct {
+	time.Time
+	raw string
+}
+
+// NewDate creates Date for given day
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Time: time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// DateOf returns calendar date of t in its location
+func DateOf(t time.Time) Date {
+	return NewDate(t.Date())
+}
+
+// String returns date in YYYYMMDD layout
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(YYYYMMDD)
+}
+
+func (d Date) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	if d.raw != "" {
+		if t, err := parseAPITime(d.raw); err == nil && sameInstant(t, d.Time) {
+			return json.Marshal(d.raw)
+		}
+	}
+	return json.Marshal(d.String())
+}
+
+func (d *Date) UnmarshalJSON(bytes []byte) error {
+	t, raw, err := unmarshalAPITime(bytes)
+	if err != nil {
+		return err
+	}
+	*d = Date{Time: t, raw: raw}
+	return nil
+}
+
+// Timestamp - date and time with offset returned by api
+type Timestamp struct {
+	time.Time
+	raw string
+}
+
+// NewTimestamp creates Timestamp from t
+func NewTimestamp(t time.Time) Timestamp {
+	return Timestamp{Time: t}
+}
+
+// String returns timestamp in RFC3339 layout
+func (t Timestamp) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.RFC3339Nano)
+}
+
+// CalendarDate returns calendar date of timestamp in its offset
+func (t Timestamp) CalendarDate() Date {
+	return DateOf(t.Time)
+}
+
+func (t Timestamp) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t Timestamp) MarshalJSON() ([]byte, error) {
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	if t.raw != "" {
+		if parsed, err := parseAPITime(t.raw); err == nil && sameInstant(parsed, t.Time) {
+			return json.Marshal(t.raw)
+		}
+	}
+	return json.Marshal(t.String())
+}
+
+func (t *Timestamp) UnmarshalJSON(bytes []byte) error {
+	parsed, raw, err := unmarshalAPITime(bytes)
+	if err != nil {
+		return err
+	}
+	*t = Timestamp{Time: parsed, raw: raw}
+	return nil
+}
+
+// unmarshalEnum decodes json string or null with enum value, unknown values are kept as is
+func unmarshalEnum(bytes []byte) (string, error) {
+	if string(bytes) == "null" {
+		return "", nil
+	}
+	var value string
+	err := json.Unmarshal(bytes, &value)
+	return value, err
+}
@@ -0,0 +1,124 @@
+package yandexwebmaster
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestDateJSON(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		wantDate Date
+		want     string
+	}{
+		{name: "date", input: `"2026-09-01"`, wantDate: NewDate(2026, 9, 1), want: `"2026-09-01"`},
+		{name: "null", input: `null`, want: `null`},
+		{name: "empty string", input: `""`, want: `null`},
+		{name: "datetime keeps raw", input: `"2026-09-01T00:00:00,000+0000"`, wantDate: NewDate(2026, 9, 1), want: `"2026-09-01T00:00:00,000+0000"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var d Date
+			if err := json.Unmarshal([]byte(tt.input), &d); err != nil {
+				t.Fatal(err)
+			}
+			if !d.Time.Equal(tt.wantDate.Time) {
+				t.Errorf("Date = %v, want %v", d, tt.wantDate)
+			}
+			got, err := json.Marshal(d)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTimestampJSON(t *testing.T) {
+	msk := time.FixedZone("", 3*60*60)
+	tests := []struct {
+		name    string
+		input   string
+		wantUTC time.Time
+		want    string
+	}{
+		{
+			name:    "comma millis",
+			input:   `"2026-09-01T10:00:00,123+0300"`,
+			wantUTC: time.Date(2026, 9, 1, 7, 0, 0, 123000000, time.UTC),
+			want:    `"2026-09-01T10:00:00,123+0300"`,
+		},
+		{
+			name:    "dot millis",
+			input:   `"2026-09-01T10:00:00.000+0300"`,
+			wantUTC: time.Date(2026, 9, 1, 7, 0, 0, 0, time.UTC),
+			want:    `"2026-09-01T10:00:00.000+0300"`,
+		},
+		{
+			name:    "rfc3339",
+			input:   `"2026-09-01T10:00:00Z"`,
+			wantUTC: time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC),
+			want:    `"2026-09-01T10:00:00Z"`,
+		},
+		{
+			name:    "without seconds fraction",
+			input:   `"2026-09-01T23:30:00-0100"`,
+			wantUTC: time.Date(2026, 9, 2, 0, 30, 0, 0, time.UTC),
+			want:    `"2026-09-01T23:30:00-0100"`,
+		},
+		{name: "null", input: `null`, want: `null`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var ts Timestamp
+			if err := json.Unmarshal([]byte(tt.input), &ts); err != nil {
+				t.Fatal(err)
+			}
+			if !ts.Time.Equal(tt.wantUTC) {
+				t.Errorf("Timestamp = %v, want %v", ts, tt.wantUTC)
+			}
+			got, err := json.Marshal(ts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("changed value drops raw", func(t *testing.T) {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(`"2026-09-01T10:00:00,000+0300"`), &ts); err != nil {
+			t.Fatal(err)
+		}
+		ts.Time = ts.Time.In(time.UTC)
+		got, _ := json.Marshal(ts)
+		if string(got) != `"2026-09-01T07:00:00Z"` {
+			t.Errorf("Marshal() = %s", got)
+		}
+		if date := ts.CalendarDate(); date.String() != "2026-09-01" {
+			t.Errorf("CalendarDate() = %s", date)
+		}
+	})
+
+	t.Run("calendar date in offset", func(t *testing.T) {
+		ts := NewTimestamp(time.Date(2026, 9, 1, 1, 0, 0, 0, msk))
+		if date := ts.CalendarDate(); date.String() != "2026-09-01" {
+			t.Errorf("CalendarDate() = %s", date)
+		}
+	})
+}
+
+func TestUnmarshalAPITimeError(t *testing.T) {
+	for _, input := range []string{`"01.09.2026"`, `42`, `"2026-13-01"`} {
+		var ts Timestamp
+		if err := json.Unmarshal([]byte(input), &ts); err == nil {
+			t.Errorf("Unmarshal(%s) = %v, want error", input, ts)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package yandexwebmaster
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ValidationError - request field violates api constraints, returned before any request
+type ValidationError struct {
+	Field  string
+	Value  interface{}
+	Reason string
+}
+
+// Error returns string representation of the ValidationError
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("invalid %s (%v): %s", e.Field, e.Value, e.Reason)
+}
+
+// ValidationErrors - all violations found in request
+type ValidationErrors []*ValidationError
+
+// Error returns string representation of the ValidationErrors
+func (e ValidationErrors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Field returns violation of given field or nil
+func (e ValidationErrors) Field(field string) *ValidationError {
+	for _, err := range e {
+		if err.Field == field {
+			return err
+		}
+	}
+	return nil
+}
+
+// validator collects request violations
+type validator struct {
+	errs ValidationErrors
+	// loc - location of calendar dates, DefaultLocation if nil
+	loc *time.Location
+}
+
+func (v *validator) add(field string, value interface{}, reason string) {
+	v.errs = append(v.errs, &ValidationError{Field: field, Value: value, Reason: reason})
+}
+
+// limit checks that limit is zero (api default) or in [1, max]
+func (v *validator) limit(field string, value int, max int) {
+	if value < 0 || value > max {
+		v.add(field, value, fmt.Sprintf("must be 0 or 1..%d", max))
+	}
+}
+
+// offset checks that offset is not negative
+func (v *validator) offset(field string, value int) {
+	if value < 0 {
+		v.add(field, value, "must not be negative")
+	}
+}
+
+// dateRange checks that dates are not in the future and from is not after to, zero dates are skipped
+func (v *validator) dateRange(fromField string, from time.Time, toField string, to time.Time) {
+	loc := v.loc
+	if loc == nil {
+		loc = DefaultLocation
+	}
+	today := DateIn(time.Now(), loc)
+	fromDate, toDate := DateIn(from, loc), DateIn(to, loc)
+	if !from.IsZero() && fromDate.After(today.Time) {
+		v.add(fromField, fromDate.String(), "must not be in the future")
+	}
+	if !to.IsZero() && toDate.After(today.Time) {
+		v.add(toField, toDate.String(), "must not be in the future")
+	}
+	if !from.IsZero() && !to.IsZero() && fromDate.After(toDate.Time) {
+		v.add(fromField, fromDate.String(), fmt.Sprintf("must not be after %s", toField))
+	}
+}
+
+// required checks that string value is not empty
+func (v *validator) required(field string, value string) {
+	if value == "" {
+		v.add(field, value, "is required")
+	}
+}
+
+// enum checks that value is known
+func (v *validator) enum(field string, value fmt.Stringer, known bool) {
+	if !known {
+		v.add(field, value.String(), "unknown value")
+	}
+}
+
+// err returns collected violations or nil
+func (v *validator) err() error {
+	if len(v.errs) == 0 {
+		return nil
+	}
+	return v.errs
+}
+
+// setDateParam adds non zero date to GET params, date is formatted in client location on encoding
+func setDateParam(params map[string]interface{}, key string, value time.Time) {
+	if !value.IsZero() {
+		params[key] = value
+	}
+}
+
+// setIntParam adds non zero int to GET params
+func setIntParam(params map[string]interface{}, key string, value int) {
+	if value != 0 {
+		params[key] = value
+	}
+}
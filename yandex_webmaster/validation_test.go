@@ -0,0 +1,88 @@
+package yandexwebmaster
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRequestValidationMessages(t *testing.T) {
+	now := time.Now()
+	tomorrow := now.AddDate(0, 0, 2)
+	tests := []struct {
+		name string
+		req  interface{ Validate() error }
+		want string
+	}{
+		{
+			name: "valid",
+			req:  PopularQueriesRequest{DateFrom: now.AddDate(0, 0, -7), DateTo: now, Limit: 500},
+		},
+		{
+			name: "zero limit is api default",
+			req:  PopularQueriesRequest{Limit: 0},
+		},
+		{
+			name: "limit over max",
+			req:  PopularQueriesRequest{Limit: 501},
+			want: "invalid Limit (501): must be 0 or 1..500",
+		},
+		{
+			name: "negative limit and offset",
+			req:  PopularQueriesRequest{Limit: -1, Offset: -5},
+			want: "invalid Limit (-1): must be 0 or 1..500; invalid Offset (-5): must not be negative",
+		},
+		{
+			name: "order by outside of param enum",
+			req:  PopularQueriesRequest{OrderBy: QueryIndicatorAvgShowPosition},
+			want: "invalid OrderBy (AVG_SHOW_POSITION): must be one of TOTAL_SHOWS, TOTAL_CLICKS",
+		},
+		{
+			name: "unknown enum values",
+			req:  PopularQueriesRequest{QueryIndicators: []QueryIndicator{"CTR"}, DeviceType: "TV"},
+			want: "invalid QueryIndicators (CTR): unknown value; invalid DeviceType (TV): unknown value",
+		},
+		{
+			name: "future date",
+			req:  IndexingHistoryRequest{DateTo: tomorrow},
+			want: "invalid DateTo (" + DateIn(tomorrow, DefaultLocation).String() + "): must not be in the future",
+		},
+		{
+			name: "reversed range",
+			req:  IndexingHistoryRequest{DateFrom: time.Date(2026, 9, 10, 12, 0, 0, 0, DefaultLocation), DateTo: time.Date(2026, 9, 1, 12, 0, 0, 0, DefaultLocation)},
+			want: "invalid DateFrom (2026-09-10): must not be after DateTo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.req.Validate()
+			if tt.want == "" {
+				if err != nil {
+					t.Fatalf("Validate() = %v", err)
+				}
+				return
+			}
+			var errs ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("Validate() = %v, want ValidationErrors", err)
+			}
+			if err.Error() != tt.want {
+				t.Errorf("Validate() = %q, want %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestValidationErrorsField(t *testing.T) {
+	err := PopularQueriesRequest{Limit: 1000, Offset: -1}.Validate()
+	var errs ValidationErrors
+	if !errors.As(err, &errs) {
+		t.Fatalf("Validate() = %v", err)
+	}
+	if got := errs.Field("Offset"); got == nil || got.Value != -1 {
+		t.Errorf("Field(Offset) = %+v", got)
+	}
+	if got := errs.Field("DateFrom"); got != nil {
+		t.Errorf("Field(DateFrom) = %+v, want nil", got)
+	}
+}
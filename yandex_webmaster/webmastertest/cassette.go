@@ -0,0 +1,317 @@
+package webmastertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Mode - mode of cassette Recorder
+type Mode string
+
+const (
+	// ModeRecord - send every request to api and store responses, cassette is overwritten on Save
+	ModeRecord Mode = "RECORD"
+	// ModeReplay - serve requests from cassette, unmatched requests are recorded unless recorder is strict
+	ModeReplay Mode = "REPLAY"
+)
+
+const (
+	// RedactedUserID - user id stored in cassettes instead of real one
+	RedactedUserID = 1
+	redactedToken  = "REDACTED"
+)
+
+var userPathRe = regexp.MustCompile(`(^|/)user/\d+(/|$)`)
+
+// Interaction - recorded request and response
+type Interaction struct {
+	// Key - method, endpoint and canonical query of request
+	Key         string          `json:"key"`
+	RequestBody json.RawMessage `json:"request_body,omitempty"`
+	Status      int             `json:"status"`
+	Header      http.Header     `json:"header,omitempty"`
+	// Body - json response body
+	Body json.RawMessage `json:"body,omitempty"`
+	// BodyText - response body which is not json, e.g. html error page
+	BodyText string `json:"body_text,omitempty"`
+}
+
+// Cassette - recorded interactions, stored as indented json
+type Cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// UnmatchedRequestError - strict recorder got request missing in cassette
+type UnmatchedRequestError struct {
+	Key string
+}
+
+// Error returns string representation of the UnmatchedRequestError
+func (e *UnmatchedRequestError) Error() string {
+	return fmt.Sprintf("no recorded interaction for %s", e.Key)
+}
+
+// Recorder - http.RoundTripper which records api responses to cassette file and replays them
+type Recorder struct {
+	// Path - cassette file
+	Path string
+	Mode Mode
+	// Strict - fail requests missing in cassette instead of recording them in ModeReplay
+	Strict bool
+	// Transport - transport to real api, http.DefaultTransport if nil
+	Transport http.RoundTripper
+
+	mu       sync.Mutex
+	cassette Cassette
+	// played - number of replayed interactions by key
+	played map[string]int
+	// recorded - cassette has new interactions
+	recorded bool
+}
+
+// NewRecorder creates recorder of cassette at path, cassette is loaded in ModeReplay
+func NewRecorder(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Path: path, Mode: mode, played: make(map[string]int)}
+	if mode != ModeReplay {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &r.cassette); err != nil {
+		return nil, fmt.Errorf("cassette %s: %w", path, err)
+	}
+	return r, nil
+}
+
+// HTTPClient returns client using recorder as transport, pass it to yandexwebmaster.WithHTTPClient
+func (r *Recorder) HTTPClient() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+// RequestKey returns cassette key of request: method, path with redacted user id and sorted query
+func RequestKey(req *http.Request) string {
+	endpoint := userPathRe.ReplaceAllString(req.URL.Path, "${1}user/{user-id}${2}")
+	query := req.URL.Query()
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			parts = append(parts, url.QueryEscape(key)+"="+url.QueryEscape(value))
+		}
+	}
+	if len(parts) == 0 {
+		return req.Method + " " + endpoint
+	}
+	return req.Method + " " + endpoint + "?" + strings.Join(parts, "&")
+}
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	key := RequestKey(req)
+	if r.Mode == ModeReplay {
+		r.mu.Lock()
+		interaction := r.next(key)
+		r.mu.Unlock()
+		if interaction != nil {
+			return interaction.response(req), nil
+		}
+		if r.Strict {
+			return nil, &UnmatchedRequestError{Key: key}
+		}
+	}
+	return r.record(key, req)
+}
+
+// next returns interaction to replay for key, repeated requests get recorded responses in order, the last is repeated
+func (r *Recorder) next(key string) *Interaction {
+	var matched []*Interaction
+	for _, interaction := range r.cassette.Interactions {
+		if interaction.Key == key {
+			matched = append(matched, interaction)
+		}
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+	i := r.played[key]
+	r.played[key]++
+	if i >= len(matched) {
+		i = len(matched) - 1
+	}
+	return matched[i]
+}
+
+// record sends request to api and stores redacted interaction
+func (r *Recorder) record(key string, req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	out := req
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		reqBody = data
+		out = req.Clone(req.Context())
+		out.Body = io.NopCloser(bytes.NewReader(data))
+	}
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	token := strings.TrimSpace(strings.TrimPrefix(req.Header.Get("Authorization"), "OAuth"))
+	interaction := &Interaction{Key: key, Status: resp.StatusCode}
+	if ct := resp.Header.Get("Content-Type"); ct != "" {
+		interaction.Header = http.Header{"Content-Type": {ct}}
+	}
+	if ra := resp.Header.Get("Retry-After"); ra != "" {
+		if interaction.Header == nil {
+			interaction.Header = http.Header{}
+		}
+		interaction.Header.Set("Retry-After", ra)
+	}
+	if len(reqBody) != 0 {
+		interaction.RequestBody = redactJSON(reqBody, token)
+	}
+	stored := redact(body, token, req.URL.Path)
+	if len(bytes.TrimSpace(stored)) != 0 {
+		if json.Valid(stored) {
+			interaction.Body = stored
+		} else {
+			interaction.BodyText = string(stored)
+		}
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, interaction)
+	r.recorded = true
+	r.mu.Unlock()
+	return resp, nil
+}
+
+// redact removes token and user id from response body
+func redact(body []byte, token string, path string) []byte {
+	if token != "" {
+		body = bytes.ReplaceAll(body, []byte(token), []byte(redactedToken))
+	}
+	body = userPathRe.ReplaceAll(body, []byte("${1}user/"+strconv.Itoa(RedactedUserID)+"${2}"))
+	if strings.HasSuffix(path, "/user") && json.Valid(body) {
+		var user map[string]json.RawMessage
+		if json.Unmarshal(body, &user) == nil {
+			if _, ok := user["user_id"]; ok {
+				user["user_id"] = json.RawMessage(strconv.Itoa(RedactedUserID))
+				if data, err := json.Marshal(user); err == nil {
+					body = data
+				}
+			}
+		}
+	}
+	return body
+}
+
+// redactJSON removes token from request body, body is kept as json string if it is not json
+func redactJSON(body []byte, token string) json.RawMessage {
+	if token != "" {
+		body = bytes.ReplaceAll(body, []byte(token), []byte(redactedToken))
+	}
+	body = bytes.TrimSpace(body)
+	if json.Valid(body) {
+		return body
+	}
+	data, _ := json.Marshal(string(body))
+	return data
+}
+
+// response builds http response of interaction
+func (i *Interaction) response(req *http.Request) *http.Response {
+	body := []byte(i.BodyText)
+	if len(i.Body) != 0 {
+		body = i.Body
+	}
+	header := http.Header{}
+	for key, values := range i.Header {
+		header[key] = append([]string(nil), values...)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", i.Status, http.StatusText(i.Status)),
+		StatusCode:    i.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// Unused returns keys of recorded interactions which were not replayed
+func (r *Recorder) Unused() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := make(map[string]int)
+	var keys []string
+	for _, interaction := range r.cassette.Interactions {
+		counts[interaction.Key]++
+		if counts[interaction.Key] > r.played[interaction.Key] {
+			keys = append(keys, interaction.Key)
+		}
+	}
+	return keys
+}
+
+// Save writes cassette file if new interactions were recorded
+func (r *Recorder) Save() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.recorded {
+		return nil
+	}
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r.cassette); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(r.Path, buf.Bytes(), 0o644); err != nil {
+		return err
+	}
+	r.recorded = false
+	return nil
+}
@@ -0,0 +1,174 @@
+package webmastertest
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func TestRequestKey(t *testing.T) {
+	tests := []struct {
+		method string
+		url    string
+		want   string
+	}{
+		{method: http.MethodGet, url: "https://api.webmaster.yandex.net/v4/user", want: "GET /v4/user"},
+		{method: http.MethodGet, url: "https://api.webmaster.yandex.net/v4/user/12345/hosts", want: "GET /v4/user/{user-id}/hosts"},
+		{method: http.MethodDelete, url: "https://api.webmaster.yandex.net/v4/user/12345", want: "DELETE /v4/user/{user-id}"},
+		{method: http.MethodGet, url: "https://api.webmaster.yandex.net/v4/users/12345", want: "GET /v4/users/12345"},
+		{
+			method: http.MethodGet,
+			url:    "https://api.webmaster.yandex.net/v4/user/1/hosts/h/indexing/history?indexing_indicator=SEARCHABLE&date_from=2026-09-01&indexing_indicator=DOWNLOADED",
+			want:   "GET /v4/user/{user-id}/hosts/h/indexing/history?date_from=2026-09-01&indexing_indicator=DOWNLOADED&indexing_indicator=SEARCHABLE",
+		},
+		{method: http.MethodGet, url: "https://api.webmaster.yandex.net/v4/user/1/hosts?q=a+b%26c", want: "GET /v4/user/{user-id}/hosts?q=a+b%26c"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, tt.url, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := RequestKey(req); got != tt.want {
+				t.Errorf("RequestKey() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		path string
+		want string
+	}{
+		{name: "token", body: `{"message":"bad token secret"}`, path: "/v4/user/5/hosts", want: `{"message":"bad token REDACTED"}`},
+		{name: "user path", body: `{"url":"/v4/user/12345/hosts/h"}`, path: "/v4/user/5/hosts", want: `{"url":"/v4/user/1/hosts/h"}`},
+		{name: "user id", body: `{"user_id":12345,"login":"x"}`, path: "/v4/user", want: `{"login":"x","user_id":1}`},
+		{name: "user id outside user endpoint", body: `{"user_id":12345}`, path: "/v4/user/5/hosts", want: `{"user_id":12345}`},
+		{name: "not json", body: `<html>secret</html>`, path: "/v4/user", want: `<html>REDACTED</html>`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redact([]byte(tt.body), "secret", tt.path)); got != tt.want {
+				t.Errorf("redact() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedactJSON(t *testing.T) {
+	tests := []struct {
+		body string
+		want string
+	}{
+		{body: "{\"token\":\"secret\"}\n", want: `{"token":"REDACTED"}`},
+		{body: "url=secret", want: `"url=REDACTED"`},
+	}
+	for _, tt := range tests {
+		if got := string(redactJSON([]byte(tt.body), "secret")); got != tt.want {
+			t.Errorf("redactJSON(%q) = %s, want %s", tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestRecorderRecordAndReplay(t *testing.T) {
+	s := NewServer(WithToken("secret"), WithUserID(12345))
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	path := filepath.Join(t.TempDir(), "cassette.json")
+
+	rec, err := NewRecorder(path, ModeRecord)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := ywm.NewClient("secret", ywm.WithBaseURL(s.URL), ywm.WithHTTPClient(rec.HTTPClient()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Hosts.GetHost(hostID); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Recrawl.RecrawlURL(hostID, "https://other.com/"); err == nil {
+		t.Fatal("RecrawlURL() of foreign url succeeded")
+	}
+	if err := rec.Save(); err != nil {
+		t.Fatal(err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{"secret", "12345"} {
+		if strings.Contains(string(data), secret) {
+			t.Errorf("cassette contains %q:\n%s", secret, data)
+		}
+	}
+
+	s.Close()
+	replay, err := NewRecorder(path, ModeReplay)
+	if err != nil {
+		t.Fatal(err)
+	}
+	replay.Strict = true
+	client, err = ywm.NewClient("other-token", ywm.WithBaseURL(s.URL), ywm.WithHTTPClient(replay.HTTPClient()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unused := replay.Unused(); len(unused) != 2 {
+		t.Errorf("Unused() = %v", unused)
+	}
+	host, err := client.Hosts.GetHost(hostID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if host.HostID != hostID {
+		t.Errorf("HostID = %q", host.HostID)
+	}
+	var apiErr *ywm.YandexWebmasterError
+	if _, err := client.Recrawl.RecrawlURL(hostID, "https://other.com/"); !errors.As(err, &apiErr) || apiErr.HTTPCode != http.StatusBadRequest {
+		t.Errorf("replayed RecrawlURL() error = %v", err)
+	}
+	if unused := replay.Unused(); len(unused) != 0 {
+		t.Errorf("Unused() = %v", unused)
+	}
+	// client reports transport errors as text
+	if _, err := client.Sitemaps.GetSitemaps(hostID, ywm.SitemapsRequest{}); err == nil || !strings.Contains(err.Error(), "no recorded interaction") {
+		t.Errorf("unrecorded request error = %v", err)
+	}
+}
+
+func TestRecorderReplayOrder(t *testing.T) {
+	key := "GET /v4/user/{user-id}/hosts"
+	rec := &Recorder{Mode: ModeReplay, Strict: true, played: make(map[string]int)}
+	rec.cassette.Interactions = []*Interaction{
+		{Key: key, Status: http.StatusServiceUnavailable, BodyText: "busy"},
+		{Key: "GET /v4/user", Status: http.StatusOK, Body: []byte(`{"user_id":1}`)},
+		{Key: key, Status: http.StatusOK, Body: []byte(`{"hosts":[]}`)},
+	}
+	req, _ := http.NewRequest(http.MethodGet, "https://api.webmaster.yandex.net/v4/user/1/hosts", nil)
+	for _, want := range []int{http.StatusServiceUnavailable, http.StatusOK, http.StatusOK} {
+		resp, err := rec.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != want {
+			t.Errorf("status = %d, want %d", resp.StatusCode, want)
+		}
+	}
+	other, _ := http.NewRequest(http.MethodGet, "https://api.webmaster.yandex.net/v4/user/1/hosts/h", nil)
+	var unmatched *UnmatchedRequestError
+	if _, err := rec.RoundTrip(other); !errors.As(err, &unmatched) || unmatched.Key != "GET /v4/user/{user-id}/hosts/h" {
+		t.Errorf("RoundTrip() error = %v, want UnmatchedRequestError", err)
+	}
+	if unused := rec.Unused(); len(unused) != 1 || unused[0] != "GET /v4/user" {
+		t.Errorf("Unused() = %v", unused)
+	}
+}
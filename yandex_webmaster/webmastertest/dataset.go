@@ -0,0 +1,715 @@
+package webmastertest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// DatasetConfig - parameters of synthetic dataset, zero fields get defaults
+type DatasetConfig struct {
+	// Seed - the same seed and config with End set give the same dataset, Seed alone is not enough
+	// because dates of default End move every day
+	Seed int64
+	// Hosts - number of hosts, 3 by default
+	Hosts int
+	// Days - length of history, 180 by default
+	Days int
+	// End - last day of history, yesterday of current time by default
+	End time.Time
+	// Pages - average number of pages of host, 1000 by default
+	Pages int
+	// Queries - number of popular queries of host, 100 by default
+	Queries int
+}
+
+// Dataset - generated hosts with reports, load it to Server or write as json fixtures
+type Dataset struct {
+	Hosts []*HostData
+	// DateFrom - first day of history
+	DateFrom ywm.Date
+	// DateTo - last day of history
+	DateTo ywm.Date
+}
+
+var (
+	// datasetSites - host names with nouns of their search queries
+	datasetSites = []struct {
+		name  string
+		nouns []string
+	}{
+		{"bookshop", []string{"книги", "детективы", "учебники", "книги для детей"}},
+		{"garden-tools", []string{"лопата", "секатор", "газонокосилка", "теплица"}},
+		{"citynews", []string{"новости", "погода", "пробки", "афиша"}},
+		{"travel-notes", []string{"путешествия", "отели", "визы", "куда поехать"}},
+		{"pet-care", []string{"корм для кошек", "ветклиника", "груминг", "корм для собак"}},
+		{"bike-service", []string{"велосипед", "ремонт велосипеда", "велозапчасти", "шины"}},
+		{"cooking", []string{"рецепты", "пирог", "суп", "выпечка"}},
+		{"kids-toys", []string{"игрушки", "конструктор", "куклы", "настольные игры"}},
+		{"фермерский-рынок", []string{"овощи", "фермерские продукты", "мёд", "молоко"}},
+		{"autoparts", []string{"запчасти", "масло моторное", "фильтры", "аккумулятор"}},
+	}
+	datasetZones    = []string{"ru", "com", "net", "org"}
+	datasetSections = []string{"catalog", "blog", "news", "help", "products", "articles"}
+	datasetMods     = []string{"купить", "цена", "отзывы", "как выбрать", "доставка", "недорого", "москва", "своими руками", "лучшие", "2026", "официальный сайт", "каталог"}
+	datasetWords    = []string{"guide", "review", "best", "new", "sale", "top", "how-to", "classic", "summer", "winter", "gift", "set"}
+	// datasetWeekday - weekly seasonality of traffic, sunday first
+	datasetWeekday = [7]float64{0.78, 1, 1.05, 1.05, 1, 0.95, 0.8}
+)
+
+// GenerateDataset generates hosts with sitemap trees, indexing and search history with trend and seasonality,
+// popular queries with ctr falling with position, recrawl tasks and diagnostics.
+// Output is reproducible only when cfg.End is set
+func GenerateDataset(cfg DatasetConfig) *Dataset {
+	if cfg.Hosts <= 0 {
+		cfg.Hosts = 3
+	}
+	if cfg.Days <= 0 {
+		cfg.Days = 180
+	}
+	if cfg.Pages <= 0 {
+		cfg.Pages = 1000
+	}
+	if cfg.Queries <= 0 {
+		cfg.Queries = 100
+	}
+	if cfg.End.IsZero() {
+		cfg.End = time.Now().AddDate(0, 0, -1)
+	}
+	end := ywm.DateIn(cfg.End, ywm.DefaultLocation)
+	g := &generator{
+		rng: rand.New(rand.NewSource(cfg.Seed)),
+		cfg: cfg,
+		end: time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, ywm.DefaultLocation),
+	}
+	dataset := &Dataset{DateFrom: ywm.DateIn(g.day(0), ywm.DefaultLocation), DateTo: end}
+	sites := g.rng.Perm(len(datasetSites))
+	for i := 0; i < cfg.Hosts; i++ {
+		site := datasetSites[sites[i%len(sites)]]
+		name := site.name
+		if i >= len(sites) {
+			name = fmt.Sprintf("%s-%d", name, i/len(sites)+1)
+		}
+		dataset.Hosts = append(dataset.Hosts, g.host(name, site.nouns))
+	}
+	return dataset
+}
+
+// generator - state of dataset generation
+type generator struct {
+	rng *rand.Rand
+	cfg DatasetConfig
+	// end - midnight of last day in api reporting location
+	end time.Time
+}
+
+// hostProfile - shape of host traffic
+type hostProfile struct {
+	base  string
+	pages []string
+	// growth - relative change of traffic over history
+	growth float64
+	// indexed - share of pages in search
+	indexed float64
+}
+
+// day returns midnight of i-th day of history
+func (g *generator) day(i int) time.Time {
+	return g.end.AddDate(0, 0, i-g.cfg.Days+1)
+}
+
+// seasonality returns traffic multiplier of i-th day with trend, weekly and yearly cycles and noise
+func (g *generator) seasonality(p *hostProfile, i int) float64 {
+	t := g.day(i)
+	trend := 1 + p.growth*float64(i)/float64(g.cfg.Days)
+	yearly := 1 + 0.15*math.Cos(2*math.Pi*float64(t.YearDay()-15)/365)
+	return trend * yearly * datasetWeekday[t.Weekday()] * g.noise(0.05)
+}
+
+// noise returns multiplicative noise around one
+func (g *generator) noise(sigma float64) float64 {
+	return math.Max(0.1, 1+g.rng.NormFloat64()*sigma)
+}
+
+// timestamp returns random time of i-th day
+func (g *generator) timestamp(i int) ywm.Timestamp {
+	return ywm.NewTimestamp(g.day(i).Add(time.Duration(g.rng.Int63n(int64(24 * time.Hour)))).Truncate(time.Second))
+}
+
+// id returns random hex id
+func (g *generator) id(n int) string {
+	b := make([]byte, n/2)
+	g.rng.Read(b)
+	return fmt.Sprintf("%x", b)
+}
+
+func (g *generator) host(name string, nouns []string) *HostData {
+	zone := datasetZones[g.rng.Intn(len(datasetZones))]
+	if strings.IndexFunc(name, func(r rune) bool { return r > 127 }) >= 0 {
+		zone = "рф"
+	}
+	hostID, err := ywm.HostIDFromURL(fmt.Sprintf("https://%s.%s", name, zone))
+	if err != nil {
+		// names and zones are constants of dataset, so invalid url is a bug of generator
+		panic(fmt.Sprintf("webmastertest: invalid dataset host %s.%s: %v", name, zone, err))
+	}
+	p := &hostProfile{
+		base:    strings.TrimSuffix(hostID.UnicodeURL(), "/"),
+		growth:  -0.2 + 0.8*g.rng.Float64(),
+		indexed: 0.6 + 0.3*g.rng.Float64(),
+	}
+	pages := int(float64(g.cfg.Pages) * (0.5 + g.rng.Float64()))
+	if pages < 10 {
+		pages = 10
+	}
+	sections := datasetSections[:2+g.rng.Intn(len(datasetSections)-1)]
+	p.pages = append(p.pages, p.base+"/")
+	for i := 1; i < pages; i++ {
+		section := sections[g.rng.Intn(len(sections))]
+		word := datasetWords[g.rng.Intn(len(datasetWords))]
+		p.pages = append(p.pages, fmt.Sprintf("%s/%s/%s-%d/", p.base, section, word, i))
+	}
+
+	quota := 20 + 10*g.rng.Intn(50)
+	host := &HostData{
+		Host: ywm.Host{
+			HostID:          hostID,
+			AsciiHostURL:    hostID.ASCIIURL(),
+			UnicodeHostURL:  hostID.UnicodeURL(),
+			Verified:        true,
+			HostDataStatus:  "OK",
+			HostDisplayName: capitalize(strings.ReplaceAll(name, "-", " ")),
+		},
+		RecrawlQuota: ywm.RecrawlQuota{DailyQuota: quota, QuotaRemainder: quota},
+	}
+	g.sitemaps(host, p, sections)
+	g.indexing(host, p)
+	g.insearch(host, p)
+	g.importantURLs(host, p, sections)
+	g.recrawl(host, p)
+	g.queries(host, p, name, nouns)
+	g.diagnostics(host)
+	return host
+}
+
+// sitemaps generates sitemap index from robots.txt with sitemap of every section and sitemap added by user
+func (g *generator) sitemaps(host *HostData, p *hostProfile, sections []string) {
+	last := g.cfg.Days - 1 - g.rng.Intn(3)
+	index := &ywm.Sitemap{
+		SitemapID:      g.id(32),
+		SitemapURL:     p.base + "/sitemap.xml",
+		LastAccessDate: g.timestamp(last),
+		Sources:        []string{"ROBOTS_TXT"},
+		SitemapType:    "INDEX_SITEMAP",
+	}
+	host.Sitemaps = append(host.Sitemaps, &SitemapEntry{Sitemap: index})
+	counts := make(map[string]int)
+	for _, page := range p.pages[1:] {
+		counts[strings.SplitN(strings.TrimPrefix(page, p.base+"/"), "/", 2)[0]]++
+	}
+	for _, section := range sections {
+		// sections are split to sitemaps of 500 urls to get deeper trees
+		for part, left := 1, counts[section]; left > 0; part++ {
+			n := left
+			if n > 500 {
+				n = 500
+			}
+			left -= n
+			sitemapURL := fmt.Sprintf("%s/sitemap-%s.xml", p.base, section)
+			if part > 1 {
+				sitemapURL = fmt.Sprintf("%s/sitemap-%s-%d.xml", p.base, section, part)
+			}
+			sitemap := &ywm.Sitemap{
+				SitemapID:      g.id(32),
+				SitemapURL:     sitemapURL,
+				LastAccessDate: g.timestamp(last),
+				URLsCount:      n,
+				Sources:        []string{"INDEX_SITEMAP"},
+				SitemapType:    "SITEMAP",
+			}
+			if g.rng.Float64() < 0.2 {
+				sitemap.ErrorsCount = 1 + g.rng.Intn(5)
+			}
+			index.ChildrenCount++
+			index.URLsCount += n
+			index.ErrorsCount += sitemap.ErrorsCount
+			host.Sitemaps = append(host.Sitemaps, &SitemapEntry{ParentID: index.SitemapID, Sitemap: sitemap})
+		}
+	}
+	user := &ywm.Sitemap{
+		SitemapID:      g.id(32),
+		SitemapURL:     p.base + "/sitemap-images.xml",
+		LastAccessDate: g.timestamp(last),
+		URLsCount:      1 + g.rng.Intn(len(p.pages)),
+		Sources:        []string{"WEBMASTER"},
+		SitemapType:    "SITEMAP",
+	}
+	host.Sitemaps = append(host.Sitemaps, &SitemapEntry{Sitemap: user})
+	host.UserSitemaps = append(host.UserSitemaps, &ywm.AddedUserSitemap{
+		SitemapID:  user.SitemapID,
+		SitemapURL: user.SitemapURL,
+		AddedDate:  g.timestamp(g.rng.Intn(g.cfg.Days)),
+	})
+}
+
+// indexing generates daily crawl counts by http code and crawled url samples
+func (g *generator) indexing(host *HostData, p *hostProfile) {
+	host.IndexingHistory = make(map[ywm.IndexingIndicator][]*ywm.Indicator)
+	crawl := float64(len(p.pages)) * (0.05 + 0.1*g.rng.Float64())
+	for i := 0; i < g.cfg.Days; i++ {
+		total := crawl * g.seasonality(p, i)
+		errors := 0.003
+		// rare days of server errors
+		if g.rng.Float64() < 0.03 {
+			errors = 0.05 + 0.15*g.rng.Float64()
+		}
+		values := map[ywm.IndexingIndicator]float64{
+			ywm.IndexingIndicatorHTTP2XX: total * (0.9 - errors),
+			ywm.IndexingIndicatorHTTP3XX: total * 0.05 * g.noise(0.3),
+			ywm.IndexingIndicatorHTTP4XX: total * 0.03 * g.noise(0.3),
+			ywm.IndexingIndicatorHTTP5XX: total * errors,
+			ywm.IndexingIndicatorOther:   total * 0.01 * g.noise(0.5),
+		}
+		date := ywm.NewTimestamp(g.day(i))
+		for _, indicator := range ywm.AllIndexingIndicators {
+			host.IndexingHistory[indicator] = append(host.IndexingHistory[indicator], &ywm.Indicator{Date: date, Value: int(math.Round(values[indicator]))})
+		}
+	}
+	statuses := []struct {
+		status ywm.IndexingIndicator
+		codes  []int
+		weight float64
+	}{
+		{ywm.IndexingIndicatorHTTP2XX, []int{200}, 0.85},
+		{ywm.IndexingIndicatorHTTP3XX, []int{301, 302}, 0.07},
+		{ywm.IndexingIndicatorHTTP4XX, []int{404, 410, 403}, 0.05},
+		{ywm.IndexingIndicatorHTTP5XX, []int{500, 502, 503}, 0.02},
+		{ywm.IndexingIndicatorOther, []int{0}, 0.01},
+	}
+	for _, i := range g.sample(len(p.pages), 500) {
+		r := g.rng.Float64()
+		k := 0
+		for ; k < len(statuses)-1 && r >= statuses[k].weight; k++ {
+			r -= statuses[k].weight
+		}
+		host.IndexingSamples = append(host.IndexingSamples, &ywm.Sample{
+			Status:     statuses[k].status,
+			HTTPCode:   statuses[k].codes[g.rng.Intn(len(statuses[k].codes))],
+			URL:        p.pages[i],
+			AccessDate: g.timestamp(g.cfg.Days - 1 - g.rng.Intn(30)),
+		})
+	}
+	sort.Slice(host.IndexingSamples, func(i, j int) bool {
+		return host.IndexingSamples[i].AccessDate.After(host.IndexingSamples[j].AccessDate.Time)
+	})
+}
+
+// sample returns up to n random distinct indexes below total
+func (g *generator) sample(total int, n int) []int {
+	perm := g.rng.Perm(total)
+	if n < len(perm) {
+		perm = perm[:n]
+	}
+	return perm
+}
+
+// title returns page title of url
+func title(u string) string {
+	parts := strings.Split(strings.Trim(u, "/"), "/")
+	words := strings.Fields(strings.ReplaceAll(parts[len(parts)-1], "-", " "))
+	if len(parts) <= 3 {
+		return "Главная"
+	}
+	return capitalize(strings.Join(words, " "))
+}
+
+// capitalize returns s with upper first letter
+func capitalize(s string) string {
+	r := []rune(s)
+	if len(r) == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r[0])) + string(r[1:])
+}
+
+// insearch generates growing number of pages in search with appeared and removed events
+func (g *generator) insearch(host *HostData, p *hostProfile) {
+	host.EventsHistory = make(map[ywm.SearchEvent][]*ywm.Indicator)
+	prev := 0
+	for i := 0; i < g.cfg.Days; i++ {
+		trend := 1 + p.growth*float64(i)/float64(g.cfg.Days)
+		value := int(float64(len(p.pages)) * p.indexed * trend * g.noise(0.01))
+		date := ywm.NewTimestamp(g.day(i))
+		host.InsearchHistory = append(host.InsearchHistory, &ywm.InsearchURLHistoryData{Date: date, Value: value})
+		if i == 0 {
+			prev = value
+			continue
+		}
+		churn := int(float64(value) * 0.005 * g.noise(0.5))
+		appeared, removed := churn, churn
+		if diff := value - prev; diff > 0 {
+			appeared += diff
+		} else {
+			removed -= diff
+		}
+		prev = value
+		host.EventsHistory[ywm.SearchEventAppearedInSearch] = append(host.EventsHistory[ywm.SearchEventAppearedInSearch], &ywm.Indicator{Date: date, Value: appeared})
+		host.EventsHistory[ywm.SearchEventRemovedFromSearch] = append(host.EventsHistory[ywm.SearchEventRemovedFromSearch], &ywm.Indicator{Date: date, Value: removed})
+	}
+	for _, i := range g.sample(len(p.pages), int(float64(len(p.pages))*p.indexed)) {
+		if len(host.InsearchSamples) == 500 {
+			break
+		}
+		host.InsearchSamples = append(host.InsearchSamples, &ywm.InsearchSample{
+			URL:        p.pages[i],
+			LastAccess: g.timestamp(g.cfg.Days - 1 - g.rng.Intn(60)),
+			Title:      title(p.pages[i]),
+		})
+	}
+	excluded := []ywm.ExcludedURLStatus{
+		ywm.ExcludedURLStatusDuplicate, ywm.ExcludedURLStatusLowQuality, ywm.ExcludedURLStatusHTTPError,
+		ywm.ExcludedURLStatusNotCanonical, ywm.ExcludedURLStatusNoIndex, ywm.ExcludedURLStatusRedirectNotSearchable,
+	}
+	for _, i := range g.sample(len(p.pages), 200) {
+		day := g.cfg.Days - 1 - g.rng.Intn(30)
+		sample := &ywm.InsearchEventSample{
+			URL:        p.pages[i],
+			Title:      title(p.pages[i]),
+			EventDate:  ywm.NewTimestamp(g.day(day)),
+			LastAccess: g.timestamp(day),
+			Event:      ywm.SearchEventAppearedInSearch,
+		}
+		if g.rng.Float64() < 0.3 {
+			sample.Event = ywm.SearchEventRemovedFromSearch
+			sample.ExcludedURLStatus = excluded[g.rng.Intn(len(excluded))]
+			switch sample.ExcludedURLStatus {
+			case ywm.ExcludedURLStatusHTTPError:
+				sample.BadHTTPStatus = 404
+			case ywm.ExcludedURLStatusNotCanonical, ywm.ExcludedURLStatusDuplicate, ywm.ExcludedURLStatusRedirectNotSearchable:
+				sample.TargetURL = p.pages[g.rng.Intn(len(p.pages))]
+			}
+		}
+		host.EventSamples = append(host.EventSamples, sample)
+	}
+	sort.Slice(host.EventSamples, func(i, j int) bool {
+		return host.EventSamples[i].EventDate.After(host.EventSamples[j].EventDate.Time)
+	})
+}
+
+// importantURLs generates monitoring of main page and section pages with history of changes
+func (g *generator) importantURLs(host *HostData, p *hostProfile, sections []string) {
+	host.ImportantURLHistory = make(map[string][]*ywm.ImportantURL)
+	urls := []string{p.pages[0]}
+	for _, section := range sections[:1+g.rng.Intn(len(sections))] {
+		urls = append(urls, fmt.Sprintf("%s/%s/", p.base, section))
+	}
+	for _, u := range urls {
+		var history []*ywm.ImportantURL
+		state := &ywm.ImportantURL{
+			URL:            u,
+			UpdateDate:     g.timestamp(g.rng.Intn(g.cfg.Days / 2)),
+			IndexingStatus: &ywm.IndexingStatus{Status: ywm.IndexingIndicatorHTTP2XX, HTTPCode: 200},
+			SearchStatus:   &ywm.SearchStatus{Title: title(u), Description: "Описание страницы " + title(u), Searchable: true},
+		}
+		state.IndexingStatus.AccessDate = state.UpdateDate
+		state.SearchStatus.LastAccess = state.UpdateDate
+		history = append(history, state)
+		for day := g.cfg.Days / 2; day < g.cfg.Days; day += 7 + g.rng.Intn(30) {
+			next := *state
+			indexing, search := *state.IndexingStatus, *state.SearchStatus
+			next.IndexingStatus, next.SearchStatus = &indexing, &search
+			next.UpdateDate = g.timestamp(day)
+			next.IndexingStatus.AccessDate = next.UpdateDate
+			next.SearchStatus.LastAccess = next.UpdateDate
+			switch g.rng.Intn(3) {
+			case 0:
+				next.ChangeIndicators = []ywm.ChangeIndicator{ywm.ChangeIndicatorTitle}
+				next.SearchStatus.Title = title(u) + " — " + datasetWords[g.rng.Intn(len(datasetWords))]
+			case 1:
+				next.ChangeIndicators = []ywm.ChangeIndicator{ywm.ChangeIndicatorDescription}
+				next.SearchStatus.Description = "Обновлённое описание страницы " + title(u)
+			case 2:
+				// temporary server error with page removed from search and returned back
+				failed := next
+				failedIndexing, failedSearch := indexing, search
+				failed.IndexingStatus, failed.SearchStatus = &failedIndexing, &failedSearch
+				failed.ChangeIndicators = []ywm.ChangeIndicator{ywm.ChangeIndicatorIndexingHTTPCode, ywm.ChangeIndicatorSearchStatus}
+				failed.IndexingStatus.Status, failed.IndexingStatus.HTTPCode = ywm.IndexingIndicatorHTTP5XX, 503
+				failed.SearchStatus.Searchable = false
+				failed.SearchStatus.ExcludedURLStatus = ywm.ExcludedURLStatusHTTPError
+				failed.SearchStatus.BadHTTPStatus = 503
+				history = append(history, &failed)
+				next.ChangeIndicators = []ywm.ChangeIndicator{ywm.ChangeIndicatorIndexingHTTPCode, ywm.ChangeIndicatorSearchStatus}
+				next.UpdateDate = ywm.NewTimestamp(failed.UpdateDate.Add(24 * time.Hour))
+				next.IndexingStatus.AccessDate = next.UpdateDate
+				next.SearchStatus.LastAccess = next.UpdateDate
+			}
+			history = append(history, &next)
+			state = &next
+		}
+		host.ImportantURLHistory[u] = history
+		host.ImportantURLs = append(host.ImportantURLs, state)
+	}
+}
+
+// recrawl generates finished recrawl tasks of last two weeks
+func (g *generator) recrawl(host *HostData, p *hostProfile) {
+	for i := 0; i < 5+g.rng.Intn(20); i++ {
+		task := &ywm.RecrawlTask{
+			TaskID:    g.id(8) + "-" + g.id(4) + "-" + g.id(4) + "-" + g.id(4) + "-" + g.id(12),
+			URL:       p.pages[g.rng.Intn(len(p.pages))],
+			AddedTime: g.timestamp(g.cfg.Days - 1 - g.rng.Intn(14)),
+			State:     ywm.RecrawlTaskStateDone,
+		}
+		if g.rng.Float64() < 0.1 {
+			task.State = ywm.RecrawlTaskStateFailed
+		}
+		host.RecrawlTasks = append(host.RecrawlTasks, task)
+	}
+	sort.Slice(host.RecrawlTasks, func(i, j int) bool {
+		return host.RecrawlTasks[i].AddedTime.Before(host.RecrawlTasks[j].AddedTime.Time)
+	})
+}
+
+// ctr returns expected click-through rate at average position
+func ctr(position float64) float64 {
+	return 0.32 * math.Pow(position, -1.1)
+}
+
+// queries generates popular queries with zipf distributed shows and ctr falling with position, and their daily history
+func (g *generator) queries(host *HostData, p *hostProfile, name string, nouns []string) {
+	brand := strings.ReplaceAll(name, "-", " ")
+	texts := map[string]bool{}
+	shows := float64(len(p.pages)) * 50 * (0.5 + g.rng.Float64())
+	var weights []float64
+	for i := 0; len(host.PopularQueries) < g.cfg.Queries && i < g.cfg.Queries*20; i++ {
+		text := brand
+		if len(host.PopularQueries) > 0 {
+			text = nouns[g.rng.Intn(len(nouns))] + " " + datasetMods[g.rng.Intn(len(datasetMods))]
+			if g.rng.Float64() < 0.3 {
+				text += " " + datasetMods[g.rng.Intn(len(datasetMods))]
+			}
+			if g.rng.Float64() < 0.1 {
+				text = brand + " " + text
+			}
+		}
+		if texts[text] {
+			continue
+		}
+		texts[text] = true
+		rank := float64(len(host.PopularQueries) + 1)
+		weight := math.Pow(rank, -1.05) * g.noise(0.2)
+		position := 1 + math.Min(49, math.Exp(1.2+0.8*g.rng.NormFloat64()))
+		if strings.Contains(text, brand) {
+			position = 1 + 0.5*g.rng.Float64()
+		}
+		query := &ywm.PopularSearchQuery{QueryID: g.id(32), QueryText: text}
+		query.Indicators.AvgShowPosition = math.Round(position*10) / 10
+		weights = append(weights, weight)
+		host.PopularQueries = append(host.PopularQueries, query)
+	}
+	var sum float64
+	for _, w := range weights {
+		sum += w
+	}
+	for i, query := range host.PopularQueries {
+		q := &query.Indicators
+		q.TotalShows = math.Max(1, math.Round(shows*weights[i]/sum))
+		q.TotalClicks = math.Round(q.TotalShows * math.Min(0.9, ctr(q.AvgShowPosition)*g.noise(0.2)))
+		if q.TotalClicks > 0 {
+			// clicks come from impressions at better positions
+			q.AvgClickPosition = math.Round(math.Max(1, q.AvgShowPosition*(0.75+0.2*g.rng.Float64()))*10) / 10
+		}
+	}
+	sort.SliceStable(host.PopularQueries, func(i, j int) bool {
+		return host.PopularQueries[i].Indicators.TotalShows > host.PopularQueries[j].Indicators.TotalShows
+	})
+
+	host.QueryHistory = make(map[string]ywm.SearchAllHistoryIndicator)
+	shape := make([]float64, g.cfg.Days)
+	var total float64
+	for i := range shape {
+		shape[i] = g.seasonality(p, i)
+		total += shape[i]
+	}
+	totalShows := make([]float64, g.cfg.Days)
+	totalClicks := make([]float64, g.cfg.Days)
+	showPositions := make([]float64, g.cfg.Days)
+	clickPositions := make([]float64, g.cfg.Days)
+	for _, query := range host.PopularQueries {
+		q := query.Indicators
+		var history ywm.SearchAllHistoryIndicator
+		for i := 0; i < g.cfg.Days; i++ {
+			date := ywm.NewTimestamp(g.day(i))
+			dayShows := math.Round(q.TotalShows * shape[i] / total * g.noise(0.3))
+			position := math.Max(1, q.AvgShowPosition*g.noise(0.05))
+			dayClicks := math.Round(dayShows * math.Min(0.9, ctr(position)*g.noise(0.2)))
+			clickPosition := 0.0
+			if dayClicks > 0 {
+				clickPosition = math.Max(1, position*(0.75+0.2*g.rng.Float64()))
+			}
+			totalShows[i] += dayShows
+			totalClicks[i] += dayClicks
+			showPositions[i] += position * dayShows
+			clickPositions[i] += clickPosition * dayClicks
+			history.TotalShows = append(history.TotalShows, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: dayShows})
+			history.TotalClicks = append(history.TotalClicks, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: dayClicks})
+			history.AvgShowPosition = append(history.AvgShowPosition, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: round1(position)})
+			history.AvgClickPosition = append(history.AvgClickPosition, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: round1(clickPosition)})
+		}
+		host.QueryHistory[query.QueryID] = history
+	}
+	for i := 0; i < g.cfg.Days; i++ {
+		date := ywm.NewTimestamp(g.day(i))
+		var showPosition, clickPosition float64
+		if totalShows[i] > 0 {
+			showPosition = showPositions[i] / totalShows[i]
+		}
+		if totalClicks[i] > 0 {
+			clickPosition = clickPositions[i] / totalClicks[i]
+		}
+		h := &host.QueriesHistory
+		h.TotalShows = append(h.TotalShows, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: totalShows[i]})
+		h.TotalClicks = append(h.TotalClicks, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: totalClicks[i]})
+		h.AvgShowPosition = append(h.AvgShowPosition, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: round1(showPosition)})
+		h.AvgClickPosition = append(h.AvgClickPosition, &ywm.SeachAllHistoryIndicatorData{Date: date, Value: round1(clickPosition)})
+	}
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
+
+// diagnostics generates state of every known problem, severe problems are present less often
+func (g *generator) diagnostics(host *HostData) {
+	chance := map[ywm.DiagnosticSeverity]float64{
+		ywm.DiagnosticSeverityFatal:           0.03,
+		ywm.DiagnosticSeverityCritical:        0.1,
+		ywm.DiagnosticSeverityPossibleProblem: 0.25,
+		ywm.DiagnosticSeverityRecommendation:  0.4,
+	}
+	for _, problemType := range ywm.DiagnosticProblemTypes() {
+		info := ywm.LookupDiagnosticProblem(problemType)
+		problem := ywm.DiagnosticProblem{
+			Type:            problemType,
+			Severity:        info.Severity,
+			State:           ywm.DiagnosticStateAbsent,
+			LastStateUpdate: g.timestamp(g.rng.Intn(g.cfg.Days)),
+		}
+		if g.rng.Float64() < chance[info.Severity] {
+			problem.State = ywm.DiagnosticStatePresent
+		}
+		host.Diagnostics = append(host.Diagnostics, problem)
+	}
+}
+
+// LoadDataset adds hosts of dataset to server
+func (s *Server) LoadDataset(dataset *Dataset) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, host := range dataset.Hosts {
+		if _, ok := s.hosts[host.Host.HostID]; ok {
+			return fmt.Errorf("host %s already added", host.Host.HostID)
+		}
+	}
+	for _, host := range dataset.Hosts {
+		host.quotaDay = s.today()
+		s.hosts[host.Host.HostID] = host
+		s.order = append(s.order, host.Host.HostID)
+	}
+	return nil
+}
+
+// WriteFixtures writes responses of api for whole history as json files laid out like endpoints below user/{user-id}/:
+// hosts.json, hosts/{host}.json, hosts/{host}/indexing/history.json, hosts/{host}/search-queries/{query-id}/history.json, ...
+// Colons of host ids are replaced with underscores in file names.
+func (d *Dataset) WriteFixtures(dir string) error {
+	hosts := ywm.Hosts{Hosts: make([]*ywm.Host, 0, len(d.Hosts))}
+	for _, host := range d.Hosts {
+		h := host.Host
+		hosts.Hosts = append(hosts.Hosts, &h)
+	}
+	files := map[string]interface{}{"hosts.json": hosts}
+	for _, host := range d.Hosts {
+		base := "hosts/" + strings.ReplaceAll(string(host.Host.HostID), ":", "_")
+		files[base+".json"] = host.Host
+		sitemaps := make(map[string]*ywm.Sitemaps)
+		for _, entry := range host.Sitemaps {
+			name := base + "/sitemaps.json"
+			if entry.ParentID != "" {
+				name = base + "/sitemaps/" + entry.ParentID + "/children.json"
+			}
+			if sitemaps[name] == nil {
+				sitemaps[name] = &ywm.Sitemaps{}
+				files[name] = sitemaps[name]
+			}
+			sitemaps[name].Sitemaps = append(sitemaps[name].Sitemaps, entry.Sitemap)
+			files[base+"/sitemaps/"+entry.Sitemap.SitemapID+".json"] = entry.Sitemap
+		}
+		files[base+"/user-added-sitemaps.json"] = ywm.AddedUserSitemaps{Sitemaps: host.UserSitemaps, Count: len(host.UserSitemaps)}
+		for _, sitemap := range host.UserSitemaps {
+			files[base+"/user-added-sitemaps/"+sitemap.SitemapID+".json"] = sitemap
+		}
+		files[base+"/indexing/history.json"] = ywm.IndexingHistory{Indicators: host.IndexingHistory}
+		files[base+"/indexing/samples.json"] = ywm.SamplesResult{Count: len(host.IndexingSamples), Samples: host.IndexingSamples}
+		files[base+"/important-urls.json"] = ywm.ImportantURLS{URLS: host.ImportantURLs}
+		for i, u := range host.ImportantURLs {
+			files[fmt.Sprintf("%s/important-urls/history/%d.json", base, i)] = ywm.ImportantURLSHistory{History: host.ImportantURLHistory[u.URL]}
+		}
+		files[base+"/search-urls/in-search/history.json"] = ywm.InseacrhURLHistory{History: host.InsearchHistory}
+		files[base+"/search-urls/in-search/samples.json"] = ywm.InsearchSampleResponse{Count: len(host.InsearchSamples), Samples: host.InsearchSamples}
+		var events ywm.SearchURLEventHistoryResponse
+		events.Indicators.AppeadINSearch = host.EventsHistory[ywm.SearchEventAppearedInSearch]
+		events.Indicators.RemovedFromSearch = host.EventsHistory[ywm.SearchEventRemovedFromSearch]
+		files[base+"/search-urls/events/history.json"] = events
+		files[base+"/search-urls/events/samples.json"] = ywm.InsearchEventSampleResponse{Count: len(host.EventSamples), Samples: host.EventSamples}
+		files[base+"/recrawl/queue.json"] = ywm.RecrawlTasks{Tasks: host.RecrawlTasks}
+		files[base+"/recrawl/quota.json"] = host.RecrawlQuota
+		files[base+"/search-queries/popular.json"] = ywm.PopularSeachQueryResponse{
+			Queries:  host.PopularQueries,
+			DateFrom: d.DateFrom,
+			DateTo:   d.DateTo,
+			Count:    len(host.PopularQueries),
+		}
+		files[base+"/search-queries/all/history.json"] = ywm.SearchAllHistoryResponse{Indicators: host.QueriesHistory}
+		for _, q := range host.PopularQueries {
+			files[base+"/search-queries/"+q.QueryID+"/history.json"] = ywm.SearchSingleHistoryResponse{
+				QueryID:    q.QueryID,
+				QueryText:  q.QueryText,
+				Indicators: host.QueryHistory[q.QueryID],
+			}
+		}
+		files[base+"/diagnostics.json"] = ywm.DiagnosticProblemsResponse{Problems: host.Diagnostics}
+	}
+	for name, v := range files {
+		if err := writeFixture(filepath.Join(dir, filepath.FromSlash(name)), v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeFixture writes v as indented json
+func writeFixture(name string, v interface{}) error {
+	buf := &bytes.Buffer{}
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return fmt.Errorf("fixture %s: %w", name, err)
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(name, buf.Bytes(), 0o644)
+}
@@ -0,0 +1,196 @@
+package webmastertest
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func TestGenerateDatasetReproducible(t *testing.T) {
+	end := time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name string
+		a    DatasetConfig
+		b    DatasetConfig
+		same bool
+	}{
+		{name: "same seed and end", a: DatasetConfig{Seed: 1, End: end}, b: DatasetConfig{Seed: 1, End: end}, same: true},
+		{name: "other seed", a: DatasetConfig{Seed: 1, End: end}, b: DatasetConfig{Seed: 2, End: end}},
+		{name: "other end", a: DatasetConfig{Seed: 1, End: end}, b: DatasetConfig{Seed: 1, End: end.AddDate(0, 0, 1)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.a.Days, tt.b.Days = 30, 30
+			a, b := GenerateDataset(tt.a), GenerateDataset(tt.b)
+			if reflect.DeepEqual(a, b) != tt.same {
+				t.Errorf("datasets equal = %v, want %v", !tt.same, tt.same)
+			}
+		})
+	}
+}
+
+func testDataset() *Dataset {
+	return GenerateDataset(DatasetConfig{Seed: 7, Hosts: 2, Days: 14, Pages: 50, Queries: 30, End: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)})
+}
+
+func TestGenerateDatasetAllSites(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		dataset := GenerateDataset(DatasetConfig{Seed: seed, Hosts: len(datasetSites), Days: 7, Pages: 10, Queries: 5, End: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)})
+		if len(dataset.Hosts) != len(datasetSites) {
+			t.Fatalf("got %d hosts, want %d", len(dataset.Hosts), len(datasetSites))
+		}
+		for _, host := range dataset.Hosts {
+			if _, err := ywm.ParseHostID(string(host.Host.HostID)); err != nil {
+				t.Errorf("invalid host id %s: %v", host.Host.HostID, err)
+			}
+		}
+	}
+}
+
+func TestLoadDataset(t *testing.T) {
+	dataset := testDataset()
+	s := NewServer()
+	defer s.Close()
+	if err := s.LoadDataset(dataset); err != nil {
+		t.Fatal(err)
+	}
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hosts, err := client.Hosts.GetHosts()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(hosts.Hosts) != len(dataset.Hosts) {
+		t.Fatalf("got %d hosts, want %d", len(hosts.Hosts), len(dataset.Hosts))
+	}
+	for i, host := range dataset.Hosts {
+		if hosts.Hosts[i].HostID != host.Host.HostID || !hosts.Hosts[i].Verified {
+			t.Errorf("host %d: got %+v, want %s verified", i, hosts.Hosts[i], host.Host.HostID)
+		}
+		quota, err := client.Recrawl.GetRecrawlQuota(host.Host.HostID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(quota, host.RecrawlQuota) {
+			t.Errorf("quota %+v, want %+v", quota, host.RecrawlQuota)
+		}
+	}
+	// hosts of dataset can not be loaded twice
+	if err := s.LoadDataset(dataset); err == nil {
+		t.Errorf("expected error on loading dataset twice")
+	}
+}
+
+// readFixture decodes fixture file into v
+func readFixture(t *testing.T, dir string, name string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(name)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("%s: %v", name, err)
+	}
+}
+
+func TestWriteFixturesRoundTrip(t *testing.T) {
+	dataset := testDataset()
+	dir := t.TempDir()
+	if err := dataset.WriteFixtures(dir); err != nil {
+		t.Fatal(err)
+	}
+	var hosts ywm.Hosts
+	readFixture(t, dir, "hosts.json", &hosts)
+	if len(hosts.Hosts) != len(dataset.Hosts) {
+		t.Fatalf("got %d hosts, want %d", len(hosts.Hosts), len(dataset.Hosts))
+	}
+	for i, host := range dataset.Hosts {
+		if !reflect.DeepEqual(*hosts.Hosts[i], host.Host) {
+			t.Errorf("host %d: got %+v, want %+v", i, *hosts.Hosts[i], host.Host)
+		}
+		base := "hosts/" + strings.ReplaceAll(string(host.Host.HostID), ":", "_")
+
+		var quota ywm.RecrawlQuota
+		readFixture(t, dir, base+"/recrawl/quota.json", &quota)
+		if !reflect.DeepEqual(quota, host.RecrawlQuota) {
+			t.Errorf("quota %+v, want %+v", quota, host.RecrawlQuota)
+		}
+
+		var popular ywm.PopularSeachQueryResponse
+		readFixture(t, dir, base+"/search-queries/popular.json", &popular)
+		if popular.Count != len(host.PopularQueries) || len(popular.Queries) != len(host.PopularQueries) {
+			t.Fatalf("got %d popular queries, want %d", len(popular.Queries), len(host.PopularQueries))
+		}
+		for j, q := range host.PopularQueries {
+			got := popular.Queries[j]
+			if got.QueryID != q.QueryID || got.QueryText != q.QueryText || !reflect.DeepEqual(got.Indicators, q.Indicators) {
+				t.Errorf("query %d: got %+v, want %+v", j, got, q)
+			}
+			var history ywm.SearchSingleHistoryResponse
+			readFixture(t, dir, base+"/search-queries/"+q.QueryID+"/history.json", &history)
+			want := host.QueryHistory[q.QueryID]
+			if history.QueryID != q.QueryID || len(history.Indicators.TotalShows) != len(want.TotalShows) {
+				t.Fatalf("history of %s: got %d days, want %d", q.QueryID, len(history.Indicators.TotalShows), len(want.TotalShows))
+			}
+			for d, point := range want.TotalShows {
+				got := history.Indicators.TotalShows[d]
+				if got.Value != point.Value || !got.Date.Equal(point.Date.Time) {
+					t.Errorf("history of %s day %d: got %v %v, want %v %v", q.QueryID, d, got.Date, got.Value, point.Date, point.Value)
+				}
+			}
+		}
+
+		var diagnostics ywm.DiagnosticProblemsResponse
+		readFixture(t, dir, base+"/diagnostics.json", &diagnostics)
+		if len(diagnostics.Problems) != len(host.Diagnostics) {
+			t.Errorf("got %d problems, want %d", len(diagnostics.Problems), len(host.Diagnostics))
+		}
+		states := make(map[ywm.DiagnosticProblemType]ywm.DiagnosticState)
+		for _, p := range host.Diagnostics {
+			states[p.Type] = p.State
+		}
+		for _, p := range diagnostics.Problems {
+			if states[p.Type] != p.State {
+				t.Errorf("problem %s state %s, want %s", p.Type, p.State, states[p.Type])
+			}
+		}
+	}
+}
+
+func TestDatasetCTRFallsWithPosition(t *testing.T) {
+	dataset := GenerateDataset(DatasetConfig{Seed: 3, Hosts: 5, Days: 7, Pages: 100, Queries: 200, End: time.Date(2026, 9, 30, 0, 0, 0, 0, time.UTC)})
+	// buckets of average show position: top 3, first page, further pages
+	bounds := []float64{3, 10, 51}
+	shows := make([]float64, len(bounds))
+	clicks := make([]float64, len(bounds))
+	for _, host := range dataset.Hosts {
+		for _, q := range host.PopularQueries {
+			for i, bound := range bounds {
+				if q.Indicators.AvgShowPosition <= bound {
+					shows[i] += q.Indicators.TotalShows
+					clicks[i] += q.Indicators.TotalClicks
+					break
+				}
+			}
+		}
+	}
+	prev := 1.0
+	for i, bound := range bounds {
+		if shows[i] == 0 {
+			t.Fatalf("no queries at positions up to %v", bound)
+		}
+		ctr := clicks[i] / shows[i]
+		if ctr >= prev {
+			t.Errorf("ctr %.4f at positions up to %v is not below %.4f of better positions", ctr, bound, prev)
+		}
+		prev = ctr
+	}
+}
@@ -0,0 +1,460 @@
+package webmastertest
+
+import (
+	"sync"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// Call - recorded call of fake method
+type Call struct {
+	Method string
+	Args   []interface{}
+}
+
+// CallLog - calls recorded by fake
+type CallLog struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (l *CallLog) record(method string, args ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = append(l.calls, Call{Method: method, Args: args})
+}
+
+// Calls returns all recorded calls in order
+func (l *CallLog) Calls() []Call {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]Call(nil), l.calls...)
+}
+
+// CallsOf returns recorded calls of method
+func (l *CallLog) CallsOf(method string) []Call {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var calls []Call
+	for _, call := range l.calls {
+		if call.Method == method {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// Reset removes recorded calls
+func (l *CallLog) Reset() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.calls = nil
+}
+
+// FakeHosts - configurable fake of HostService, unset funcs return zero values
+type FakeHosts struct {
+	CallLog
+	GetHostsFunc   func() (ywm.Hosts, error)
+	GetHostFunc    func(hostID ywm.HostID) (ywm.Host, error)
+	AddHostFunc    func(hostURL string) (ywm.CreatedHost, error)
+	DeleteHostFunc func(hostID ywm.HostID) (interface{}, error)
+}
+
+var _ ywm.HostsAPI = (*FakeHosts)(nil)
+
+func (f *FakeHosts) GetHosts() (ywm.Hosts, error) {
+	f.record("GetHosts")
+	if f.GetHostsFunc != nil {
+		return f.GetHostsFunc()
+	}
+	var result ywm.Hosts
+	return result, nil
+}
+
+func (f *FakeHosts) GetHost(hostID ywm.HostID) (ywm.Host, error) {
+	f.record("GetHost", hostID)
+	if f.GetHostFunc != nil {
+		return f.GetHostFunc(hostID)
+	}
+	var result ywm.Host
+	return result, nil
+}
+
+func (f *FakeHosts) AddHost(hostURL string) (ywm.CreatedHost, error) {
+	f.record("AddHost", hostURL)
+	if f.AddHostFunc != nil {
+		return f.AddHostFunc(hostURL)
+	}
+	var result ywm.CreatedHost
+	return result, nil
+}
+
+func (f *FakeHosts) DeleteHost(hostID ywm.HostID) (interface{}, error) {
+	f.record("DeleteHost", hostID)
+	if f.DeleteHostFunc != nil {
+		return f.DeleteHostFunc(hostID)
+	}
+	var result interface{}
+	return result, nil
+}
+
+// FakeSitemaps - configurable fake of SitemapService, unset funcs return zero values
+type FakeSitemaps struct {
+	CallLog
+	GetSitemapsFunc          func(hostID ywm.HostID, req ywm.SitemapsRequest) (ywm.Sitemaps, error)
+	GetSitemapFunc           func(hostID ywm.HostID, sitemapID string) (ywm.Sitemap, error)
+	GetUserAddedSitemapsFunc func(hostID ywm.HostID, req ywm.UserAddedSitemapsRequest) (ywm.AddedUserSitemaps, error)
+	GetUserAddedSitemapFunc  func(hostID ywm.HostID, sitemapID string) (ywm.AddedUserSitemap, error)
+	AddSitemapFunc           func(hostID ywm.HostID, url string) (ywm.AddedSitemap, error)
+	DeleteSitemapFunc        func(hostID ywm.HostID, sitemapID string) (interface{}, error)
+}
+
+var _ ywm.SitemapsAPI = (*FakeSitemaps)(nil)
+
+func (f *FakeSitemaps) GetSitemaps(hostID ywm.HostID, req ywm.SitemapsRequest) (ywm.Sitemaps, error) {
+	f.record("GetSitemaps", hostID, req)
+	if f.GetSitemapsFunc != nil {
+		return f.GetSitemapsFunc(hostID, req)
+	}
+	var result ywm.Sitemaps
+	return result, nil
+}
+
+func (f *FakeSitemaps) GetSitemap(hostID ywm.HostID, sitemapID string) (ywm.Sitemap, error) {
+	f.record("GetSitemap", hostID, sitemapID)
+	if f.GetSitemapFunc != nil {
+		return f.GetSitemapFunc(hostID, sitemapID)
+	}
+	var result ywm.Sitemap
+	return result, nil
+}
+
+func (f *FakeSitemaps) GetUserAddedSitemaps(hostID ywm.HostID, req ywm.UserAddedSitemapsRequest) (ywm.AddedUserSitemaps, error) {
+	f.record("GetUserAddedSitemaps", hostID, req)
+	if f.GetUserAddedSitemapsFunc != nil {
+		return f.GetUserAddedSitemapsFunc(hostID, req)
+	}
+	var result ywm.AddedUserSitemaps
+	return result, nil
+}
+
+func (f *FakeSitemaps) GetUserAddedSitemap(hostID ywm.HostID, sitemapID string) (ywm.AddedUserSitemap, error) {
+	f.record("GetUserAddedSitemap", hostID, sitemapID)
+	if f.GetUserAddedSitemapFunc != nil {
+		return f.GetUserAddedSitemapFunc(hostID, sitemapID)
+	}
+	var result ywm.AddedUserSitemap
+	return result, nil
+}
+
+func (f *FakeSitemaps) AddSitemap(hostID ywm.HostID, url string) (ywm.AddedSitemap, error) {
+	f.record("AddSitemap", hostID, url)
+	if f.AddSitemapFunc != nil {
+		return f.AddSitemapFunc(hostID, url)
+	}
+	var result ywm.AddedSitemap
+	return result, nil
+}
+
+func (f *FakeSitemaps) DeleteSitemap(hostID ywm.HostID, sitemapID string) (interface{}, error) {
+	f.record("DeleteSitemap", hostID, sitemapID)
+	if f.DeleteSitemapFunc != nil {
+		return f.DeleteSitemapFunc(hostID, sitemapID)
+	}
+	var result interface{}
+	return result, nil
+}
+
+// FakeIndexing - configurable fake of IndexingService, unset funcs return zero values
+type FakeIndexing struct {
+	CallLog
+	GetIndexingHistoryFunc func(hostID ywm.HostID, req ywm.IndexingHistoryRequest) (ywm.IndexingHistory, error)
+	GetIndexingSamplesFunc func(hostID ywm.HostID, req ywm.IndexingSamplesRequest) (ywm.SamplesResult, error)
+}
+
+var _ ywm.IndexingAPI = (*FakeIndexing)(nil)
+
+func (f *FakeIndexing) GetIndexingHistory(hostID ywm.HostID, req ywm.IndexingHistoryRequest) (ywm.IndexingHistory, error) {
+	f.record("GetIndexingHistory", hostID, req)
+	if f.GetIndexingHistoryFunc != nil {
+		return f.GetIndexingHistoryFunc(hostID, req)
+	}
+	var result ywm.IndexingHistory
+	return result, nil
+}
+
+func (f *FakeIndexing) GetIndexingSamples(hostID ywm.HostID, req ywm.IndexingSamplesRequest) (ywm.SamplesResult, error) {
+	f.record("GetIndexingSamples", hostID, req)
+	if f.GetIndexingSamplesFunc != nil {
+		return f.GetIndexingSamplesFunc(hostID, req)
+	}
+	var result ywm.SamplesResult
+	return result, nil
+}
+
+// FakeImportantURL - configurable fake of ImportantURLService, unset funcs return zero values
+type FakeImportantURL struct {
+	CallLog
+	GetMonitoringImportantURLSFunc func(hostID ywm.HostID) (ywm.ImportantURLS, error)
+	GetImportantURLHistoryFunc     func(hostID ywm.HostID, url string) (ywm.ImportantURLSHistory, error)
+	GetImportantURLChangesFunc     func(hostID ywm.HostID, url string) ([]ywm.ImportantURLDiff, error)
+}
+
+var _ ywm.ImportantURLAPI = (*FakeImportantURL)(nil)
+
+func (f *FakeImportantURL) GetMonitoringImportantURLS(hostID ywm.HostID) (ywm.ImportantURLS, error) {
+	f.record("GetMonitoringImportantURLS", hostID)
+	if f.GetMonitoringImportantURLSFunc != nil {
+		return f.GetMonitoringImportantURLSFunc(hostID)
+	}
+	var result ywm.ImportantURLS
+	return result, nil
+}
+
+func (f *FakeImportantURL) GetImportantURLHistory(hostID ywm.HostID, url string) (ywm.ImportantURLSHistory, error) {
+	f.record("GetImportantURLHistory", hostID, url)
+	if f.GetImportantURLHistoryFunc != nil {
+		return f.GetImportantURLHistoryFunc(hostID, url)
+	}
+	var result ywm.ImportantURLSHistory
+	return result, nil
+}
+
+func (f *FakeImportantURL) GetImportantURLChanges(hostID ywm.HostID, url string) ([]ywm.ImportantURLDiff, error) {
+	f.record("GetImportantURLChanges", hostID, url)
+	if f.GetImportantURLChangesFunc != nil {
+		return f.GetImportantURLChangesFunc(hostID, url)
+	}
+	var result []ywm.ImportantURLDiff
+	return result, nil
+}
+
+// FakeInsearchURL - configurable fake of InsearchURLService, unset funcs return zero values
+type FakeInsearchURL struct {
+	CallLog
+	GetInsearchURLHistoryFunc       func(hostID ywm.HostID, req ywm.InsearchURLHistoryRequest) (ywm.InseacrhURLHistory, error)
+	GetInsearchURLSamplesFunc       func(hostID ywm.HostID, req ywm.InsearchURLSamplesRequest) (ywm.InsearchSampleResponse, error)
+	GetInsearchURLEventsHistoryFunc func(hostID ywm.HostID, req ywm.InsearchURLHistoryRequest) (ywm.SearchURLEventHistoryResponse, error)
+	GetInsearchURLEventSamplesFunc  func(hostID ywm.HostID, req ywm.InsearchURLSamplesRequest) (ywm.InsearchEventSampleResponse, error)
+}
+
+var _ ywm.InsearchURLAPI = (*FakeInsearchURL)(nil)
+
+func (f *FakeInsearchURL) GetInsearchURLHistory(hostID ywm.HostID, req ywm.InsearchURLHistoryRequest) (ywm.InseacrhURLHistory, error) {
+	f.record("GetInsearchURLHistory", hostID, req)
+	if f.GetInsearchURLHistoryFunc != nil {
+		return f.GetInsearchURLHistoryFunc(hostID, req)
+	}
+	var result ywm.InseacrhURLHistory
+	return result, nil
+}
+
+func (f *FakeInsearchURL) GetInsearchURLSamples(hostID ywm.HostID, req ywm.InsearchURLSamplesRequest) (ywm.InsearchSampleResponse, error) {
+	f.record("GetInsearchURLSamples", hostID, req)
+	if f.GetInsearchURLSamplesFunc != nil {
+		return f.GetInsearchURLSamplesFunc(hostID, req)
+	}
+	var result ywm.InsearchSampleResponse
+	return result, nil
+}
+
+func (f *FakeInsearchURL) GetInsearchURLEventsHistory(hostID ywm.HostID, req ywm.InsearchURLHistoryRequest) (ywm.SearchURLEventHistoryResponse, error) {
+	f.record("GetInsearchURLEventsHistory", hostID, req)
+	if f.GetInsearchURLEventsHistoryFunc != nil {
+		return f.GetInsearchURLEventsHistoryFunc(hostID, req)
+	}
+	var result ywm.SearchURLEventHistoryResponse
+	return result, nil
+}
+
+func (f *FakeInsearchURL) GetInsearchURLEventSamples(hostID ywm.HostID, req ywm.InsearchURLSamplesRequest) (ywm.InsearchEventSampleResponse, error) {
+	f.record("GetInsearchURLEventSamples", hostID, req)
+	if f.GetInsearchURLEventSamplesFunc != nil {
+		return f.GetInsearchURLEventSamplesFunc(hostID, req)
+	}
+	var result ywm.InsearchEventSampleResponse
+	return result, nil
+}
+
+// FakeRecrawl - configurable fake of RecrawlService, unset funcs return zero values
+type FakeRecrawl struct {
+	CallLog
+	RecrawlURLFunc      func(hostID ywm.HostID, url string) (ywm.RecrawlURLResponse, error)
+	GetRecrawlTaskFunc  func(hostID ywm.HostID, taskID string) (ywm.RecrawlTask, error)
+	GetRecrawlTasksFunc func(hostID ywm.HostID, req ywm.RecrawlTasksRequest) (ywm.RecrawlTasks, error)
+	GetRecrawlQuotaFunc func(hostID ywm.HostID) (ywm.RecrawlQuota, error)
+}
+
+var _ ywm.RecrawlAPI = (*FakeRecrawl)(nil)
+
+func (f *FakeRecrawl) RecrawlURL(hostID ywm.HostID, url string) (ywm.RecrawlURLResponse, error) {
+	f.record("RecrawlURL", hostID, url)
+	if f.RecrawlURLFunc != nil {
+		return f.RecrawlURLFunc(hostID, url)
+	}
+	var result ywm.RecrawlURLResponse
+	return result, nil
+}
+
+func (f *FakeRecrawl) GetRecrawlTask(hostID ywm.HostID, taskID string) (ywm.RecrawlTask, error) {
+	f.record("GetRecrawlTask", hostID, taskID)
+	if f.GetRecrawlTaskFunc != nil {
+		return f.GetRecrawlTaskFunc(hostID, taskID)
+	}
+	var result ywm.RecrawlTask
+	return result, nil
+}
+
+func (f *FakeRecrawl) GetRecrawlTasks(hostID ywm.HostID, req ywm.RecrawlTasksRequest) (ywm.RecrawlTasks, error) {
+	f.record("GetRecrawlTasks", hostID, req)
+	if f.GetRecrawlTasksFunc != nil {
+		return f.GetRecrawlTasksFunc(hostID, req)
+	}
+	var result ywm.RecrawlTasks
+	return result, nil
+}
+
+func (f *FakeRecrawl) GetRecrawlQuota(hostID ywm.HostID) (ywm.RecrawlQuota, error) {
+	f.record("GetRecrawlQuota", hostID)
+	if f.GetRecrawlQuotaFunc != nil {
+		return f.GetRecrawlQuotaFunc(hostID)
+	}
+	var result ywm.RecrawlQuota
+	return result, nil
+}
+
+// FakeSearchQuery - configurable fake of SearchQueryService, unset funcs return zero values
+type FakeSearchQuery struct {
+	CallLog
+	GetPopularSearchQueriesFunc     func(hostID ywm.HostID, req ywm.PopularQueriesRequest) (ywm.PopularSeachQueryResponse, error)
+	GetQueryAllHistoryFunc          func(hostID ywm.HostID, req ywm.QueryHistoryRequest) (ywm.SearchAllHistoryResponse, error)
+	GetSingleSearchQueryHistoryFunc func(hostID ywm.HostID, queryID string, req ywm.QueryHistoryRequest) (ywm.SearchSingleHistoryResponse, error)
+}
+
+var _ ywm.SearchQueryAPI = (*FakeSearchQuery)(nil)
+
+func (f *FakeSearchQuery) GetPopularSearchQueries(hostID ywm.HostID, req ywm.PopularQueriesRequest) (ywm.PopularSeachQueryResponse, error) {
+	f.record("GetPopularSearchQueries", hostID, req)
+	if f.GetPopularSearchQueriesFunc != nil {
+		return f.GetPopularSearchQueriesFunc(hostID, req)
+	}
+	var result ywm.PopularSeachQueryResponse
+	return result, nil
+}
+
+func (f *FakeSearchQuery) GetQueryAllHistory(hostID ywm.HostID, req ywm.QueryHistoryRequest) (ywm.SearchAllHistoryResponse, error) {
+	f.record("GetQueryAllHistory", hostID, req)
+	if f.GetQueryAllHistoryFunc != nil {
+		return f.GetQueryAllHistoryFunc(hostID, req)
+	}
+	var result ywm.SearchAllHistoryResponse
+	return result, nil
+}
+
+func (f *FakeSearchQuery) GetSingleSearchQueryHistory(hostID ywm.HostID, queryID string, req ywm.QueryHistoryRequest) (ywm.SearchSingleHistoryResponse, error) {
+	f.record("GetSingleSearchQueryHistory", hostID, queryID, req)
+	if f.GetSingleSearchQueryHistoryFunc != nil {
+		return f.GetSingleSearchQueryHistoryFunc(hostID, queryID, req)
+	}
+	var result ywm.SearchSingleHistoryResponse
+	return result, nil
+}
+
+// FakeDiagnostic - configurable fake of DiagnosticService, unset funcs return zero values
+type FakeDiagnostic struct {
+	CallLog
+	GetDiagnositcsFunc func(hostID ywm.HostID) (ywm.DiagnosticProblemsResponse, error)
+}
+
+var _ ywm.DiagnosticAPI = (*FakeDiagnostic)(nil)
+
+func (f *FakeDiagnostic) GetDiagnositcs(hostID ywm.HostID) (ywm.DiagnosticProblemsResponse, error) {
+	f.record("GetDiagnositcs", hostID)
+	if f.GetDiagnositcsFunc != nil {
+		return f.GetDiagnositcsFunc(hostID)
+	}
+	var result ywm.DiagnosticProblemsResponse
+	return result, nil
+}
+
+// FakeAPI - fake of all api services, implements yandexwebmaster.API. Zero value is ready to use,
+// nil fakes are created on first access, so they may be configured only where test needs it
+type FakeAPI struct {
+	mu sync.Mutex
+
+	Hosts        *FakeHosts
+	Sitemaps     *FakeSitemaps
+	Indexing     *FakeIndexing
+	ImportantURL *FakeImportantURL
+	InsearchURL  *FakeInsearchURL
+	Recrawl      *FakeRecrawl
+	SearchQuery  *FakeSearchQuery
+	Diagnostic   *FakeDiagnostic
+}
+
+var _ ywm.API = (*FakeAPI)(nil)
+
+// NewFakeAPI creates FakeAPI with unconfigured fakes of every service, fields may be configured
+// before use
+func NewFakeAPI() *FakeAPI {
+	return &FakeAPI{
+		Hosts:        &FakeHosts{},
+		Sitemaps:     &FakeSitemaps{},
+		Indexing:     &FakeIndexing{},
+		ImportantURL: &FakeImportantURL{},
+		InsearchURL:  &FakeInsearchURL{},
+		Recrawl:      &FakeRecrawl{},
+		SearchQuery:  &FakeSearchQuery{},
+		Diagnostic:   &FakeDiagnostic{},
+	}
+}
+
+// HostsAPI returns fake of Hosts service
+func (f *FakeAPI) HostsAPI() ywm.HostsAPI {
+	return lazyFake(&f.mu, &f.Hosts)
+}
+
+// SitemapsAPI returns fake of Sitemaps service
+func (f *FakeAPI) SitemapsAPI() ywm.SitemapsAPI {
+	return lazyFake(&f.mu, &f.Sitemaps)
+}
+
+// IndexingAPI returns fake of Indexing service
+func (f *FakeAPI) IndexingAPI() ywm.IndexingAPI {
+	return lazyFake(&f.mu, &f.Indexing)
+}
+
+// ImportantURLAPI returns fake of ImportantURL service
+func (f *FakeAPI) ImportantURLAPI() ywm.ImportantURLAPI {
+	return lazyFake(&f.mu, &f.ImportantURL)
+}
+
+// InsearchURLAPI returns fake of InsearchURL service
+func (f *FakeAPI) InsearchURLAPI() ywm.InsearchURLAPI {
+	return lazyFake(&f.mu, &f.InsearchURL)
+}
+
+// RecrawlAPI returns fake of Recrawl service
+func (f *FakeAPI) RecrawlAPI() ywm.RecrawlAPI {
+	return lazyFake(&f.mu, &f.Recrawl)
+}
+
+// SearchQueryAPI returns fake of SearchQuery service
+func (f *FakeAPI) SearchQueryAPI() ywm.SearchQueryAPI {
+	return lazyFake(&f.mu, &f.SearchQuery)
+}
+
+// DiagnosticAPI returns fake of Diagnostic service
+func (f *FakeAPI) DiagnosticAPI() ywm.DiagnosticAPI {
+	return lazyFake(&f.mu, &f.Diagnostic)
+}
+
+// lazyFake returns fake of field creating it if field is nil
+func lazyFake[T any](mu *sync.Mutex, field **T) *T {
+	mu.Lock()
+	defer mu.Unlock()
+	if *field == nil {
+		*field = new(T)
+	}
+	return *field
+}
@@ -0,0 +1,155 @@
+package webmastertest
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+func TestFakeAPIZeroValue(t *testing.T) {
+	var fake FakeAPI
+	var api ywm.API = &fake
+	if _, err := api.HostsAPI().GetHosts(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.RecrawlAPI().GetRecrawlQuota("https:example.com:443"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.DiagnosticAPI().GetDiagnositcs("https:example.com:443"); err != nil {
+		t.Fatal(err)
+	}
+	// accessors create fakes once and keep them in fields
+	if fake.Hosts == nil || api.HostsAPI() != ywm.HostsAPI(fake.Hosts) {
+		t.Errorf("hosts fake is not kept in field")
+	}
+	if got := fake.Hosts.CallsOf("GetHosts"); len(got) != 1 {
+		t.Errorf("got %d GetHosts calls, want 1", len(got))
+	}
+	if fake.Sitemaps != nil {
+		t.Errorf("unused fake is created")
+	}
+}
+
+func TestFakeAPIConcurrentAccess(t *testing.T) {
+	var fake FakeAPI
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			fake.SearchQueryAPI().GetPopularSearchQueries("https:example.com:443", ywm.PopularQueriesRequest{})
+		}()
+	}
+	wg.Wait()
+	if got := len(fake.SearchQuery.Calls()); got != 10 {
+		t.Errorf("got %d calls, want 10", got)
+	}
+}
+
+func TestNewFakeAPI(t *testing.T) {
+	fake := NewFakeAPI()
+	fields := reflect.ValueOf(fake).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		if field := fields.Field(i); field.Kind() == reflect.Ptr && field.IsNil() {
+			t.Errorf("%s is nil", fields.Type().Field(i).Name)
+		}
+	}
+}
+
+func TestFakeRecordsCalls(t *testing.T) {
+	hostID := ywm.HostID("https:example.com:443")
+	fake := NewFakeAPI()
+	req := ywm.RecrawlTasksRequest{Limit: 10}
+	fake.RecrawlAPI().RecrawlURL(hostID, "https://example.com/a")
+	fake.RecrawlAPI().GetRecrawlTasks(hostID, req)
+	fake.RecrawlAPI().RecrawlURL(hostID, "https://example.com/b")
+
+	want := []Call{
+		{Method: "RecrawlURL", Args: []interface{}{hostID, "https://example.com/a"}},
+		{Method: "GetRecrawlTasks", Args: []interface{}{hostID, req}},
+		{Method: "RecrawlURL", Args: []interface{}{hostID, "https://example.com/b"}},
+	}
+	if got := fake.Recrawl.Calls(); !reflect.DeepEqual(got, want) {
+		t.Errorf("calls %+v, want %+v", got, want)
+	}
+	if got := fake.Recrawl.CallsOf("RecrawlURL"); !reflect.DeepEqual(got, []Call{want[0], want[2]}) {
+		t.Errorf("RecrawlURL calls %+v", got)
+	}
+	if got := fake.Recrawl.CallsOf("GetRecrawlQuota"); got != nil {
+		t.Errorf("GetRecrawlQuota calls %+v, want none", got)
+	}
+	fake.Recrawl.Reset()
+	if got := fake.Recrawl.Calls(); len(got) != 0 {
+		t.Errorf("calls after reset %+v", got)
+	}
+}
+
+func TestFakeReturnsConfiguredResponses(t *testing.T) {
+	hostID := ywm.HostID("https:example.com:443")
+	errQuota := errors.New("quota failed")
+	tests := []struct {
+		name      string
+		configure func(f *FakeRecrawl)
+		want      ywm.RecrawlQuota
+		wantErr   error
+	}{
+		{name: "unconfigured", configure: func(f *FakeRecrawl) {}},
+		{
+			name: "response",
+			configure: func(f *FakeRecrawl) {
+				f.GetRecrawlQuotaFunc = func(id ywm.HostID) (ywm.RecrawlQuota, error) {
+					if id != hostID {
+						t.Errorf("host %s, want %s", id, hostID)
+					}
+					return ywm.RecrawlQuota{DailyQuota: 10, QuotaRemainder: 3}, nil
+				}
+			},
+			want: ywm.RecrawlQuota{DailyQuota: 10, QuotaRemainder: 3},
+		},
+		{
+			name: "error",
+			configure: func(f *FakeRecrawl) {
+				f.GetRecrawlQuotaFunc = func(ywm.HostID) (ywm.RecrawlQuota, error) {
+					return ywm.RecrawlQuota{}, errQuota
+				}
+			},
+			wantErr: errQuota,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &FakeRecrawl{}
+			tt.configure(fake)
+			got, err := fake.GetRecrawlQuota(hostID)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("error %v, want %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			// failed calls are recorded too
+			if calls := fake.CallsOf("GetRecrawlQuota"); len(calls) != 1 {
+				t.Errorf("got %d calls, want 1", len(calls))
+			}
+		})
+	}
+}
+
+func TestFakeHostsConfiguredError(t *testing.T) {
+	fake := &FakeHosts{
+		AddHostFunc: func(hostURL string) (ywm.CreatedHost, error) {
+			return ywm.CreatedHost{}, &ywm.YandexWebmasterError{HTTPCode: 409, ErrorData: "HOST_ALREADY_ADDED"}
+		},
+	}
+	_, err := fake.AddHost("https://example.com")
+	var apiErr *ywm.YandexWebmasterError
+	if !errors.As(err, &apiErr) || apiErr.HTTPCode != 409 {
+		t.Errorf("got %v, want api error 409", err)
+	}
+	if got := fake.Calls(); !reflect.DeepEqual(got, []Call{{Method: "AddHost", Args: []interface{}{"https://example.com"}}}) {
+		t.Errorf("calls %+v", got)
+	}
+}
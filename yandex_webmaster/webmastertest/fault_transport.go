@@ -0,0 +1,268 @@
+package webmastertest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// FaultKind - kind of failure injected by FaultTransport
+type FaultKind string
+
+const (
+	// FaultLatency - delay request by Latency and pass it on, other rules are still applied
+	FaultLatency FaultKind = "LATENCY"
+	// FaultRateLimit - respond 429 with Retry-After to Burst requests in a row
+	FaultRateLimit FaultKind = "RATE_LIMIT"
+	// FaultServerError - respond json error with Status, 503 by default
+	FaultServerError FaultKind = "SERVER_ERROR"
+	// FaultConnectionReset - fail request with connection reset by peer
+	FaultConnectionReset FaultKind = "CONNECTION_RESET"
+	// FaultTruncatedBody - pass request on and cut response body after TruncateAt bytes
+	FaultTruncatedBody FaultKind = "TRUNCATED_BODY"
+	// FaultMalformedJSON - pass request on and cut complete response body after TruncateAt bytes, so json is broken
+	FaultMalformedJSON FaultKind = "MALFORMED_JSON"
+	// FaultHTMLError - respond html error page of balancer with Status, 502 by default
+	FaultHTMLError FaultKind = "HTML_ERROR"
+)
+
+// FaultRule - failure injected into requests matching Method and Path with Probability
+type FaultRule struct {
+	Kind FaultKind
+	// Method - http method, any if empty
+	Method string
+	// Path - path.Match pattern of path below /v4/, e.g. "user/*/hosts/*/recrawl/queue", any if empty
+	Path string
+	// Probability - chance to inject fault into matching request, fault is always injected if zero
+	Probability float64
+	// Latency - delay of FaultLatency
+	Latency time.Duration
+	// Status - response status of FaultServerError and FaultHTMLError
+	Status int
+	// RetryAfter - Retry-After of FaultRateLimit, one second by default
+	RetryAfter time.Duration
+	// Burst - number of matching requests in a row rejected by FaultRateLimit, one by default
+	Burst int
+	// TruncateAt - bytes of body kept by FaultTruncatedBody and FaultMalformedJSON, half of body by default
+	TruncateAt int
+}
+
+// InjectedFault - fault injected into request
+type InjectedFault struct {
+	Kind   FaultKind
+	Method string
+	Path   string
+}
+
+// FaultTransport - http.RoundTripper which injects failures between client and api,
+// transport created without NewFaultTransport uses seed 0
+type FaultTransport struct {
+	// Transport - transport to api, http.DefaultTransport if nil
+	Transport http.RoundTripper
+	Rules     []FaultRule
+
+	mu  sync.Mutex
+	rng *rand.Rand
+	// bursts - remaining rate limited requests by rule index
+	bursts   map[int]int
+	injected []InjectedFault
+}
+
+// NewFaultTransport creates transport with rules, the same seed gives the same sequence of faults
+func NewFaultTransport(seed int64, rules ...FaultRule) *FaultTransport {
+	return &FaultTransport{
+		Rules:  rules,
+		rng:    rand.New(rand.NewSource(seed)),
+		bursts: make(map[int]int),
+	}
+}
+
+// HTTPClient returns client using transport, pass it to yandexwebmaster.WithHTTPClient
+func (t *FaultTransport) HTTPClient() *http.Client {
+	return &http.Client{Transport: t}
+}
+
+// Injected returns injected faults in order
+func (t *FaultTransport) Injected() []InjectedFault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]InjectedFault(nil), t.injected...)
+}
+
+// endpointPath returns path of api endpoint below /v4/
+func endpointPath(u *url.URL) string {
+	p := u.EscapedPath()
+	if i := strings.Index(p, "/v4/"); i >= 0 {
+		return p[i+len("/v4/"):]
+	}
+	return strings.TrimPrefix(p, "/")
+}
+
+func (r *FaultRule) matches(method string, p string) bool {
+	if r.Method != "" && r.Method != method {
+		return false
+	}
+	if r.Path != "" {
+		if ok, _ := path.Match(r.Path, p); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// pick returns latency to apply and rule of terminal fault for request
+func (t *FaultTransport) pick(method string, p string) (time.Duration, *FaultRule) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rng == nil {
+		t.rng = rand.New(rand.NewSource(0))
+	}
+	if t.bursts == nil {
+		t.bursts = make(map[int]int)
+	}
+	var latency time.Duration
+	for i := range t.Rules {
+		rule := &t.Rules[i]
+		if !rule.matches(method, p) {
+			continue
+		}
+		if t.bursts[i] > 0 {
+			t.bursts[i]--
+			t.injected = append(t.injected, InjectedFault{Kind: rule.Kind, Method: method, Path: p})
+			return latency, rule
+		}
+		if rule.Probability > 0 && t.rng.Float64() >= rule.Probability {
+			continue
+		}
+		t.injected = append(t.injected, InjectedFault{Kind: rule.Kind, Method: method, Path: p})
+		if rule.Kind == FaultLatency {
+			latency += rule.Latency
+			continue
+		}
+		if rule.Kind == FaultRateLimit && rule.Burst > 1 {
+			t.bursts[i] = rule.Burst - 1
+		}
+		return latency, rule
+	}
+	return latency, nil
+}
+
+func (t *FaultTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	latency, rule := t.pick(req.Method, endpointPath(req.URL))
+	if latency > 0 {
+		timer := time.NewTimer(latency)
+		select {
+		case <-timer.C:
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		}
+	}
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	if rule == nil {
+		return transport.RoundTrip(req)
+	}
+	if req.Body != nil && rule.Kind != FaultTruncatedBody && rule.Kind != FaultMalformedJSON {
+		req.Body.Close()
+	}
+	switch rule.Kind {
+	case FaultRateLimit:
+		retryAfter := rule.RetryAfter
+		if retryAfter <= 0 {
+			retryAfter = time.Second
+		}
+		resp := faultResponse(req, http.StatusTooManyRequests, "application/json; charset=utf-8",
+			`{"error_code":"TOO_MANY_REQUESTS_ERROR","error_message":"too many requests"}`)
+		resp.Header.Set("Retry-After", strconv.Itoa(int((retryAfter+time.Second-1)/time.Second)))
+		return resp, nil
+	case FaultServerError:
+		status := rule.Status
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		return faultResponse(req, status, "application/json; charset=utf-8",
+			fmt.Sprintf(`{"error_code":"INTERNAL_ERROR","error_message":%q}`, http.StatusText(status))), nil
+	case FaultConnectionReset:
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	case FaultMalformedJSON:
+		resp, body, err := truncate(transport, req, rule.TruncateAt)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(bytes.NewReader(body))
+		resp.ContentLength = int64(len(body))
+		resp.Header.Del("Content-Length")
+		return resp, nil
+	case FaultHTMLError:
+		status := rule.Status
+		if status == 0 {
+			status = http.StatusBadGateway
+		}
+		page := fmt.Sprintf("<html><head><title>%d %s</title></head><body><h1>%d %s</h1><hr><center>nginx</center></body></html>",
+			status, http.StatusText(status), status, http.StatusText(status))
+		return faultResponse(req, status, "text/html; charset=utf-8", page), nil
+	case FaultTruncatedBody:
+		resp, body, err := truncate(transport, req, rule.TruncateAt)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{io.ErrUnexpectedEOF}))
+		return resp, nil
+	}
+	return transport.RoundTrip(req)
+}
+
+// truncate passes request on and returns response with its body cut after keep bytes, half of body by default
+func truncate(transport http.RoundTripper, req *http.Request, keep int) (*http.Response, []byte, error) {
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, nil, err
+	}
+	if keep <= 0 || keep >= len(body) {
+		keep = len(body) / 2
+	}
+	return resp, body[:keep], nil
+}
+
+// errReader - reader failing with err
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// faultResponse builds response with body
+func faultResponse(req *http.Request, status int, contentType string, body string) *http.Response {
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {contentType}},
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
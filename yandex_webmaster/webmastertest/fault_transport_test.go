@@ -0,0 +1,177 @@
+package webmastertest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// get sends request through transport to path below server url
+func get(t *testing.T, ft *FaultTransport, s *Server, path string) (*http.Response, []byte, error) {
+	t.Helper()
+	req, err := http.NewRequest(http.MethodGet, s.URL+path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "OAuth "+s.Token)
+	resp, err := ft.HTTPClient().Do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	return resp, body, err
+}
+
+func TestFaultTransportKinds(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddHost("https://example.com")
+	tests := []struct {
+		name        string
+		rule        FaultRule
+		status      int
+		contentType string
+		wantErr     error
+		validJSON   bool
+	}{
+		{name: "no fault", rule: FaultRule{Kind: FaultServerError, Path: "user/*/hosts/*/sitemaps"}, status: http.StatusOK, validJSON: true},
+		{name: "latency", rule: FaultRule{Kind: FaultLatency, Latency: time.Millisecond}, status: http.StatusOK, validJSON: true},
+		{name: "rate limit", rule: FaultRule{Kind: FaultRateLimit, RetryAfter: 1500 * time.Millisecond}, status: http.StatusTooManyRequests, validJSON: true},
+		{name: "server error", rule: FaultRule{Kind: FaultServerError}, status: http.StatusServiceUnavailable, validJSON: true},
+		{name: "server error status", rule: FaultRule{Kind: FaultServerError, Status: http.StatusInternalServerError}, status: http.StatusInternalServerError, validJSON: true},
+		{name: "html error", rule: FaultRule{Kind: FaultHTMLError}, status: http.StatusBadGateway, contentType: "text/html; charset=utf-8"},
+		{name: "malformed json", rule: FaultRule{Kind: FaultMalformedJSON}, status: http.StatusOK},
+		{name: "truncated body", rule: FaultRule{Kind: FaultTruncatedBody, TruncateAt: 5}, wantErr: io.ErrUnexpectedEOF},
+		{name: "connection reset", rule: FaultRule{Kind: FaultConnectionReset}, wantErr: syscall.ECONNRESET},
+		{name: "other method", rule: FaultRule{Kind: FaultServerError, Method: http.MethodPost}, status: http.StatusOK, validJSON: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := NewFaultTransport(1, tt.rule)
+			resp, body, err := get(t, ft, s, "user/1/hosts/https:example.com:443")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != tt.status {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.status)
+			}
+			if tt.contentType != "" && resp.Header.Get("Content-Type") != tt.contentType {
+				t.Errorf("Content-Type = %q", resp.Header.Get("Content-Type"))
+			}
+			if json.Valid(body) != tt.validJSON {
+				t.Errorf("json.Valid(%s) = %v", body, !tt.validJSON)
+			}
+		})
+	}
+}
+
+func TestFaultTransportMalformedJSONTruncatesResponse(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.AddHost("https://example.com")
+	_, full, err := get(t, &FaultTransport{}, s, "user/1/hosts/https:example.com:443/summary")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		truncateAt int
+		want       string
+	}{
+		{truncateAt: 0, want: string(full[:len(full)/2])},
+		{truncateAt: 10, want: string(full[:10])},
+		{truncateAt: len(full), want: string(full[:len(full)/2])},
+	}
+	for _, tt := range tests {
+		ft := NewFaultTransport(1, FaultRule{Kind: FaultMalformedJSON, Path: "user/*/hosts/*/summary", TruncateAt: tt.truncateAt})
+		resp, body, err := get(t, ft, s, "user/1/hosts/https:example.com:443/summary")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != tt.want || resp.ContentLength != int64(len(tt.want)) {
+			t.Errorf("TruncateAt %d: body = %s (%d), want %s", tt.truncateAt, body, resp.ContentLength, tt.want)
+		}
+		if !strings.HasPrefix(string(full), string(body)) {
+			t.Errorf("body %s is not prefix of response", body)
+		}
+	}
+}
+
+func TestFaultTransportRateLimit(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ft := NewFaultTransport(1, FaultRule{Kind: FaultRateLimit, RetryAfter: 1500 * time.Millisecond})
+	resp, _, err := get(t, ft, s, "user")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Header.Get("Retry-After") != "2" {
+		t.Errorf("Retry-After = %q, want 2", resp.Header.Get("Retry-After"))
+	}
+}
+
+func TestFaultTransportZeroValue(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	ft := &FaultTransport{Rules: []FaultRule{
+		{Kind: FaultServerError, Probability: 0.5},
+		{Kind: FaultRateLimit, Burst: 2},
+	}}
+	var kinds []FaultKind
+	for i := 0; i < 6; i++ {
+		if _, _, err := get(t, ft, s, "user"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, fault := range ft.Injected() {
+		kinds = append(kinds, fault.Kind)
+	}
+	if len(kinds) != 6 {
+		t.Errorf("Injected() = %v, want fault for every request", kinds)
+	}
+	seeded := NewFaultTransport(0, ft.Rules...)
+	for i := 0; i < 6; i++ {
+		get(t, seeded, s, "user")
+	}
+	if !reflect.DeepEqual(seeded.Injected(), ft.Injected()) {
+		t.Errorf("zero value transport = %v, want the same faults as seed 0 %v", ft.Injected(), seeded.Injected())
+	}
+}
+
+func TestFaultTransportSeed(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	rules := []FaultRule{
+		{Kind: FaultLatency, Probability: 0.3, Latency: time.Microsecond},
+		{Kind: FaultServerError, Probability: 0.5},
+	}
+	run := func(seed int64) []InjectedFault {
+		ft := NewFaultTransport(seed, rules...)
+		for i := 0; i < 20; i++ {
+			get(t, ft, s, "user/1/hosts")
+		}
+		return ft.Injected()
+	}
+	first := run(7)
+	if len(first) == 0 || len(first) == 40 {
+		t.Fatalf("Injected() = %v, want some faults", first)
+	}
+	if second := run(7); !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gives %v and %v", first, second)
+	}
+	if other := run(8); reflect.DeepEqual(first, other) {
+		t.Errorf("different seeds give the same faults %v", first)
+	}
+}
@@ -0,0 +1,443 @@
+package webmastertest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// parseDates reads date_from and date_to params
+func parseDates(query url.Values) (dateRange, *apiError) {
+	var r dateRange
+	for _, param := range []struct {
+		key  string
+		date *ywm.Date
+	}{{"date_from", &r.from}, {"date_to", &r.to}} {
+		value := query.Get(param.key)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(ywm.YYYYMMDD, value)
+		if err != nil {
+			return r, errValidation(param.key, value)
+		}
+		*param.date = ywm.DateOf(t)
+	}
+	if !r.from.IsZero() && !r.to.IsZero() && r.from.After(r.to.Time) {
+		return r, errValidation("date_from", query.Get("date_from"))
+	}
+	return r, nil
+}
+
+// intParam reads int param in [0, max], def is used if param is missing
+func intParam(query url.Values, key string, def int, max int) (int, *apiError) {
+	value := query.Get(key)
+	if value == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 || (max > 0 && n > max) {
+		return 0, errValidation(key, value)
+	}
+	return n, nil
+}
+
+// page returns bounds of page of total items by offset and limit params
+func page(query url.Values, total int, defLimit int, maxLimit int) (int, int, *apiError) {
+	offset, apiErr := intParam(query, "offset", 0, 0)
+	if apiErr != nil {
+		return 0, 0, apiErr
+	}
+	limit, apiErr := intParam(query, "limit", defLimit, maxLimit)
+	if apiErr != nil {
+		return 0, 0, apiErr
+	}
+	if offset > total {
+		offset = total
+	}
+	end := offset + limit
+	if end > total {
+		end = total
+	}
+	return offset, end, nil
+}
+
+// decodeBody decodes json request body
+func decodeBody(body []byte, v interface{}) *apiError {
+	if err := json.Unmarshal(body, v); err != nil {
+		return &apiError{status: http.StatusBadRequest, code: "INVALID_JSON", message: err.Error()}
+	}
+	return nil
+}
+
+func (s *Server) getHosts() (int, interface{}, *apiError) {
+	result := ywm.Hosts{Hosts: make([]*ywm.Host, 0, len(s.order))}
+	for _, hostID := range s.order {
+		host := s.hosts[hostID].Host
+		result.Hosts = append(result.Hosts, &host)
+	}
+	return http.StatusOK, result, nil
+}
+
+func (s *Server) addHost(body []byte) (int, interface{}, *apiError) {
+	var req struct {
+		HostURL string `json:"host_url"`
+	}
+	if apiErr := decodeBody(body, &req); apiErr != nil {
+		return 0, nil, apiErr
+	}
+	host, err := s.newHost(req.HostURL, false)
+	if err != nil {
+		return 0, nil, errValidation("host_url", req.HostURL)
+	}
+	hostID := host.Host.HostID
+	if existing, ok := s.hosts[hostID]; ok {
+		return 0, nil, &apiError{
+			status:  http.StatusConflict,
+			code:    "HOST_ALREADY_ADDED",
+			message: "host already added",
+			fields:  map[string]interface{}{"host_id": hostID, "verified": existing.Host.Verified},
+		}
+	}
+	s.hosts[hostID] = host
+	s.order = append(s.order, hostID)
+	return http.StatusCreated, ywm.CreatedHost{HostID: hostID}, nil
+}
+
+func (s *Server) getSitemaps(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	parentID := query.Get("parent_id")
+	var entries []*SitemapEntry
+	for _, entry := range host.Sitemaps {
+		if entry.ParentID == parentID {
+			entries = append(entries, entry)
+		}
+	}
+	if from := query.Get("from"); from != "" {
+		start := len(entries)
+		for i, entry := range entries {
+			if entry.Sitemap.SitemapID == from {
+				start = i + 1
+				break
+			}
+		}
+		entries = entries[start:]
+	}
+	limit, apiErr := intParam(query, "limit", 10, 100)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	if limit < len(entries) {
+		entries = entries[:limit]
+	}
+	result := ywm.Sitemaps{Sitemaps: make([]*ywm.Sitemap, 0, len(entries))}
+	for _, entry := range entries {
+		result.Sitemaps = append(result.Sitemaps, entry.Sitemap)
+	}
+	return http.StatusOK, result, nil
+}
+
+func (s *Server) getSitemap(host *HostData, sitemapID string) (int, interface{}, *apiError) {
+	for _, entry := range host.Sitemaps {
+		if entry.Sitemap.SitemapID == sitemapID {
+			return http.StatusOK, entry.Sitemap, nil
+		}
+	}
+	return 0, nil, errNotFound()
+}
+
+func (s *Server) addSitemap(host *HostData, body []byte) (int, interface{}, *apiError) {
+	var req struct {
+		URL string `json:"url"`
+	}
+	if apiErr := decodeBody(body, &req); apiErr != nil {
+		return 0, nil, apiErr
+	}
+	u, err := url.Parse(req.URL)
+	if err != nil || u.Host == "" {
+		return 0, nil, errValidation("url", req.URL)
+	}
+	if hostID, err := ywm.HostIDFromURL(req.URL); err != nil || hostID != host.Host.HostID {
+		return 0, nil, &apiError{status: http.StatusBadRequest, code: "URL_DOES_NOT_BELONG_TO_HOST", message: "sitemap url does not belong to host"}
+	}
+	for _, sitemap := range host.UserSitemaps {
+		if sitemap.SitemapURL == req.URL {
+			return 0, nil, &apiError{
+				status:  http.StatusConflict,
+				code:    "SITEMAP_ALREADY_ADDED",
+				message: "sitemap already added",
+				fields:  map[string]interface{}{"sitemap_id": sitemap.SitemapID},
+			}
+		}
+	}
+	sitemap := &ywm.AddedUserSitemap{SitemapID: s.nextID("sitemap"), SitemapURL: req.URL, AddedDate: s.timestamp()}
+	host.UserSitemaps = append(host.UserSitemaps, sitemap)
+	return http.StatusCreated, ywm.AddedSitemap{SitemapID: sitemap.SitemapID}, nil
+}
+
+func (s *Server) getUserAddedSitemaps(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	start, end, apiErr := page(query, len(host.UserSitemaps), 10, 100)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.AddedUserSitemaps{
+		Sitemaps: append([]*ywm.AddedUserSitemap{}, host.UserSitemaps[start:end]...),
+		Count:    len(host.UserSitemaps),
+	}, nil
+}
+
+func (s *Server) getUserAddedSitemap(host *HostData, sitemapID string) (int, interface{}, *apiError) {
+	for _, sitemap := range host.UserSitemaps {
+		if sitemap.SitemapID == sitemapID {
+			return http.StatusOK, sitemap, nil
+		}
+	}
+	return 0, nil, errNotFound()
+}
+
+func (s *Server) deleteSitemap(host *HostData, sitemapID string) (int, interface{}, *apiError) {
+	for i, sitemap := range host.UserSitemaps {
+		if sitemap.SitemapID == sitemapID {
+			host.UserSitemaps = append(host.UserSitemaps[:i], host.UserSitemaps[i+1:]...)
+			return http.StatusNoContent, nil, nil
+		}
+	}
+	return 0, nil, errNotFound()
+}
+
+// filterIndicators returns indicators in date range
+func filterIndicators(indicators []*ywm.Indicator, r dateRange) []*ywm.Indicator {
+	result := make([]*ywm.Indicator, 0, len(indicators))
+	for _, ind := range indicators {
+		if r.contains(ind.Date.Time) {
+			result = append(result, ind)
+		}
+	}
+	return result
+}
+
+// filterQueryData returns query history in date range
+func filterQueryData(data []*ywm.SeachAllHistoryIndicatorData, r dateRange) []*ywm.SeachAllHistoryIndicatorData {
+	result := make([]*ywm.SeachAllHistoryIndicatorData, 0, len(data))
+	for _, item := range data {
+		if r.contains(item.Date.Time) {
+			result = append(result, item)
+		}
+	}
+	return result
+}
+
+func (s *Server) getIndexingHistory(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	indicators := ywm.AllIndexingIndicators
+	if values := query["indexing_indicator"]; len(values) != 0 {
+		indicators = nil
+		for _, value := range values {
+			indicators = append(indicators, ywm.IndexingIndicator(value))
+		}
+	}
+	result := ywm.IndexingHistory{Indicators: make(map[ywm.IndexingIndicator][]*ywm.Indicator)}
+	for _, indicator := range indicators {
+		result.Indicators[indicator] = filterIndicators(host.IndexingHistory[indicator], r)
+	}
+	return http.StatusOK, result, nil
+}
+
+func (s *Server) getIndexingSamples(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	samples := host.IndexingSamples
+	if values := query["indexing_indicator"]; len(values) != 0 {
+		allowed := make(map[ywm.IndexingIndicator]bool)
+		for _, value := range values {
+			allowed[ywm.IndexingIndicator(value)] = true
+		}
+		samples = nil
+		for _, sample := range host.IndexingSamples {
+			if allowed[sample.Status] {
+				samples = append(samples, sample)
+			}
+		}
+	}
+	start, end, apiErr := page(query, len(samples), 10, 100)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.SamplesResult{Count: len(samples), Samples: samples[start:end]}, nil
+}
+
+func (s *Server) getImportantURLHistory(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	u := query.Get("url")
+	history, ok := host.ImportantURLHistory[u]
+	if !ok {
+		return 0, nil, &apiError{status: http.StatusNotFound, code: "URL_NOT_FOUND", message: "url is not monitored", fields: map[string]interface{}{"url": u}}
+	}
+	return http.StatusOK, ywm.ImportantURLSHistory{History: history}, nil
+}
+
+func (s *Server) getInsearchHistory(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	result := ywm.InseacrhURLHistory{History: make([]*ywm.InsearchURLHistoryData, 0, len(host.InsearchHistory))}
+	for _, item := range host.InsearchHistory {
+		if r.contains(item.Date.Time) {
+			result.History = append(result.History, item)
+		}
+	}
+	return http.StatusOK, result, nil
+}
+
+func (s *Server) getInsearchSamples(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	start, end, apiErr := page(query, len(host.InsearchSamples), 10, 100)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.InsearchSampleResponse{Count: len(host.InsearchSamples), Samples: host.InsearchSamples[start:end]}, nil
+}
+
+func (s *Server) getEventsHistory(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	var result ywm.SearchURLEventHistoryResponse
+	result.Indicators.AppeadINSearch = filterIndicators(host.EventsHistory[ywm.SearchEventAppearedInSearch], r)
+	result.Indicators.RemovedFromSearch = filterIndicators(host.EventsHistory[ywm.SearchEventRemovedFromSearch], r)
+	return http.StatusOK, result, nil
+}
+
+func (s *Server) getEventSamples(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	start, end, apiErr := page(query, len(host.EventSamples), 10, 100)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.InsearchEventSampleResponse{Count: len(host.EventSamples), Samples: host.EventSamples[start:end]}, nil
+}
+
+func (s *Server) getRecrawlTasks(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	s.refreshTasks(host)
+	var tasks []*ywm.RecrawlTask
+	for _, task := range host.RecrawlTasks {
+		if r.contains(task.AddedTime.Time) {
+			tasks = append(tasks, task)
+		}
+	}
+	start, end, apiErr := page(query, len(tasks), 50, 100)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.RecrawlTasks{Tasks: append([]*ywm.RecrawlTask{}, tasks[start:end]...)}, nil
+}
+
+func (s *Server) recrawlURL(host *HostData, body []byte) (int, interface{}, *apiError) {
+	var req struct {
+		URL string `json:"url"`
+	}
+	if apiErr := decodeBody(body, &req); apiErr != nil {
+		return 0, nil, apiErr
+	}
+	if hostID, err := ywm.HostIDFromURL(req.URL); err != nil || hostID != host.Host.HostID {
+		return 0, nil, &apiError{status: http.StatusBadRequest, code: "URL_DOES_NOT_BELONG_TO_HOST", message: "url does not belong to host"}
+	}
+	s.refreshQuota(host)
+	if host.RecrawlQuota.QuotaRemainder <= 0 {
+		return 0, nil, &apiError{
+			status:  http.StatusTooManyRequests,
+			code:    "QUOTA_EXCEEDED",
+			message: "daily recrawl quota exceeded",
+			fields:  map[string]interface{}{"daily_quota": host.RecrawlQuota.DailyQuota},
+		}
+	}
+	host.RecrawlQuota.QuotaRemainder--
+	task := &ywm.RecrawlTask{TaskID: s.nextID("task"), URL: req.URL, AddedTime: s.timestamp(), State: ywm.RecrawlTaskStateInProgress}
+	host.RecrawlTasks = append(host.RecrawlTasks, task)
+	return http.StatusAccepted, ywm.RecrawlURLResponse{TaskID: task.TaskID, QuotaRemainder: host.RecrawlQuota.QuotaRemainder}, nil
+}
+
+func (s *Server) getRecrawlTask(host *HostData, taskID string) (int, interface{}, *apiError) {
+	s.refreshTasks(host)
+	for _, task := range host.RecrawlTasks {
+		if task.TaskID == taskID {
+			return http.StatusOK, task, nil
+		}
+	}
+	return 0, nil, &apiError{status: http.StatusNotFound, code: "TASK_NOT_FOUND", message: "recrawl task not found", fields: map[string]interface{}{"task_id": taskID}}
+}
+
+func (s *Server) getPopularQueries(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	queries := append([]*ywm.PopularSearchQuery(nil), host.PopularQueries...)
+	orderBy := ywm.QueryIndicator(query.Get("order_by"))
+	if orderBy == "" {
+		orderBy = ywm.QueryIndicatorTotalShows
+	}
+	if orderBy != ywm.QueryIndicatorTotalShows && orderBy != ywm.QueryIndicatorTotalClicks {
+		return 0, nil, errValidation("order_by", orderBy)
+	}
+	sort.SliceStable(queries, func(i, j int) bool {
+		if orderBy == ywm.QueryIndicatorTotalClicks {
+			return queries[i].Indicators.TotalClicks > queries[j].Indicators.TotalClicks
+		}
+		return queries[i].Indicators.TotalShows > queries[j].Indicators.TotalShows
+	})
+	start, end, apiErr := page(query, len(queries), 500, 500)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.PopularSeachQueryResponse{
+		Queries:  queries[start:end],
+		DateFrom: r.from,
+		DateTo:   r.to,
+		Count:    len(queries),
+	}, nil
+}
+
+// filterQueryHistory returns query history in date range
+func filterQueryHistory(history ywm.SearchAllHistoryIndicator, r dateRange) ywm.SearchAllHistoryIndicator {
+	return ywm.SearchAllHistoryIndicator{
+		TotalShows:       filterQueryData(history.TotalShows, r),
+		TotalClicks:      filterQueryData(history.TotalClicks, r),
+		AvgShowPosition:  filterQueryData(history.AvgShowPosition, r),
+		AvgClickPosition: filterQueryData(history.AvgClickPosition, r),
+	}
+}
+
+func (s *Server) getQueriesHistory(host *HostData, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	return http.StatusOK, ywm.SearchAllHistoryResponse{Indicators: filterQueryHistory(host.QueriesHistory, r)}, nil
+}
+
+func (s *Server) getQueryHistory(host *HostData, queryID string, query url.Values) (int, interface{}, *apiError) {
+	r, apiErr := parseDates(query)
+	if apiErr != nil {
+		return 0, nil, apiErr
+	}
+	history, ok := host.QueryHistory[queryID]
+	if !ok {
+		return 0, nil, &apiError{status: http.StatusNotFound, code: "QUERY_ID_NOT_FOUND", message: "query not found", fields: map[string]interface{}{"query_id": queryID}}
+	}
+	result := ywm.SearchSingleHistoryResponse{QueryID: queryID, Indicators: filterQueryHistory(history, r)}
+	for _, q := range host.PopularQueries {
+		if q.QueryID == queryID {
+			result.QueryText = q.QueryText
+		}
+	}
+	return http.StatusOK, result, nil
+}
@@ -0,0 +1,413 @@
+// Package webmastertest provides in-process fake of Yandex Webmaster API v4 for tests.
+//
+// Server keeps hosts, sitemaps, recrawl tasks and reports in memory and serves
+// every endpoint called by yandexwebmaster services:
+//
+//	srv := webmastertest.NewServer()
+//	defer srv.Close()
+//	hostID, _ := srv.AddHost("https://example.com")
+//	client, _ := srv.Client()
+//	hosts, _ := client.Hosts.GetHosts()
+//
+// GenerateDataset fills server with seeded demo data. Recorder replays recorded
+// responses of real api, FakeAPI and Fake* services implement yandexwebmaster
+// service interfaces without http.
+package webmastertest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+const (
+	DefaultToken      = "test-token"
+	DefaultUserID     = 1
+	DefaultDailyQuota = 100
+)
+
+// Server - stateful fake Webmaster API server
+type Server struct {
+	// URL - base url of api, pass to yandexwebmaster.WithBaseURL
+	URL    string
+	Token  string
+	UserID int
+	// DailyQuota - recrawl quota of added hosts
+	DailyQuota int
+	// RecrawlDuration - time after which recrawl task is done, tasks stay IN_PROGRESS if zero
+	RecrawlDuration time.Duration
+
+	srv      *httptest.Server
+	now      func() time.Time
+	mu       sync.Mutex
+	hosts    map[ywm.HostID]*HostData
+	order    []ywm.HostID
+	faults   []*Fault
+	requests []Request
+	seq      int
+}
+
+// Option - Server option
+type Option func(*Server)
+
+// WithToken - accept only token instead of DefaultToken
+func WithToken(token string) Option {
+	return func(s *Server) {
+		s.Token = token
+	}
+}
+
+// WithUserID - serve user id instead of DefaultUserID
+func WithUserID(userID int) Option {
+	return func(s *Server) {
+		s.UserID = userID
+	}
+}
+
+// WithDailyQuota - recrawl quota of added hosts instead of DefaultDailyQuota
+func WithDailyQuota(quota int) Option {
+	return func(s *Server) {
+		s.DailyQuota = quota
+	}
+}
+
+// WithRecrawlDuration - finish recrawl tasks after d
+func WithRecrawlDuration(d time.Duration) Option {
+	return func(s *Server) {
+		s.RecrawlDuration = d
+	}
+}
+
+// WithClock - use now instead of time.Now for added dates and recrawl progress
+func WithClock(now func() time.Time) Option {
+	return func(s *Server) {
+		s.now = now
+	}
+}
+
+// NewServer starts fake server with empty state
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		Token:      DefaultToken,
+		UserID:     DefaultUserID,
+		DailyQuota: DefaultDailyQuota,
+		now:        time.Now,
+		hosts:      make(map[ywm.HostID]*HostData),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.srv = httptest.NewServer(s)
+	s.URL = s.srv.URL + "/v4/"
+	return s
+}
+
+// Close shuts down server
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// Client creates api client connected to server with server token, opts are applied after connection options
+func (s *Server) Client(opts ...ywm.Option) (*ywm.Client, error) {
+	opts = append([]ywm.Option{ywm.WithBaseURL(s.URL), ywm.WithHTTPClient(s.srv.Client())}, opts...)
+	return ywm.NewClient(s.Token, opts...)
+}
+
+// Request - request received by server
+type Request struct {
+	Method string
+	// Path - path below base url
+	Path  string
+	Query url.Values
+	Body  []byte
+}
+
+// Requests returns requests received by server in order
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Fault - scripted failure of requests matching Method and Path
+type Fault struct {
+	// Method - http method, any if empty
+	Method string
+	// Path - path.Match pattern of path below base url, e.g. "user/*/hosts/*/recrawl/queue", any if empty
+	Path string
+	// Status - response status, 500 if zero
+	Status int
+	// ErrorCode - error_code of json error payload, INTERNAL_ERROR if empty
+	ErrorCode string
+	// Body - raw response body sent instead of json error payload, e.g. html page
+	Body string
+	// Header - additional response headers, e.g. Retry-After
+	Header http.Header
+	// Delay - pause before response
+	Delay time.Duration
+	// Times - number of matching requests to fail, every matching request if zero
+	Times int
+
+	hits int
+}
+
+// InjectFault adds fault, faults are matched in order of injection
+func (s *Server) InjectFault(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all faults
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// matchFault returns first active fault matching request
+func (s *Server) matchFault(method string, p string) *Fault {
+	for _, f := range s.faults {
+		if f.Times > 0 && f.hits >= f.Times {
+			continue
+		}
+		if f.Method != "" && f.Method != method {
+			continue
+		}
+		if f.Path != "" {
+			if ok, _ := path.Match(f.Path, p); !ok {
+				continue
+			}
+		}
+		f.hits++
+		return f
+	}
+	return nil
+}
+
+// writeFault writes fault response
+func writeFault(w http.ResponseWriter, f *Fault) {
+	if f.Delay > 0 {
+		time.Sleep(f.Delay)
+	}
+	for key, values := range f.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	status := f.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	if f.Body != "" {
+		w.WriteHeader(status)
+		w.Write([]byte(f.Body))
+		return
+	}
+	code := f.ErrorCode
+	if code == "" {
+		code = "INTERNAL_ERROR"
+	}
+	writeError(w, status, code, "injected fault", nil)
+}
+
+// apiError - error payload of api
+type apiError struct {
+	status  int
+	code    string
+	message string
+	fields  map[string]interface{}
+}
+
+// writeError writes json error payload in api format
+func writeError(w http.ResponseWriter, status int, code string, message string, fields map[string]interface{}) {
+	payload := map[string]interface{}{"error_code": code, "error_message": message}
+	for key, value := range fields {
+		payload[key] = value
+	}
+	writeJSON(w, status, payload)
+}
+
+func writeJSON(w http.ResponseWriter, status int, value interface{}) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "INTERNAL_ERROR", err.Error(), nil)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "INVALID_BODY", err.Error(), nil)
+		return
+	}
+	p := strings.TrimPrefix(r.URL.EscapedPath(), "/v4/")
+
+	s.mu.Lock()
+	s.requests = append(s.requests, Request{Method: r.Method, Path: p, Query: r.URL.Query(), Body: body})
+	fault := s.matchFault(r.Method, p)
+	s.mu.Unlock()
+	if fault != nil {
+		writeFault(w, fault)
+		return
+	}
+
+	if r.Header.Get("Authorization") != "OAuth "+s.Token {
+		writeError(w, http.StatusUnauthorized, "INVALID_OAUTH_TOKEN", "invalid oauth token", nil)
+		return
+	}
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		unescaped, err := url.PathUnescape(segment)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "INVALID_URL", err.Error(), nil)
+			return
+		}
+		segments[i] = unescaped
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status, result, apiErr := s.route(r.Method, segments, r.URL.Query(), body)
+	if apiErr != nil {
+		writeError(w, apiErr.status, apiErr.code, apiErr.message, apiErr.fields)
+		return
+	}
+	if result == nil {
+		w.WriteHeader(status)
+		return
+	}
+	writeJSON(w, status, result)
+}
+
+// route dispatches request by path segments below base url
+func (s *Server) route(method string, segments []string, query url.Values, body []byte) (int, interface{}, *apiError) {
+	if len(segments) == 1 && segments[0] == "user" {
+		if method != http.MethodGet {
+			return 0, nil, errMethodNotAllowed()
+		}
+		return http.StatusOK, ywm.User{UserID: s.UserID}, nil
+	}
+	if len(segments) < 3 || segments[0] != "user" || segments[2] != "hosts" {
+		return 0, nil, errNotFound()
+	}
+	if segments[1] != fmt.Sprint(s.UserID) {
+		return 0, nil, &apiError{status: http.StatusForbidden, code: "ACCESS_FORBIDDEN", message: "access to user is forbidden"}
+	}
+	if len(segments) == 3 {
+		switch method {
+		case http.MethodGet:
+			return s.getHosts()
+		case http.MethodPost:
+			return s.addHost(body)
+		}
+		return 0, nil, errMethodNotAllowed()
+	}
+	hostID, err := ywm.ParseHostID(segments[3])
+	if err != nil {
+		return 0, nil, errValidation("host-id", segments[3])
+	}
+	host, ok := s.hosts[hostID]
+	if !ok {
+		return 0, nil, &apiError{status: http.StatusNotFound, code: "HOST_NOT_FOUND", message: "host not found", fields: map[string]interface{}{"host_id": hostID}}
+	}
+	rest := segments[4:]
+	if len(rest) == 0 {
+		switch method {
+		case http.MethodGet:
+			return http.StatusOK, host.Host, nil
+		case http.MethodDelete:
+			s.deleteHost(hostID)
+			return http.StatusNoContent, nil, nil
+		}
+		return 0, nil, errMethodNotAllowed()
+	}
+	if !host.Host.Verified {
+		return 0, nil, &apiError{status: http.StatusForbidden, code: "HOST_NOT_VERIFIED", message: "host is not verified by user", fields: map[string]interface{}{"host_id": hostID}}
+	}
+	return s.routeHost(host, method, rest, query, body)
+}
+
+// routeHost dispatches request to host resources
+func (s *Server) routeHost(host *HostData, method string, rest []string, query url.Values, body []byte) (int, interface{}, *apiError) {
+	resource := strings.Join(rest, "/")
+	get := method == http.MethodGet
+	switch {
+	case resource == "sitemaps" && get:
+		return s.getSitemaps(host, query)
+	case rest[0] == "sitemaps" && len(rest) == 2 && get:
+		return s.getSitemap(host, rest[1])
+	case resource == "user-added-sitemaps" && get:
+		return s.getUserAddedSitemaps(host, query)
+	case resource == "user-added-sitemaps" && method == http.MethodPost:
+		return s.addSitemap(host, body)
+	case rest[0] == "user-added-sitemaps" && len(rest) == 2 && get:
+		return s.getUserAddedSitemap(host, rest[1])
+	case rest[0] == "user-added-sitemaps" && len(rest) == 2 && method == http.MethodDelete:
+		return s.deleteSitemap(host, rest[1])
+	case resource == "indexing/history" && get:
+		return s.getIndexingHistory(host, query)
+	case resource == "indexing/samples" && get:
+		return s.getIndexingSamples(host, query)
+	case resource == "important-urls" && get:
+		return http.StatusOK, ywm.ImportantURLS{URLS: host.ImportantURLs}, nil
+	case resource == "important-urls/history" && get:
+		return s.getImportantURLHistory(host, query)
+	case resource == "search-urls/in-search/history" && get:
+		return s.getInsearchHistory(host, query)
+	case resource == "search-urls/in-search/samples" && get:
+		return s.getInsearchSamples(host, query)
+	case resource == "search-urls/events/history" && get:
+		return s.getEventsHistory(host, query)
+	case resource == "search-urls/events/samples" && get:
+		return s.getEventSamples(host, query)
+	case resource == "recrawl/queue" && get:
+		return s.getRecrawlTasks(host, query)
+	case resource == "recrawl/queue" && method == http.MethodPost:
+		return s.recrawlURL(host, body)
+	case rest[0] == "recrawl" && len(rest) == 3 && rest[1] == "queue" && get:
+		return s.getRecrawlTask(host, rest[2])
+	case resource == "recrawl/quota" && get:
+		s.refreshQuota(host)
+		return http.StatusOK, host.RecrawlQuota, nil
+	case resource == "search-queries/popular" && get:
+		return s.getPopularQueries(host, query)
+	case resource == "search-queries/all/history" && get:
+		return s.getQueriesHistory(host, query)
+	case rest[0] == "search-queries" && len(rest) == 3 && rest[2] == "history" && get:
+		return s.getQueryHistory(host, rest[1], query)
+	case resource == "diagnostics" && get:
+		return http.StatusOK, ywm.DiagnosticProblemsResponse{Problems: host.Diagnostics}, nil
+	}
+	return 0, nil, errNotFound()
+}
+
+func errNotFound() *apiError {
+	return &apiError{status: http.StatusNotFound, code: "RESOURCE_NOT_FOUND", message: "resource not found"}
+}
+
+func errMethodNotAllowed() *apiError {
+	return &apiError{status: http.StatusMethodNotAllowed, code: "METHOD_NOT_ALLOWED", message: "method not allowed"}
+}
+
+func errValidation(field string, value interface{}) *apiError {
+	return &apiError{
+		status:  http.StatusBadRequest,
+		code:    "FIELD_VALIDATION_ERROR",
+		message: fmt.Sprintf("invalid value of %s", field),
+		fields:  map[string]interface{}{"field_name": field, "field_value": value},
+	}
+}
@@ -0,0 +1,225 @@
+package webmastertest
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// do sends raw request to server and returns status and error_code of response
+func do(t *testing.T, s *Server, method string, path string, body string) (int, string) {
+	t.Helper()
+	req, err := http.NewRequest(method, s.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "OAuth "+s.Token)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	data, _ := io.ReadAll(resp.Body)
+	var payload struct {
+		ErrorCode string `json:"error_code"`
+	}
+	json.Unmarshal(data, &payload)
+	return resp.StatusCode, payload.ErrorCode
+}
+
+func TestServerRoutes(t *testing.T) {
+	s := NewServer(WithDailyQuota(1))
+	defer s.Close()
+	hostID, err := s.AddHost("https://example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddHost("https://example.com/"); err == nil {
+		t.Error("AddHost() of existing host succeeded")
+	}
+	host := "user/1/hosts/" + string(hostID)
+	tests := []struct {
+		name     string
+		method   string
+		path     string
+		body     string
+		status   int
+		wantCode string
+	}{
+		{name: "user", method: http.MethodGet, path: "user", status: http.StatusOK},
+		{name: "hosts", method: http.MethodGet, path: "user/1/hosts", status: http.StatusOK},
+		{name: "other user", method: http.MethodGet, path: "user/2/hosts", status: http.StatusForbidden, wantCode: "ACCESS_FORBIDDEN"},
+		{name: "add host", method: http.MethodPost, path: "user/1/hosts", body: `{"host_url":"https://new.example.com"}`, status: http.StatusCreated},
+		{name: "add existing host", method: http.MethodPost, path: "user/1/hosts", body: `{"host_url":"https://example.com"}`, status: http.StatusConflict, wantCode: "HOST_ALREADY_ADDED"},
+		{name: "add invalid json", method: http.MethodPost, path: "user/1/hosts", body: `{`, status: http.StatusBadRequest, wantCode: "INVALID_JSON"},
+		{name: "unverified host resource", method: http.MethodGet, path: "user/1/hosts/https:new.example.com:443/sitemaps", status: http.StatusForbidden, wantCode: "HOST_NOT_VERIFIED"},
+		{name: "unknown host", method: http.MethodGet, path: "user/1/hosts/https:missing.com:443", status: http.StatusNotFound, wantCode: "HOST_NOT_FOUND"},
+		{name: "invalid host id", method: http.MethodGet, path: "user/1/hosts/example.com", status: http.StatusBadRequest, wantCode: "FIELD_VALIDATION_ERROR"},
+		{name: "unknown resource", method: http.MethodGet, path: host + "/unknown", status: http.StatusNotFound, wantCode: "RESOURCE_NOT_FOUND"},
+		{name: "method not allowed", method: http.MethodPut, path: host, status: http.StatusMethodNotAllowed, wantCode: "METHOD_NOT_ALLOWED"},
+		{name: "limit over max", method: http.MethodGet, path: host + "/search-queries/popular?limit=501", status: http.StatusBadRequest, wantCode: "FIELD_VALIDATION_ERROR"},
+		{name: "reversed dates", method: http.MethodGet, path: host + "/indexing/history?date_from=2026-09-02&date_to=2026-09-01", status: http.StatusBadRequest, wantCode: "FIELD_VALIDATION_ERROR"},
+		{name: "order by position", method: http.MethodGet, path: host + "/search-queries/popular?order_by=AVG_SHOW_POSITION", status: http.StatusBadRequest, wantCode: "FIELD_VALIDATION_ERROR"},
+		{name: "recrawl foreign url", method: http.MethodPost, path: host + "/recrawl/queue", body: `{"url":"https://other.com/"}`, status: http.StatusBadRequest, wantCode: "URL_DOES_NOT_BELONG_TO_HOST"},
+		{name: "recrawl", method: http.MethodPost, path: host + "/recrawl/queue", body: `{"url":"https://example.com/a"}`, status: http.StatusAccepted},
+		{name: "recrawl over quota", method: http.MethodPost, path: host + "/recrawl/queue", body: `{"url":"https://example.com/b"}`, status: http.StatusTooManyRequests, wantCode: "QUOTA_EXCEEDED"},
+		{name: "unknown task", method: http.MethodGet, path: host + "/recrawl/queue/nope", status: http.StatusNotFound, wantCode: "TASK_NOT_FOUND"},
+		{name: "delete host", method: http.MethodDelete, path: "user/1/hosts/https:new.example.com:443", status: http.StatusNoContent},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, code := do(t, s, tt.method, tt.path, tt.body)
+			if status != tt.status || code != tt.wantCode {
+				t.Errorf("%s %s = %d %q, want %d %q", tt.method, tt.path, status, code, tt.status, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestServerAuth(t *testing.T) {
+	s := NewServer(WithToken("secret"))
+	defer s.Close()
+	if _, err := ywm.NewClient("wrong", ywm.WithBaseURL(s.URL)); err == nil {
+		t.Fatal("NewClient() with wrong token succeeded")
+	}
+	var apiErr *ywm.YandexWebmasterError
+	_, err := ywm.NewClient("wrong", ywm.WithBaseURL(s.URL))
+	if !errors.As(err, &apiErr) || apiErr.HTTPCode != http.StatusUnauthorized {
+		t.Errorf("NewClient() error = %v", err)
+	}
+	if _, err := s.Client(); err != nil {
+		t.Errorf("Client() error = %v", err)
+	}
+}
+
+func TestServerFaults(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	s.InjectFault(Fault{Method: http.MethodGet, Path: "user/*/hosts/*/recrawl/quota", Status: http.StatusServiceUnavailable, Times: 1})
+	s.InjectFault(Fault{Path: "user/*/hosts/*/diagnostics", Body: "<html>bad gateway</html>", Status: http.StatusBadGateway})
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name   string
+		call   func() error
+		status int
+	}{
+		{name: "first quota request fails", call: func() error { _, err := client.Recrawl.GetRecrawlQuota(hostID); return err }, status: http.StatusServiceUnavailable},
+		{name: "fault is used up", call: func() error { _, err := client.Recrawl.GetRecrawlQuota(hostID); return err }},
+		{name: "raw body fault", call: func() error { _, err := client.Diagnostic.GetDiagnositcs(hostID); return err }, status: http.StatusBadGateway},
+		{name: "other paths are served", call: func() error { _, err := client.Hosts.GetHost(hostID); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.call()
+			if tt.status == 0 {
+				if err != nil {
+					t.Fatalf("error = %v", err)
+				}
+				return
+			}
+			var apiErr *ywm.YandexWebmasterError
+			if !errors.As(err, &apiErr) || apiErr.HTTPCode != tt.status {
+				t.Errorf("error = %v, want status %d", err, tt.status)
+			}
+		})
+	}
+	s.ClearFaults()
+	if _, err := client.Diagnostic.GetDiagnositcs(hostID); err != nil {
+		t.Errorf("after ClearFaults error = %v", err)
+	}
+}
+
+func TestServerRecrawlProgress(t *testing.T) {
+	now := time.Date(2026, 9, 1, 10, 0, 0, 0, time.UTC)
+	s := NewServer(WithRecrawlDuration(time.Minute), WithClock(func() time.Time { return now }))
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Recrawl.RecrawlURL(hostID, "https://example.com/page")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.QuotaRemainder != DefaultDailyQuota-1 {
+		t.Errorf("QuotaRemainder = %d", resp.QuotaRemainder)
+	}
+	tests := []struct {
+		after time.Duration
+		want  ywm.RecrawlTaskState
+	}{
+		{after: 0, want: ywm.RecrawlTaskStateInProgress},
+		{after: 59 * time.Second, want: ywm.RecrawlTaskStateInProgress},
+		{after: time.Minute, want: ywm.RecrawlTaskStateDone},
+	}
+	start := now
+	for _, tt := range tests {
+		now = start.Add(tt.after)
+		task, err := client.Recrawl.GetRecrawlTask(hostID, resp.TaskID)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if task.State != tt.want {
+			t.Errorf("state after %s = %s, want %s", tt.after, task.State, tt.want)
+		}
+	}
+	now = start.AddDate(0, 0, 1)
+	quota, err := client.Recrawl.GetRecrawlQuota(hostID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quota.QuotaRemainder != DefaultDailyQuota {
+		t.Errorf("quota next day = %d, want %d", quota.QuotaRemainder, DefaultDailyQuota)
+	}
+}
+
+func TestServerPopularQueriesPaging(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	hostID, _ := s.AddHost("https://example.com")
+	s.UpdateHost(hostID, func(h *HostData) {
+		for i := 0; i < 5; i++ {
+			h.PopularQueries = append(h.PopularQueries, &ywm.PopularSearchQuery{
+				QueryID:    string(rune('a' + i)),
+				Indicators: ywm.SearchIndicator{TotalShows: float64(i), TotalClicks: float64(5 - i)},
+			})
+		}
+	})
+	client, err := s.Client()
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		req  ywm.PopularQueriesRequest
+		want string
+	}{
+		{req: ywm.PopularQueriesRequest{}, want: "edcba"},
+		{req: ywm.PopularQueriesRequest{OrderBy: ywm.QueryIndicatorTotalClicks, Limit: 2}, want: "ab"},
+		{req: ywm.PopularQueriesRequest{Limit: 2, Offset: 4}, want: "a"},
+		{req: ywm.PopularQueriesRequest{Offset: 10}, want: ""},
+	}
+	for _, tt := range tests {
+		resp, err := client.SearchQuery.GetPopularSearchQueries(hostID, tt.req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := ""
+		for _, q := range resp.Queries {
+			got += q.QueryID
+		}
+		if got != tt.want || resp.Count != 5 {
+			t.Errorf("GetPopularSearchQueries(%+v) = %q count %d, want %q", tt.req, got, resp.Count, tt.want)
+		}
+	}
+}
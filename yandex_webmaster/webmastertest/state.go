@@ -0,0 +1,175 @@
+package webmastertest
+
+import (
+	"fmt"
+	"time"
+
+	ywm "github.com/bzdvdn/yandex-webmaster-go/yandex_webmaster"
+)
+
+// SitemapEntry - sitemap found by robot with its parent sitemap index
+type SitemapEntry struct {
+	// ParentID - id of sitemap index, empty for top level sitemaps
+	ParentID string
+	Sitemap  *ywm.Sitemap
+}
+
+// HostData - state of fake host, reports are filtered and paged by server on every request
+type HostData struct {
+	Host                ywm.Host
+	Sitemaps            []*SitemapEntry
+	UserSitemaps        []*ywm.AddedUserSitemap
+	RecrawlTasks        []*ywm.RecrawlTask
+	RecrawlQuota        ywm.RecrawlQuota
+	IndexingHistory     map[ywm.IndexingIndicator][]*ywm.Indicator
+	IndexingSamples     []*ywm.Sample
+	ImportantURLs       []*ywm.ImportantURL
+	ImportantURLHistory map[string][]*ywm.ImportantURL
+	InsearchHistory     []*ywm.InsearchURLHistoryData
+	InsearchSamples     []*ywm.InsearchSample
+	EventsHistory       map[ywm.SearchEvent][]*ywm.Indicator
+	EventSamples        []*ywm.InsearchEventSample
+	PopularQueries      []*ywm.PopularSearchQuery
+	QueriesHistory      ywm.SearchAllHistoryIndicator
+	// QueryHistory - history of single queries by query id
+	QueryHistory map[string]ywm.SearchAllHistoryIndicator
+	Diagnostics  ywm.DiagnosticProblems
+	// quotaDay - day of last quota reset
+	quotaDay ywm.Date
+}
+
+// newHost creates state of host with hostURL
+func (s *Server) newHost(hostURL string, verified bool) (*HostData, error) {
+	hostID, err := ywm.HostIDFromURL(hostURL)
+	if err != nil {
+		return nil, err
+	}
+	host := &HostData{
+		Host: ywm.Host{
+			HostID:         hostID,
+			AsciiHostURL:   hostID.ASCIIURL(),
+			UnicodeHostURL: hostID.UnicodeURL(),
+			Verified:       verified,
+			HostDataStatus: "NOT_LOADED",
+		},
+		RecrawlQuota: ywm.RecrawlQuota{DailyQuota: s.DailyQuota, QuotaRemainder: s.DailyQuota},
+		quotaDay:     s.today(),
+	}
+	if verified {
+		host.Host.HostDataStatus = "OK"
+	}
+	return host, nil
+}
+
+// today returns current date in api reporting location
+func (s *Server) today() ywm.Date {
+	return ywm.DateIn(s.now(), ywm.DefaultLocation)
+}
+
+// timestamp returns current time in api reporting location
+func (s *Server) timestamp() ywm.Timestamp {
+	return ywm.NewTimestamp(s.now().In(ywm.DefaultLocation))
+}
+
+// nextID returns unique id with prefix
+func (s *Server) nextID(prefix string) string {
+	s.seq++
+	return fmt.Sprintf("%s-%d", prefix, s.seq)
+}
+
+// AddHost adds verified host with url and returns its id
+func (s *Server) AddHost(hostURL string) (ywm.HostID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	host, err := s.newHost(hostURL, true)
+	if err != nil {
+		return "", err
+	}
+	hostID := host.Host.HostID
+	if _, ok := s.hosts[hostID]; ok {
+		return "", fmt.Errorf("host %s already added", hostID)
+	}
+	s.hosts[hostID] = host
+	s.order = append(s.order, hostID)
+	return hostID, nil
+}
+
+// UpdateHost calls fn with state of host under server lock
+func (s *Server) UpdateHost(hostID ywm.HostID, fn func(h *HostData)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	host, ok := s.hosts[hostID]
+	if !ok {
+		return fmt.Errorf("host %s not found", hostID)
+	}
+	fn(host)
+	return nil
+}
+
+// Host returns copy of host state
+func (s *Server) Host(hostID ywm.HostID) (HostData, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	host, ok := s.hosts[hostID]
+	if !ok {
+		return HostData{}, false
+	}
+	s.refreshQuota(host)
+	s.refreshTasks(host)
+	return *host, true
+}
+
+// HostIDs returns ids of hosts in order of adding
+func (s *Server) HostIDs() []ywm.HostID {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]ywm.HostID(nil), s.order...)
+}
+
+func (s *Server) deleteHost(hostID ywm.HostID) {
+	delete(s.hosts, hostID)
+	for i, id := range s.order {
+		if id == hostID {
+			s.order = append(s.order[:i], s.order[i+1:]...)
+			break
+		}
+	}
+}
+
+// refreshQuota restores recrawl quota on new day
+func (s *Server) refreshQuota(host *HostData) {
+	if today := s.today(); today.After(host.quotaDay.Time) {
+		host.quotaDay = today
+		host.RecrawlQuota.QuotaRemainder = host.RecrawlQuota.DailyQuota
+	}
+}
+
+// refreshTasks finishes recrawl tasks older than RecrawlDuration
+func (s *Server) refreshTasks(host *HostData) {
+	if s.RecrawlDuration <= 0 {
+		return
+	}
+	now := s.now()
+	for _, task := range host.RecrawlTasks {
+		if task.State == ywm.RecrawlTaskStateInProgress && now.Sub(task.AddedTime.Time) >= s.RecrawlDuration {
+			task.State = ywm.RecrawlTaskStateDone
+		}
+	}
+}
+
+// dateRange - inclusive date filter of history requests, zero dates are open bounds
+type dateRange struct {
+	from ywm.Date
+	to   ywm.Date
+}
+
+func (r dateRange) contains(t time.Time) bool {
+	date := ywm.DateIn(t, ywm.DefaultLocation)
+	if !r.from.IsZero() && date.Before(r.from.Time) {
+		return false
+	}
+	if !r.to.IsZero() && date.After(r.to.Time) {
+		return false
+	}
+	return true
+}